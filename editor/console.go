@@ -1,26 +1,265 @@
 package editor
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 )
 
-var consoleChannel = make(chan string, 10)
-var consoleChannelUsed = false
+// LogBufferSize is a number of the latest log entries kept in memory (see GetLogEntries)
+const LogBufferSize = 500
 
-func GetConsoleChannel() <-chan string {
-	if !consoleChannelUsed {
-		consoleChannelUsed = true
+// MaxLogFileSize is a size of the log file after which it's moved to a file with ".old" suffix on start (see SetLogFile)
+const MaxLogFileSize = 5 << 20
+
+// LogFile is a file log entries are appended to, set by InitLogging. Empty value disables file output
+var LogFile = defaultLogFile()
+
+// LogEntry is a log record kept in memory and sent to subscribers (see SubscribeLog)
+type LogEntry struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Message string    `json:"message"`
+	// Fields are structured attributes of the record, groups are joined with dots (e.g. "file.path")
+	Fields map[string]string `json:"fields"`
+}
+
+// String returns the entry in log line format: time, level, message and sorted fields as key=value
+func (e LogEntry) String() string {
+	return e.Time.Format("2006/01/02 15:04:05") + " " + e.Level + " " + e.getText()
+}
+
+// getText returns the message with fields, without time and level
+func (e LogEntry) getText() string {
+	text := e.Message
+	for _, key := range SortKeys(e.Fields) {
+		value := e.Fields[key]
+		if strings.ContainsAny(value, " \t\"=") || value == "" {
+			value = fmt.Sprintf("%q", value)
+		}
+		text += " " + key + "=" + value
 	}
+	return text
+}
 
-	return consoleChannel
+// logCore is a log state shared by all handlers derived from Logger
+type logCore struct {
+	mu    sync.Mutex
+	level slog.LevelVar
+	// entries is a ring buffer, next is an index of the oldest entry when it's full
+	entries     []LogEntry
+	next        int
+	subscribers map[chan LogEntry]bool
+	output      io.Writer
+	file        *os.File
+	// console is a channel of GetConsoleChannel, entries are sent to it after the first call
+	console     chan string
+	consoleUsed bool
 }
 
-func ConsoleWrite(line string, p ...any) {
-	line = fmt.Sprintf(line+"\n", p...)
-	if consoleChannelUsed {
-		consoleChannel <- line
+var logs = &logCore{output: os.Stderr, subscribers: make(map[chan LogEntry]bool), console: make(chan string, 10)}
+
+// Logger is a structured logger of the application. Entries are written to stderr and LogFile,
+// kept in memory and sent to subscribers without blocking, so logging never waits for readers
+var Logger = slog.New(&logHandler{core: logs})
+
+// logHandler is slog handler writing to logCore, attrs are already prefixed with groups
+type logHandler struct {
+	core   *logCore
+	attrs  []slog.Attr
+	prefix string
+}
+
+func (h *logHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.core.level.Level()
+}
+
+func (h *logHandler) Handle(_ context.Context, record slog.Record) error {
+	entry := LogEntry{Time: record.Time, Level: record.Level.String(), Message: record.Message}
+	if record.NumAttrs() > 0 || len(h.attrs) > 0 {
+		entry.Fields = make(map[string]string)
+	}
+	for _, attr := range h.attrs {
+		addLogField(entry.Fields, "", attr)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		addLogField(entry.Fields, h.prefix, attr)
+		return true
+	})
+
+	h.core.write(entry)
+	return nil
+}
+
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handler := &logHandler{core: h.core, prefix: h.prefix, attrs: append([]slog.Attr{}, h.attrs...)}
+	for _, attr := range attrs {
+		handler.attrs = append(handler.attrs, slog.Attr{Key: h.prefix + attr.Key, Value: attr.Value})
+	}
+	return handler
+}
+
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &logHandler{core: h.core, attrs: h.attrs, prefix: h.prefix + name + "."}
+}
+
+// addLogField adds the attribute to fields, groups are flattened with dots
+func addLogField(fields map[string]string, prefix string, attr slog.Attr) {
+	value := attr.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, inner := range value.Group() {
+			addLogField(fields, prefix, inner)
+		}
+		return
+	}
+	if attr.Key != "" {
+		fields[prefix+attr.Key] = value.String()
+	}
+}
+
+// write saves the entry to the ring buffer and outputs, then sends it to subscribers if they have room for it
+func (c *logCore) write(entry LogEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) < LogBufferSize {
+		c.entries = append(c.entries, entry)
+	} else {
+		c.entries[c.next] = entry
+		c.next = (c.next + 1) % LogBufferSize
+	}
+
+	line := entry.String() + "\n"
+	_, _ = io.WriteString(c.output, line)
+	if c.file != nil {
+		_, _ = c.file.WriteString(line)
 	}
 
-	log.Print(line)
+	for subscriber := range c.subscribers {
+		select {
+		case subscriber <- entry:
+		default:
+		}
+	}
+	if c.consoleUsed {
+		select {
+		case c.console <- entry.getText() + "\n":
+		default:
+		}
+	}
+}
+
+// defaultLogFile returns a log file inside user config directory, or empty string if it's unknown
+func defaultLogFile() string {
+	if dir := defaultStudioDir("logs"); dir != "" {
+		return filepath.Join(dir, "civ4-studio.log")
+	}
+	return ""
+}
+
+// InitLogging sets verbosity (debug entries are shown in dev mode only) and starts writing to LogFile
+func InitLogging(devMode bool) {
+	level := slog.LevelInfo
+	if devMode {
+		level = slog.LevelDebug
+	}
+	SetLogLevel(level)
+
+	if err := SetLogFile(LogFile); err != nil {
+		Logger.Warn("Cannot open log file", "path", LogFile, "error", err)
+	}
+}
+
+// SetLogLevel sets the minimal level of written entries
+func SetLogLevel(level slog.Level) {
+	logs.level.Set(level)
+}
+
+// SetLogFile starts appending entries to the file, empty path stops file output.
+// The file exceeding MaxLogFileSize is moved to a file with ".old" suffix first
+func SetLogFile(path string) error {
+	var file *os.File
+	if path != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if stat, err := os.Stat(path); err == nil && stat.Size() > MaxLogFileSize {
+			_ = os.Rename(path, path+".old")
+		}
+
+		var err error
+		file, err = os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	logs.mu.Lock()
+	defer logs.mu.Unlock()
+	if logs.file != nil {
+		_ = logs.file.Close()
+	}
+	logs.file = file
+	return nil
+}
+
+// GetLogEntries returns entries kept in memory with the level or higher, the oldest first
+func GetLogEntries(level slog.Level) []LogEntry {
+	logs.mu.Lock()
+	defer logs.mu.Unlock()
+
+	entries := make([]LogEntry, 0, len(logs.entries))
+	for i := range logs.entries {
+		entry := logs.entries[(logs.next+i)%len(logs.entries)]
+		var entryLevel slog.Level
+		if entryLevel.UnmarshalText([]byte(entry.Level)) == nil && entryLevel >= level {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// SubscribeLog returns a channel receiving new entries. Entries are dropped if the channel buffer is full,
+// so a slow subscriber never blocks logging. The returned function unsubscribes and closes the channel
+func SubscribeLog(buffer int) (<-chan LogEntry, func()) {
+	ch := make(chan LogEntry, buffer)
+	logs.mu.Lock()
+	logs.subscribers[ch] = true
+	logs.mu.Unlock()
+
+	once := sync.Once{}
+	return ch, func() {
+		once.Do(func() {
+			logs.mu.Lock()
+			delete(logs.subscribers, ch)
+			logs.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// GetConsoleChannel returns a channel of log lines (message with fields), kept for compatibility with the old console.
+// Lines are dropped if nobody reads the channel, use SubscribeLog for structured entries
+func GetConsoleChannel() <-chan string {
+	logs.mu.Lock()
+	defer logs.mu.Unlock()
+	logs.consoleUsed = true
+	return logs.console
+}
+
+// ConsoleWrite formats the line like fmt.Sprintf and logs it with info level, prefer Logger with fields for new code
+func ConsoleWrite(line string, p ...any) {
+	Logger.Info(fmt.Sprintf(line, p...))
 }
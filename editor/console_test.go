@@ -1,6 +1,16 @@
 package editor
 
-import "testing"
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestConsoleWrite(t *testing.T) {
 	ch := GetConsoleChannel()
@@ -15,3 +25,144 @@ func TestConsoleWrite(t *testing.T) {
 		t.Fatal("Expected message on channel")
 	}
 }
+
+// resetLogs replaces the log state for the test and restores it after
+func resetLogs(t *testing.T) {
+	logs.mu.Lock()
+	entries, next, output := logs.entries, logs.next, logs.output
+	logs.entries, logs.next, logs.output = nil, 0, io.Discard
+	logs.mu.Unlock()
+	level := logs.level.Level()
+
+	t.Cleanup(func() {
+		_ = SetLogFile("")
+		logs.mu.Lock()
+		logs.entries, logs.next, logs.output = entries, next, output
+		logs.mu.Unlock()
+		SetLogLevel(level)
+	})
+}
+
+func TestLoggerFields(t *testing.T) {
+	resetLogs(t)
+
+	Logger.With("file", "a b.xml").WithGroup("map").Info("Parsed", "width", 10, slog.Group("size", "x", 1))
+	entries := GetLogEntries(slog.LevelDebug)
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+
+	entry := entries[0]
+	expected := map[string]string{"file": "a b.xml", "map.width": "10", "map.size.x": "1"}
+	if entry.Level != "INFO" || entry.Message != "Parsed" || !maps.Equal(entry.Fields, expected) {
+		t.Fatalf("Unexpected entry %+v", entry)
+	}
+	if text := entry.getText(); text != `Parsed file="a b.xml" map.size.x=1 map.width=10` {
+		t.Fatalf("Unexpected text %s", text)
+	}
+}
+
+func TestLogLevels(t *testing.T) {
+	resetLogs(t)
+
+	SetLogLevel(slog.LevelInfo)
+	Logger.Debug("hidden")
+	Logger.Info("info")
+	Logger.Warn("warn")
+	if entries := GetLogEntries(slog.LevelDebug); len(entries) != 2 {
+		t.Fatalf("Expected debug entry to be skipped, got %v", entries)
+	}
+	if entries := GetLogEntries(slog.LevelWarn); len(entries) != 1 || entries[0].Message != "warn" {
+		t.Fatalf("Expected only warning, got %v", entries)
+	}
+
+	SetLogLevel(slog.LevelDebug)
+	Logger.Debug("shown")
+	if entries := GetLogEntries(slog.LevelDebug); len(entries) != 3 {
+		t.Fatalf("Expected debug entry in dev mode, got %v", entries)
+	}
+}
+
+func TestLogRingBuffer(t *testing.T) {
+	resetLogs(t)
+
+	for i := 0; i < LogBufferSize+10; i++ {
+		ConsoleWrite("entry %d", i)
+	}
+	entries := GetLogEntries(slog.LevelDebug)
+	if len(entries) != LogBufferSize {
+		t.Fatalf("Expected %d entries, got %d", LogBufferSize, len(entries))
+	}
+	if entries[0].Message != "entry 10" || entries[len(entries)-1].Message != fmt.Sprintf("entry %d", LogBufferSize+9) {
+		t.Fatalf("Unexpected order: %s ... %s", entries[0].Message, entries[len(entries)-1].Message)
+	}
+}
+
+func TestSubscribeLog(t *testing.T) {
+	resetLogs(t)
+
+	fast, unsubscribeFast := SubscribeLog(10)
+	defer unsubscribeFast()
+	slow, unsubscribeSlow := SubscribeLog(1)
+
+	// The slow subscriber never reads, logging must not wait for it
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 5; i++ {
+			Logger.Info("message", "i", i)
+		}
+		done <- true
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Logging is blocked by slow subscriber")
+	}
+
+	if len(fast) != 5 || len(slow) != 1 {
+		t.Fatalf("Expected 5 and 1 buffered entries, got %d and %d", len(fast), len(slow))
+	}
+	if entry := <-fast; entry.Fields["i"] != "0" {
+		t.Fatalf("Unexpected first entry %+v", entry)
+	}
+
+	unsubscribeSlow()
+	unsubscribeSlow()
+	Logger.Info("after")
+	<-slow
+	if _, ok := <-slow; ok {
+		t.Fatal("Expected channel to be closed after unsubscribe")
+	}
+}
+
+func TestSetLogFile(t *testing.T) {
+	resetLogs(t)
+
+	path := filepath.Join(t.TempDir(), "logs", "test.log")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, make([]byte, MaxLogFileSize+1), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetLogFile(path); err != nil {
+		t.Fatal(err)
+	}
+	Logger.Warn("Backup failed", "error", "denied")
+	if err := SetLogFile(""); err != nil {
+		t.Fatal(err)
+	}
+	Logger.Info("not written")
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(string(content), " WARN Backup failed error=denied\n") || strings.Contains(string(content), "not written") {
+		t.Fatalf("Unexpected log file content: %q", content)
+	}
+	if stat, err := os.Stat(path + ".old"); err != nil || stat.Size() != MaxLogFileSize+1 {
+		t.Fatalf("Expected old log file to be rotated, got %v", err)
+	}
+}
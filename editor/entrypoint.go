@@ -7,7 +7,7 @@ import (
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/options/assetserver"
-	"log"
+	"os"
 )
 
 var assets embed.FS
@@ -15,11 +15,11 @@ var assets embed.FS
 var Application fyne.App
 var GlobalConfig *Config
 
-const steamDefaultGameDir = "C:\\Program Files (x86)\\Steam\\steamapps\\common\\Sid Meier's Civilization IV Beyond the Sword\\Beyond the Sword"
-
 var (
 	RunGame = flag.Bool("run-game", false, "Run game with current mod instead of editor")
+	Profile = flag.String("profile", "", "Launch profile used by -run-game (selected one by default)")
 	DevMode = flag.Bool("dev", false, "Run in dev mode (load test map, show debug logs etc.)")
+	LintMod = flag.Bool("lint-mod", false, "Check XML files of current mod for missing texts and broken references, print report and exit")
 )
 
 func SetAssetFS(fs embed.FS) {
@@ -28,12 +28,26 @@ func SetAssetFS(fs embed.FS) {
 
 func RunApplication() {
 	flag.Parse()
+	InitLogging(*DevMode)
 
 	if *RunGame {
-		_ = LaunchGame("")
+		config, _ := GetConfig()
+		GlobalConfig = &config
+		profile, err := config.GetLaunchProfile(*Profile)
+		if err == nil {
+			err = LaunchGame(profile, "", LaunchNewGame)
+		}
+		if err != nil {
+			Logger.Error("Cannot launch game", "error", err)
+			os.Exit(1)
+		}
 		return
 	}
 
+	if *LintMod {
+		os.Exit(RunLintMod(os.Stdout))
+	}
+
 	app := NewApp()
 
 	err := wails.Run(&options.App{
@@ -52,6 +66,6 @@ func RunApplication() {
 	})
 
 	if err != nil {
-		log.Println("Error: ", err)
+		Logger.Error("Application failed", "error", err)
 	}
 }
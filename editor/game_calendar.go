@@ -0,0 +1,223 @@
+package editor
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Calendar types are hardcoded in the game engine (CalendarTypes enum), so they are not taken from CIV4CalendarInfos.xml
+const (
+	CalendarDefault  = "CALENDAR_DEFAULT"
+	CalendarBiYearly = "CALENDAR_BI_YEARLY"
+	CalendarYears    = "CALENDAR_YEARS"
+	CalendarTurns    = "CALENDAR_TURNS"
+	CalendarSeasons  = "CALENDAR_SEASONS"
+	CalendarMonths   = "CALENDAR_MONTHS"
+	CalendarWeeks    = "CALENDAR_WEEKS"
+)
+
+// MonthsPerYear and SeasonsPerYear are numbers of entries in CIV4MonthInfos.xml and CIV4SeasonInfos.xml
+const (
+	MonthsPerYear  = 12
+	SeasonsPerYear = 4
+)
+
+// DefaultSpeed is used by the game when map doesn't set game speed
+const DefaultSpeed = "GAMESPEED_NORMAL"
+
+// MonthNames are English names of months (TXT_KEY_MONTH_*), used to format dates
+var MonthNames = []string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}
+
+// gameDateRegex matches dates accepted by ParseGameDate: year (negative for BC) and optional month number
+var gameDateRegex = regexp.MustCompile(`^\s*(-?\d+)(?:\s*[-/.]\s*(\d{1,2}))?\s*$`)
+
+// maxCalendarTurn limits search of the turn by date, it's much more than any game speed has
+const maxCalendarTurn = 1 << 20
+
+// GameDate is an in-game date
+type GameDate struct {
+	// Year is negative for BC dates
+	Year int
+	// Month is a month index from 0 (January) to 11 (December)
+	Month int
+}
+
+// String returns date in "1939-09" format, accepted by ParseGameDate
+func (d GameDate) String() string {
+	return fmt.Sprintf("%d-%02d", d.Year, d.Month+1)
+}
+
+// ParseGameDate parses date in "YEAR" or "YEAR-MONTH" format (e.g. "-4000" or "1939-09"). Month is 1-based, January if not set
+func ParseGameDate(s string) (GameDate, error) {
+	matches := gameDateRegex.FindStringSubmatch(s)
+	if matches == nil {
+		return GameDate{}, errors.New("invalid date " + s + ", expected YEAR or YEAR-MONTH")
+	}
+
+	date := GameDate{}
+	date.Year, _ = strconv.Atoi(matches[1])
+	if matches[2] != "" {
+		month, _ := strconv.Atoi(matches[2])
+		if month < 1 || month > MonthsPerYear {
+			return GameDate{}, errors.New("invalid month in date " + s)
+		}
+		date.Month = month - 1
+	}
+
+	return date, nil
+}
+
+// Calendar computes in-game dates of turns the same way the game does (getTurnMonthForGame in the game core)
+type Calendar struct {
+	// Type is one of Calendar* constants
+	Type      string
+	StartYear int
+	// Speed defines month increments of the default calendar
+	Speed *SpeedInfo
+	// WeeksPerMonth is used by the weeks calendar (WEEKS_PER_MONTHS global define)
+	WeeksPerMonth int
+}
+
+// NewCalendar returns a calendar for the calendar type and game speed (see Game.Calendar and Game.Speed).
+// Empty values are replaced with defaults the same way the game does
+func (r *GameRegistries) NewCalendar(calendarType string, speed string, startYear int) (*Calendar, error) {
+	if calendarType == "" {
+		calendarType = CalendarDefault
+	}
+	if speed == "" {
+		speed = DefaultSpeed
+	}
+
+	c := &Calendar{Type: calendarType, StartYear: startYear, WeeksPerMonth: r.GlobalIntDefines["WEEKS_PER_MONTHS"]}
+	switch calendarType {
+	case CalendarDefault:
+		c.Speed = r.SpeedInfos[speed]
+		if c.Speed == nil || len(c.Speed.GameTurns) == 0 {
+			return nil, errors.New("game speed " + speed + " not found or has no turn increments")
+		}
+	case CalendarWeeks:
+		if c.WeeksPerMonth <= 0 {
+			return nil, errors.New("WEEKS_PER_MONTHS define is not set")
+		}
+	case CalendarBiYearly, CalendarYears, CalendarTurns, CalendarSeasons, CalendarMonths:
+	default:
+		return nil, errors.New("unknown calendar " + calendarType)
+	}
+
+	return c, nil
+}
+
+// NewGameCalendar returns a calendar for the map game settings
+func (r *GameRegistries) NewGameCalendar(g *Game) (*Calendar, error) {
+	return r.NewCalendar(g.Calendar, g.Speed, g.StartYear)
+}
+
+// GetTurnMonth returns the number of months from year 0 to the turn (negative for BC dates).
+// Integer math is intentionally the same as in the game, including truncation of negative values
+func (c *Calendar) GetTurnMonth(turn int) int {
+	month := c.StartYear * MonthsPerYear
+
+	switch c.Type {
+	case CalendarDefault:
+		turnCount := 0
+		for _, increment := range c.Speed.GameTurns {
+			if turn > turnCount+increment.TurnsPerIncrement {
+				month += increment.MonthIncrement * increment.TurnsPerIncrement
+				turnCount += increment.TurnsPerIncrement
+			} else {
+				month += increment.MonthIncrement * (turn - turnCount)
+				turnCount = turn
+				break
+			}
+		}
+
+		// Turns after the last segment use its increment
+		if turn > turnCount {
+			month += c.Speed.GameTurns[len(c.Speed.GameTurns)-1].MonthIncrement * (turn - turnCount)
+		}
+	case CalendarBiYearly:
+		month += 2 * turn * MonthsPerYear
+	case CalendarYears, CalendarTurns:
+		month += turn * MonthsPerYear
+	case CalendarSeasons:
+		month += turn * MonthsPerYear / SeasonsPerYear
+	case CalendarMonths:
+		month += turn
+	case CalendarWeeks:
+		month += turn / c.WeeksPerMonth
+	}
+
+	return month
+}
+
+// GetTurnDate returns the in-game date of the turn (turn 0 is the first turn of the game)
+func (c *Calendar) GetTurnDate(turn int) GameDate {
+	month := c.GetTurnMonth(turn)
+
+	// Months of BC years are counted from January too, so the year is rounded down (e.g. month -1 is December 1 BC).
+	// Then the date has the same months from year 0 as the turn, and GetTurnForDate returns the turn back
+	year := month / MonthsPerYear
+	if month%MonthsPerYear < 0 {
+		year--
+	}
+	return GameDate{Year: year, Month: month - year*MonthsPerYear}
+}
+
+// GetTurnForDate returns the first turn on or after the date. exact is false if no turn has exactly this date
+// (e.g. default calendar skips years in early game). Returns error if the date is before the start year
+func (c *Calendar) GetTurnForDate(date GameDate) (turn int, exact bool, err error) {
+	target := date.Year*MonthsPerYear + date.Month
+	if c.GetTurnMonth(0) > target {
+		return 0, false, fmt.Errorf("date %s is before the start year %d", date, c.StartYear)
+	}
+
+	// Months never decrease with turns, so binary search can be used
+	high := 1
+	for c.GetTurnMonth(high) < target {
+		if high >= maxCalendarTurn {
+			return 0, false, fmt.Errorf("date %s is too far from the start year %d", date, c.StartYear)
+		}
+		high *= 2
+	}
+
+	low := 0
+	for low < high {
+		middle := (low + high) / 2
+		if c.GetTurnMonth(middle) < target {
+			low = middle + 1
+		} else {
+			high = middle
+		}
+	}
+
+	return low, c.GetTurnMonth(low) == target, nil
+}
+
+// FormatTurnDate returns the date of the turn as the game shows it (e.g. "4000 BC", "September 1939 AD" or "Turn 10")
+func (c *Calendar) FormatTurnDate(turn int) string {
+	date := c.GetTurnDate(turn)
+	year := fmt.Sprintf("%d AD", date.Year)
+	if date.Year < 0 {
+		year = fmt.Sprintf("%d BC", -date.Year)
+	}
+
+	switch c.Type {
+	case CalendarTurns:
+		return fmt.Sprintf("Turn %d", turn+1)
+	case CalendarYears, CalendarBiYearly:
+		return year
+	case CalendarSeasons:
+		return fmt.Sprintf("Season %d, %s", turn%SeasonsPerYear+1, year)
+	case CalendarWeeks:
+		return fmt.Sprintf("Week %d, %s %s", turn%c.WeeksPerMonth+1, MonthNames[date.Month], year)
+	case CalendarDefault:
+		// Month is shown only when turns are shorter than a year
+		if (c.GetTurnMonth(turn+1)-c.GetTurnMonth(turn))%MonthsPerYear == 0 {
+			return year
+		}
+	}
+
+	return MonthNames[date.Month] + " " + year
+}
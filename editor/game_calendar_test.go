@@ -0,0 +1,123 @@
+package editor
+
+import (
+	"testing"
+)
+
+// testNormalSpeed has the same turn increments as the normal speed of Beyond the Sword
+var testNormalSpeed = &SpeedInfo{
+	TypeInfo: TypeInfo{Type: "GAMESPEED_NORMAL"},
+	GameTurns: []GameTurnInfo{
+		{480, 75}, {300, 60}, {240, 25}, {120, 50}, {60, 60}, {24, 50}, {12, 120},
+	},
+}
+
+func TestCalendarDefault(t *testing.T) {
+	r := NewGameRegistries()
+	r.SpeedInfos["GAMESPEED_NORMAL"] = testNormalSpeed
+
+	c, err := r.NewGameCalendar(&Game{StartYear: -4000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[int]string{
+		0:   "4000 BC",
+		1:   "3960 BC",
+		75:  "1000 BC",
+		200: "1400 AD",
+		440: "2020 AD",
+		460: "2040 AD",
+	}
+	for turn, expected := range tests {
+		if date := c.FormatTurnDate(turn); date != expected {
+			t.Errorf("FormatTurnDate(%d) failed: expected %s, got %s", turn, expected, date)
+		}
+	}
+
+	// 1000 BC is exactly turn 75, 990 BC is between turns 75 and 76
+	if turn, exact, err := c.GetTurnForDate(GameDate{Year: -1000}); err != nil || turn != 75 || !exact {
+		t.Errorf("GetTurnForDate failed: %d %v %v", turn, exact, err)
+	}
+	if turn, exact, err := c.GetTurnForDate(GameDate{Year: -990}); err != nil || turn != 76 || exact {
+		t.Errorf("GetTurnForDate failed: %d %v %v", turn, exact, err)
+	}
+	if _, _, err := c.GetTurnForDate(GameDate{Year: -5000}); err == nil {
+		t.Error("GetTurnForDate failed: expected error for date before start")
+	}
+
+	if _, err = r.NewCalendar(CalendarDefault, "GAMESPEED_UNKNOWN", 0); err == nil {
+		t.Error("NewCalendar failed: expected error for unknown speed")
+	}
+}
+
+func TestCalendarMonths(t *testing.T) {
+	r := NewGameRegistries()
+	r.GlobalIntDefines["WEEKS_PER_MONTHS"] = 4
+
+	c, err := r.NewCalendar(CalendarMonths, "", 1939)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	date, _ := ParseGameDate("1939-09")
+	turn, exact, err := c.GetTurnForDate(date)
+	if err != nil || turn != 8 || !exact {
+		t.Errorf("GetTurnForDate failed: %d %v %v", turn, exact, err)
+	}
+	if c.FormatTurnDate(turn) != "September 1939 AD" || c.GetTurnDate(turn) != date {
+		t.Errorf("FormatTurnDate failed: %s", c.FormatTurnDate(turn))
+	}
+
+	c, _ = r.NewCalendar(CalendarWeeks, "", 1939)
+	if c.FormatTurnDate(33) != "Week 2, September 1939 AD" {
+		t.Errorf("FormatTurnDate failed for weeks: %s", c.FormatTurnDate(33))
+	}
+
+	c, _ = r.NewCalendar(CalendarSeasons, "", 1939)
+	if c.GetTurnDate(5) != (GameDate{Year: 1940, Month: 3}) {
+		t.Errorf("GetTurnDate failed for seasons: %v", c.GetTurnDate(5))
+	}
+
+	if _, err = ParseGameDate("1939-13"); err == nil {
+		t.Error("ParseGameDate failed: expected error for invalid month")
+	}
+}
+
+func TestCalendarBCRoundTrip(t *testing.T) {
+	r := NewGameRegistries()
+	r.GlobalIntDefines["WEEKS_PER_MONTHS"] = 4
+
+	tests := []struct {
+		calendar  string
+		startYear int
+		turn      int
+		date      GameDate
+	}{
+		{CalendarMonths, -1, 0, GameDate{Year: -1, Month: 0}},
+		{CalendarMonths, -1, 11, GameDate{Year: -1, Month: 11}},
+		{CalendarMonths, -1, 12, GameDate{Year: 0, Month: 0}},
+		{CalendarMonths, -4000, 13, GameDate{Year: -3999, Month: 1}},
+		{CalendarSeasons, -100, 3, GameDate{Year: -100, Month: 9}},
+		{CalendarSeasons, -100, 5, GameDate{Year: -99, Month: 3}},
+		{CalendarWeeks, -10, 9, GameDate{Year: -10, Month: 2}},
+		{CalendarYears, -4000, 3, GameDate{Year: -3997, Month: 0}},
+	}
+	for _, test := range tests {
+		c, err := r.NewCalendar(test.calendar, "", test.startYear)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if date := c.GetTurnDate(test.turn); date != test.date {
+			t.Errorf("GetTurnDate(%d) of %s failed: expected %v, got %v", test.turn, test.calendar, test.date, date)
+		}
+		// Weeks calendar has several turns in a month, the first one is found
+		expected := test.turn
+		if test.calendar == CalendarWeeks {
+			expected -= test.turn % c.WeeksPerMonth
+		}
+		if turn, exact, err := c.GetTurnForDate(test.date); err != nil || turn != expected || !exact {
+			t.Errorf("GetTurnForDate(%v) of %s failed: %d %v %v", test.date, test.calendar, turn, exact, err)
+		}
+	}
+}
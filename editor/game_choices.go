@@ -0,0 +1,148 @@
+package editor
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// ChoiceOrder is an order of choices (see GetChoices)
+type ChoiceOrder string
+
+const (
+	// OrderXML keeps the order entries are defined in XML files, the game uses it for type IDs and its own lists
+	OrderXML ChoiceOrder = "xml"
+	// OrderName sorts entries by localized description
+	OrderName ChoiceOrder = "name"
+)
+
+// Choice is a registry entry prepared for choice lists in UI (dropdowns, checkboxes etc.)
+type Choice struct {
+	Type string `json:"type"`
+	// Description is a localized name, or type itself if the entry has no name
+	Description string `json:"description"`
+	// Help is a localized additional description, may be empty
+	Help string `json:"help"`
+	// Icon is a path to the icon relative to the game directory, may be empty
+	Icon string `json:"icon"`
+}
+
+// InfoEntry is implemented by all infos embedding TypeInfo, so any registry can be converted to choices
+type InfoEntry interface {
+	GetTypeInfo() *TypeInfo
+}
+
+func (t *TypeInfo) GetTypeInfo() *TypeInfo {
+	return t
+}
+
+// registryChoices contains choice getters for registries available by name (see GetRegistryChoices)
+var registryChoices = map[string]func(r *GameRegistries, order ChoiceOrder) []Choice{
+	"EraInfos":          func(r *GameRegistries, order ChoiceOrder) []Choice { return GetChoices(r, r.EraInfos, order) },
+	"SpeedInfos":        func(r *GameRegistries, order ChoiceOrder) []Choice { return GetChoices(r, r.SpeedInfos, order) },
+	"CalendarInfos":     func(r *GameRegistries, order ChoiceOrder) []Choice { return GetChoices(r, r.CalendarInfos, order) },
+	"GameOptionInfos":   func(r *GameRegistries, order ChoiceOrder) []Choice { return GetChoices(r, r.GameOptionInfos, order) },
+	"GameMPInfos":       func(r *GameRegistries, order ChoiceOrder) []Choice { return GetChoices(r, r.GameMPInfos, order) },
+	"ForceControlInfos": func(r *GameRegistries, order ChoiceOrder) []Choice { return GetChoices(r, r.ForceControlInfos, order) },
+	"VictoryInfos":      func(r *GameRegistries, order ChoiceOrder) []Choice { return GetChoices(r, r.VictoryInfos, order) },
+	"TerrainInfos":      func(r *GameRegistries, order ChoiceOrder) []Choice { return GetChoices(r, r.TerrainInfos, order) },
+	"FeatureInfos":      func(r *GameRegistries, order ChoiceOrder) []Choice { return GetChoices(r, r.FeatureInfos, order) },
+	"BonusInfos":        func(r *GameRegistries, order ChoiceOrder) []Choice { return GetChoices(r, r.BonusInfos, order) },
+	"ImprovementInfos":  func(r *GameRegistries, order ChoiceOrder) []Choice { return GetChoices(r, r.ImprovementInfos, order) },
+	"RouteInfos":        func(r *GameRegistries, order ChoiceOrder) []Choice { return GetChoices(r, r.RouteInfos, order) },
+	"UnitInfos":         func(r *GameRegistries, order ChoiceOrder) []Choice { return GetChoices(r, r.UnitInfos, order) },
+	"PromotionInfos":    func(r *GameRegistries, order ChoiceOrder) []Choice { return GetChoices(r, r.PromotionInfos, order) },
+	"BuildingInfos":     func(r *GameRegistries, order ChoiceOrder) []Choice { return GetChoices(r, r.BuildingInfos, order) },
+	"TechInfos":         func(r *GameRegistries, order ChoiceOrder) []Choice { return GetChoices(r, r.TechInfos, order) },
+	"ProjectInfos":      func(r *GameRegistries, order ChoiceOrder) []Choice { return GetChoices(r, r.ProjectInfos, order) },
+	"CivilizationInfos": func(r *GameRegistries, order ChoiceOrder) []Choice { return GetChoices(r, r.CivilizationInfos, order) },
+	"LeaderHeadInfos":   func(r *GameRegistries, order ChoiceOrder) []Choice { return GetChoices(r, r.LeaderHeadInfos, order) },
+	"PlayerColorInfos":  func(r *GameRegistries, order ChoiceOrder) []Choice { return GetChoices(r, r.PlayerColorInfos, order) },
+	"ReligionInfos":     func(r *GameRegistries, order ChoiceOrder) []Choice { return GetChoices(r, r.ReligionInfos, order) },
+	"CivicInfos":        func(r *GameRegistries, order ChoiceOrder) []Choice { return GetChoices(r, r.CivicInfos, order) },
+	"CivicOptionInfos":  func(r *GameRegistries, order ChoiceOrder) []Choice { return GetChoices(r, r.CivicOptionInfos, order) },
+	"HandicapInfos":     func(r *GameRegistries, order ChoiceOrder) []Choice { return GetChoices(r, r.HandicapInfos, order) },
+	"ArtStyleInfos":     func(r *GameRegistries, order ChoiceOrder) []Choice { return GetChoices(r, r.ArtStyleInfos, order) },
+}
+
+// GetRegistryNames returns names of registries available as choices (see GetRegistryChoices)
+func GetRegistryNames() []string {
+	return SortKeys(registryChoices)
+}
+
+// GetChoices returns all entries of the registry (e.g. r.EraInfos) as choices in the specified order.
+// Entries with the same position (or name) are sorted by type, so the result is always the same
+func GetChoices[T InfoEntry](r *GameRegistries, registry map[string]T, order ChoiceOrder) []Choice {
+	choices := make([]Choice, 0, len(registry))
+	for _, entry := range registry {
+		info := entry.GetTypeInfo()
+		choice := Choice{
+			Type:        info.Type,
+			Description: r.GetLangString(info.Description),
+			Help:        r.GetLangString(info.Help),
+			Icon:        GetButtonPath(info.Button),
+		}
+		if choice.Description == "" {
+			choice.Description = info.Type
+		}
+
+		choices = append(choices, choice)
+	}
+
+	sort.Slice(choices, func(i, j int) bool {
+		a, b := choices[i], choices[j]
+		if order == OrderName {
+			if nameA, nameB := strings.ToLower(a.Description), strings.ToLower(b.Description); nameA != nameB {
+				return nameA < nameB
+			}
+		} else {
+			// Types without known position (not loaded from files) go to the end
+			posA, okA := r.XmlTypeOrder[a.Type]
+			posB, okB := r.XmlTypeOrder[b.Type]
+			if okA != okB {
+				return okA
+			}
+			if posA != posB {
+				return posA < posB
+			}
+		}
+
+		return a.Type < b.Type
+	})
+
+	return choices
+}
+
+// FilterChoices returns choices with the types, keeping their order
+func FilterChoices(choices []Choice, types []string) []Choice {
+	var result []Choice
+	for _, choice := range choices {
+		if IsInSlice(types, choice.Type) {
+			result = append(result, choice)
+		}
+	}
+	return result
+}
+
+// GetRegistryChoices returns choices of the registry by its name (e.g. "EraInfos", see GetRegistryNames)
+func (r *GameRegistries) GetRegistryChoices(registry string, order ChoiceOrder) ([]Choice, error) {
+	getter, ok := registryChoices[registry]
+	if !ok {
+		return nil, errors.New("unknown registry " + registry)
+	}
+
+	return getter(r, order), nil
+}
+
+// GetButtonPath returns an icon path from the button definition. Buttons may be defined in atlas format:
+// "[path],[fallback path],[atlas path],[column],[row]", where the first non-empty path is used
+func GetButtonPath(button string) string {
+	for _, part := range strings.Split(button, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			return part
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,52 @@
+package editor
+
+import (
+	"testing"
+)
+
+func TestGetChoices(t *testing.T) {
+	r := NewGameRegistries()
+	r.LangStrings["English"] = map[string]string{
+		"TXT_KEY_ERA_ANCIENT":  "Ancient",
+		"TXT_KEY_ERA_CLASSIC":  "Classical",
+		"TXT_KEY_ERA_MODERN":   "Modern",
+		"TXT_KEY_ERA_ANCIENT2": "Beginning of history",
+	}
+	r.EraInfos = map[string]*TypeInfo{
+		"ERA_ANCIENT":  {Type: "ERA_ANCIENT", Description: "TXT_KEY_ERA_ANCIENT", Help: "TXT_KEY_ERA_ANCIENT2"},
+		"ERA_CLASSIC":  {Type: "ERA_CLASSIC", Description: "TXT_KEY_ERA_CLASSIC"},
+		"ERA_MODERN":   {Type: "ERA_MODERN", Description: "TXT_KEY_ERA_MODERN", Button: ",Art/Modern.dds,Art/Atlas.dds,1,2"},
+		"ERA_UNLOADED": {Type: "ERA_UNLOADED"},
+	}
+	r.XmlTypeOrder = map[string]int{"ERA_MODERN": 0, "ERA_ANCIENT": 1, "ERA_CLASSIC": 2}
+
+	expected := map[ChoiceOrder][]string{
+		OrderXML:  {"ERA_MODERN", "ERA_ANCIENT", "ERA_CLASSIC", "ERA_UNLOADED"},
+		OrderName: {"ERA_ANCIENT", "ERA_CLASSIC", "ERA_UNLOADED", "ERA_MODERN"},
+	}
+
+	for order, types := range expected {
+		// Repeat to make sure map iteration order doesn't change the result
+		for i := 0; i < 10; i++ {
+			choices := GetChoices(r, r.EraInfos, order)
+			for j, choice := range choices {
+				if choice.Type != types[j] {
+					t.Fatalf("GetChoices failed: unexpected %s order %v", order, choices)
+				}
+			}
+		}
+	}
+
+	choices, err := r.GetRegistryChoices("EraInfos", OrderXML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if choices[0].Icon != "Art/Modern.dds" || choices[1].Description != "Ancient" || choices[1].Help != "Beginning of history" || choices[3].Description != "ERA_UNLOADED" {
+		t.Errorf("GetRegistryChoices failed: unexpected choices %+v", choices)
+	}
+
+	if _, err = r.GetRegistryChoices("UnknownInfos", OrderXML); err == nil {
+		t.Error("GetRegistryChoices failed: expected error for unknown registry")
+	}
+}
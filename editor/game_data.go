@@ -0,0 +1,72 @@
+package editor
+
+import (
+	"context"
+	"sync"
+)
+
+// GameData is game information loaded from the game directory and mod (see LoadGameData).
+// Every instance is independent, so several mods can be loaded and compared at once.
+// It's safe for concurrent use: Reload loads new registries first and replaces them at once,
+// so readers never see partially loaded data and are not blocked while files are parsed.
+type GameData struct {
+	mu         sync.RWMutex
+	reloadMu   sync.Mutex
+	registries *GameRegistries
+
+	// GameDir is a game directory the data is loaded from, it must not be changed after creation
+	GameDir string
+	// Mod is a mod name the data is loaded for (empty for base game), it must not be changed after creation
+	Mod string
+}
+
+// NewGameData returns empty game data for the game directory and mod, use Reload to load it
+func NewGameData(gameDir string, mod string) *GameData {
+	return &GameData{GameDir: gameDir, Mod: mod, registries: NewGameRegistries()}
+}
+
+// LoadGameData loads game data for the game directory and mod (empty for base game).
+// Progress is reported to the reporter of the context (see StartProgress), loading stops if the context is cancelled
+func LoadGameData(ctx context.Context, gameDir string, mod string) (*GameData, error) {
+	data := NewGameData(gameDir, mod)
+	if err := data.Reload(ctx); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Reload loads XML files again and replaces registries when loading is finished.
+// Registries are kept unchanged if loading fails or is cancelled. Concurrent reloads are executed one by one.
+func (d *GameData) Reload(ctx context.Context) error {
+	d.reloadMu.Lock()
+	defer d.reloadMu.Unlock()
+
+	registries, err := LoadGameRegistries(ctx, d.GameDir, d.Mod)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	d.registries = registries
+	d.mu.Unlock()
+	return nil
+}
+
+// Registries returns currently loaded registries. They are never modified, so the result stays consistent
+// even if the data is reloaded meanwhile. Use it once for a group of reads (e.g. building a form).
+// Nil game data returns empty registries, so UI can be shown before data is loaded.
+func (d *GameData) Registries() *GameRegistries {
+	if d == nil {
+		return NewGameRegistries()
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.registries
+}
+
+// GetLangString returns language string by key in the configured language (see GameRegistries.GetLangString)
+func (d *GameData) GetLangString(key string) string {
+	return d.Registries().GetLangString(key)
+}
@@ -2,10 +2,8 @@ package editor
 
 import (
 	"errors"
-	"fmt"
 	"os"
 	"path/filepath"
-	"runtime"
 	"strings"
 )
 
@@ -14,6 +12,8 @@ const AssetsDir = "Assets"
 const PublicMapsDir = "PublicMaps"
 const PrivateMapsDir = "PrivateMaps"
 const ModsDir = "Mods"
+const WarlordsDir = "Warlords"
+const BtsDir = "Beyond the Sword"
 
 // CheckGameDirectory checks if game directory contains executable and required directories
 func CheckGameDirectory(path string) error {
@@ -55,16 +55,56 @@ func GetPublicMapsDir() string {
 	return GetModDir() + string(os.PathSeparator) + PublicMapsDir
 }
 
-// GetRootDirs returns list of directories where to search for game files (game directory + mod directory)
+// GetRootDirs returns list of directories where to search for game files for the current configuration (see GetRootDirsFor)
 func GetRootDirs() []string {
-	dirs := []string{GlobalConfig.GameDir}
-	if IsMod() {
-		dirs = append(dirs, GetModDir())
+	return GetRootDirsFor(GlobalConfig.GameDir, GlobalConfig.Mod)
+}
+
+// GetRootDirsFor returns list of directories where to search for game files (base game directories + mod directory, if mod is not empty).
+// Directories are ordered the same way the game loads them, so files from later directories replace earlier ones
+func GetRootDirsFor(gameDir string, mod string) []string {
+	dirs := GetBaseGameDirs(gameDir)
+	if mod != "" {
+		dirs = append(dirs, filepath.Join(gameDir, ModsDir, mod))
 	}
 
 	return dirs
 }
 
+// GetBaseGameDirs returns list of base game directories in loading order: Vanilla, Warlords and Beyond the Sword.
+// Complete and Steam installations keep the expansions inside the original game directory,
+// and expansions only contain files that were changed, so the original files are still needed
+func GetBaseGameDirs(gameDir string) []string {
+	var dirs []string
+
+	base := filepath.Base(gameDir)
+	if strings.EqualFold(base, BtsDir) || strings.EqualFold(base, WarlordsDir) {
+		parent := filepath.Dir(gameDir)
+		if isDir(filepath.Join(parent, AssetsDir)) {
+			dirs = append(dirs, parent)
+		}
+
+		warlords := filepath.Join(parent, WarlordsDir)
+		if !strings.EqualFold(base, WarlordsDir) && isDir(filepath.Join(warlords, AssetsDir)) {
+			dirs = append(dirs, warlords)
+		}
+	}
+
+	return append(dirs, gameDir)
+}
+
+// isDir returns true if path exists and is a directory
+func isDir(path string) bool {
+	stat, err := os.Stat(path)
+	return err == nil && stat.IsDir()
+}
+
+// isFile returns true if path exists and is not a directory
+func isFile(path string) bool {
+	stat, err := os.Stat(path)
+	return err == nil && !stat.IsDir()
+}
+
 // GetModsList returns list of mods in game directory
 func GetModsList(path string) []string {
 	var mods []string
@@ -83,24 +123,14 @@ func GetModsList(path string) []string {
 	return mods
 }
 
-// GetFilesFromGameDirsRecursive returns a list of files by path (filepath or directory to scan) from all game directories (core game + mods) recursively
+// GetFilesFromGameDirsRecursive returns a list of files by path (filepath or directory to scan) from all game directories (core game + mods) recursively.
+// Files that cannot be read are skipped, their errors are joined and returned with the found files
 func GetFilesFromGameDirsRecursive(path string, ext string) (files []string, err error) {
-	var currentErr error
-
 	for _, dir := range GetRootDirs() {
-		subDir := dir + string(os.PathSeparator) + path
-
-		currentErr = filepath.Walk(subDir, func(walkFileName string, f os.FileInfo, err error) error {
-			if !strings.HasSuffix(strings.ToLower(walkFileName), "."+ext) {
-				return nil
-			}
-
-			files = append(files, walkFileName)
-			return nil
-		})
-
-		if currentErr != nil {
-			err = fmt.Errorf("%w\n%s", err, currentErr)
+		gameFiles, walkErr := walkGameFiles(dir, path, ext)
+		err = errors.Join(err, walkErr)
+		for _, file := range gameFiles {
+			files = append(files, file.Path)
 		}
 	}
 
@@ -124,7 +154,7 @@ func GetFilesFromGameDirs(path string) []string {
 
 		readDir, err := os.ReadDir(parent)
 		if err != nil {
-			ConsoleWrite(err.Error())
+			Logger.Warn("Cannot read directory", "path", parent, "error", err)
 			continue
 		}
 
@@ -139,51 +169,3 @@ func GetFilesFromGameDirs(path string) []string {
 
 	return files
 }
-
-// LaunchGame starts the game with current configured mod
-func LaunchGame(mapFileName string) error {
-	if err := CheckGameDirectory(GlobalConfig.GameDir); err != nil {
-		return err
-	}
-
-	var argv []string
-	var err error
-	if GlobalConfig.Mod != "" {
-		argv = append(argv, "mod=\" "+GlobalConfig.Mod+"\"")
-	}
-
-	if mapFileName != "" {
-		absPath, err := filepath.Abs(mapFileName)
-		if err == nil {
-			mapFileName = absPath
-		}
-
-		argv = append(argv, "/FXSLOAD=\\\"\""+absPath+"\"")
-	}
-
-	if runtime.GOOS != "windows" {
-		var game *os.Process
-		game, err = os.StartProcess(GetExe(), argv, &os.ProcAttr{
-			Dir:   GlobalConfig.GameDir,
-			Files: []*os.File{os.Stdin, os.Stdout, os.Stderr},
-			Sys:   nil,
-		})
-		if err != nil {
-			return err
-		}
-
-		go func() {
-			wait, err := game.Wait()
-			if err != nil {
-				ConsoleWrite(err.Error())
-				return
-			}
-
-			ConsoleWrite("Game exited with code %d", wait.ExitCode())
-		}()
-	} else {
-		return RunElevated(GetExe(), argv)
-	}
-
-	return nil
-}
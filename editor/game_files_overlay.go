@@ -0,0 +1,137 @@
+package editor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ModulesDir is a directory with XML modules inside mod directory (see IsModularLoading)
+const ModulesDir = "Assets/Modules"
+
+// modularLoadingRegex matches modular loading option in mod INI file ("ModularLoading = 1" in [CONFIG] section)
+var modularLoadingRegex = regexp.MustCompile(`(?im)^\s*b?ModularLoading\s*=\s*1`)
+
+// GameFile is a file found in one of the game root directories (see GetRootDirs)
+type GameFile struct {
+	// Path is a full path to the file
+	Path string
+	// RelativePath is a path relative to the root directory. Files with the same relative path replace each other
+	RelativePath string
+	// Root is a root directory the file was taken from (base game, expansion or mod)
+	Root string
+	// Module is true if the file is an XML module, which is merged with other files instead of replacing them
+	Module bool
+}
+
+// IsModularLoading returns true if the current mod enables modular XML loading (see IsModularLoadingFor)
+func IsModularLoading() bool {
+	return IsModularLoadingFor(GlobalConfig.GameDir, GlobalConfig.Mod)
+}
+
+// IsModularLoadingFor returns true if the mod enables modular XML loading in its INI file.
+// In this case, all XML files from Assets/Modules are loaded in addition to the regular ones.
+func IsModularLoadingFor(gameDir string, mod string) bool {
+	if mod == "" {
+		return false
+	}
+
+	data, err := os.ReadFile(filepath.Join(gameDir, ModsDir, mod, mod+".ini"))
+	if err != nil {
+		return false
+	}
+
+	return modularLoadingRegex.Match(data)
+}
+
+// GetXMLFiles returns all XML files the game would load for the current configuration (see GetXMLFilesFor)
+func GetXMLFiles() ([]*GameFile, error) {
+	return GetXMLFilesFor(GlobalConfig.GameDir, GlobalConfig.Mod)
+}
+
+// GetXMLFilesFor returns all XML files the game would load for the game directory and mod (empty for base game).
+// Regular files are overlaid by relative path (see GetOverlaidFilesFrom), modules (if enabled) are added to the end.
+func GetXMLFilesFor(gameDir string, mod string) ([]*GameFile, error) {
+	files, err := GetOverlaidFilesFrom(GetRootDirsFor(gameDir, mod), XmlDir, XmlExt)
+
+	if IsModularLoadingFor(gameDir, mod) {
+		modules, modulesErr := walkGameFiles(filepath.Join(gameDir, ModsDir, mod), ModulesDir, XmlExt)
+		if modulesErr != nil {
+			err = errors.Join(err, modulesErr)
+		}
+
+		for _, module := range modules {
+			module.Module = true
+			files = append(files, module)
+		}
+	}
+
+	return files, err
+}
+
+// GetOverlaidFiles returns files by path from all game directories of the current configuration (see GetOverlaidFilesFrom)
+func GetOverlaidFiles(path string, ext string) ([]*GameFile, error) {
+	return GetOverlaidFilesFrom(GetRootDirs(), path, ext)
+}
+
+// GetOverlaidFilesFrom returns files by path (directory to scan) from root directories (see GetRootDirsFor) with the game engine precedence:
+// if a later directory (expansion or mod) contains a file with the same relative path, it replaces the earlier one completely.
+// Relative paths are compared case-insensitively, as the game is made for Windows.
+func GetOverlaidFilesFrom(roots []string, path string, ext string) ([]*GameFile, error) {
+	var err error
+	overlay := make(map[string]*GameFile)
+
+	for _, root := range roots {
+		files, walkErr := walkGameFiles(root, path, ext)
+		if walkErr != nil {
+			err = errors.Join(err, walkErr)
+		}
+
+		for _, file := range files {
+			overlay[strings.ToLower(filepath.ToSlash(file.RelativePath))] = file
+		}
+	}
+
+	files := make([]*GameFile, 0, len(overlay))
+	for _, key := range SortKeys(overlay) {
+		files = append(files, overlay[key])
+	}
+
+	return files, err
+}
+
+// walkGameFiles returns files with extension from the root directory subpath recursively, sorted by path.
+// Missing directory is not an error, because mods usually contain only some of the game directories.
+// Files and directories that cannot be read are skipped, their errors are joined and returned with the found files.
+func walkGameFiles(root string, path string, ext string) ([]*GameFile, error) {
+	var files []*GameFile
+	var walkErrors error
+
+	dir := filepath.Join(root, filepath.FromSlash(path))
+	if !isDir(dir) {
+		return files, nil
+	}
+
+	err := filepath.Walk(dir, func(walkFileName string, f os.FileInfo, err error) error {
+		if err != nil {
+			walkErrors = errors.Join(walkErrors, err)
+			return nil
+		}
+
+		if f.IsDir() || !strings.HasSuffix(strings.ToLower(walkFileName), "."+ext) {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(root, walkFileName)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, &GameFile{Path: walkFileName, RelativePath: relativePath, Root: root})
+		return nil
+	})
+
+	return files, errors.Join(err, walkErrors)
+}
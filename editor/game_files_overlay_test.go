@@ -0,0 +1,74 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// createTestGameDir creates a fake Complete-like installation with Warlords, BTS and a mod with modular loading
+func createTestGameDir(t *testing.T) string {
+	root := t.TempDir()
+	files := map[string]string{
+		"Assets/XML/GameInfo/CIV4EraInfos.xml":                            "vanilla",
+		"Assets/XML/Units/CIV4UnitInfos.xml":                              "vanilla",
+		"Warlords/Assets/XML/Units/CIV4UnitInfos.xml":                     "warlords",
+		"Beyond the Sword/Assets/XML/GameInfo/CIV4EraInfos.xml":           "bts",
+		"Beyond the Sword/Mods/Test/Assets/XML/Units/Civ4UnitInfos.xml":   "mod",
+		"Beyond the Sword/Mods/Test/Assets/Modules/Extra/Extra_Units.xml": "module",
+		"Beyond the Sword/Mods/Test/Test.ini":                             "[CONFIG]\nModularLoading = 1\n",
+	}
+
+	for path, content := range files {
+		fullPath := filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return root
+}
+
+func TestGetBaseGameDirs(t *testing.T) {
+	root := createTestGameDir(t)
+	dirs := GetBaseGameDirs(filepath.Join(root, BtsDir))
+
+	if len(dirs) != 3 || dirs[0] != root || dirs[1] != filepath.Join(root, WarlordsDir) || dirs[2] != filepath.Join(root, BtsDir) {
+		t.Errorf("GetBaseGameDirs failed: %v", dirs)
+	}
+}
+
+func TestGetXMLFiles(t *testing.T) {
+	root := createTestGameDir(t)
+	oldConfig := GlobalConfig
+	defer func() { GlobalConfig = oldConfig }()
+
+	GlobalConfig = &Config{GameDir: filepath.Join(root, BtsDir), Mod: "Test"}
+	if !IsModularLoading() {
+		t.Fatal("IsModularLoading failed: modular loading is enabled in mod INI")
+	}
+
+	files, err := GetXMLFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) != 3 {
+		t.Fatalf("GetXMLFiles failed: expected 3 files, got %d", len(files))
+	}
+
+	expected := []string{"bts", "mod", "module"}
+	for i, file := range files {
+		content, _ := os.ReadFile(file.Path)
+		if string(content) != expected[i] {
+			t.Errorf("GetXMLFiles failed: expected file %d to be from %s, got %s (%s)", i, expected[i], content, file.Path)
+		}
+	}
+
+	if !files[2].Module || files[1].Module {
+		t.Error("GetXMLFiles failed: only module files should be marked as modules")
+	}
+}
@@ -1,59 +1,423 @@
 package editor
 
+// NoneType is used in game XML files (and sometimes in maps) when type is not set
+const NoneType = "NONE"
+
+// TypeInfo contains common fields of all game infos
 type TypeInfo struct {
-	Type        string
+	Type string
+	// Description is a text key of the name (e.g. TXT_KEY_TECH_WHEEL)
 	Description string
+	// Help is a text key of the additional description, not all infos have it
+	Help string
+	// Button is an icon path, may be empty or in atlas format (see GetButtonPath)
+	Button string
+}
+
+// SpeedInfo is a game speed defined in CIV4GameSpeedInfo.xml
+type SpeedInfo struct {
+	TypeInfo
+	// GameTurns defines how many months pass every turn for the default calendar (see Calendar)
+	GameTurns []GameTurnInfo
+	// Percents of the normal speed
+	GrowthPercent    int
+	TrainPercent     int
+	ConstructPercent int
+	ResearchPercent  int
+}
+
+// GameTurnInfo is a calendar segment of the game speed: every turn of the segment adds MonthIncrement months
+type GameTurnInfo struct {
+	MonthIncrement    int
+	TurnsPerIncrement int
+}
+
+// TerrainInfo is a base terrain of the plot (grassland, ocean etc.) defined in CIV4TerrainInfos.xml
+type TerrainInfo struct {
+	TypeInfo
+	// Yields is a base plot yield (food, production, commerce)
+	Yields           []int
+	RiverYieldChange []int
+	HillsYieldChange []int
+	Water            bool
+	Impassable       bool
+	// Found means that cities can be founded on this terrain
+	Found           bool
+	FoundCoast      bool
+	FoundFreshWater bool
+	Movement        int
+	Defense         int
+}
+
+// FeatureInfo is a terrain feature (forest, jungle, oasis etc.) defined in CIV4FeatureInfos.xml
+type FeatureInfo struct {
+	TypeInfo
+	YieldChanges      []int
+	RiverYieldChange  []int
+	HillsYieldChange  []int
+	Movement          int
+	HealthPercent     int
+	NoCoast           bool
+	NoRiver           bool
+	NoAdjacent        bool
+	RequiresFlatlands bool
+	RequiresRiver     bool
+	AddsFreshWater    bool
+	Impassable        bool
+	NoCity            bool
+	NoImprovement     bool
+	// ValidTerrains is a list of terrain types the feature can be placed on
+	ValidTerrains []string
+}
+
+// BonusInfo is a resource (wheat, iron etc.) defined in CIV4BonusInfos.xml
+type BonusInfo struct {
+	TypeInfo
+	BonusClass   string
+	TechReveal   string
+	TechTrade    string
+	TechObsolete string
+	YieldChanges []int
+	Health       int
+	Happiness    int
+	MinLatitude  int
+	MaxLatitude  int
+	Hills        bool
+	Flatlands    bool
+	NoRiverSide  bool
+	// ValidTerrains is a list of terrain types the bonus can be placed on without any feature
+	ValidTerrains []string
+	// ValidFeatures is a list of features the bonus can be placed on
+	ValidFeatures []string
+	// ValidFeatureTerrains is a list of terrain types under the feature (see ValidFeatures) the bonus can be placed on
+	ValidFeatureTerrains []string
+}
+
+// ImprovementInfo is a tile improvement (farm, mine etc.) defined in CIV4ImprovementInfos.xml
+type ImprovementInfo struct {
+	TypeInfo
+	PrereqNatureYields   []int
+	YieldChanges         []int
+	HillsMakesValid      bool
+	FreshWaterMakesValid bool
+	RiverSideMakesValid  bool
+	NoFreshWater         bool
+	RequiresFlatlands    bool
+	RequiresRiverSide    bool
+	RequiresIrrigation   bool
+	RequiresFeature      bool
+	Water                bool
+	Goody                bool
+	Permanent            bool
+	OutsideBorders       bool
+	// ValidTerrains, ValidFeatures and ValidBonuses are lists of types that make the improvement valid on the plot
+	ValidTerrains []string
+	ValidFeatures []string
+	ValidBonuses  []string
+	// TradeBonuses is a list of bonuses the improvement connects to the trade network
+	TradeBonuses []string
+	Pillage      string
+	Upgrade      string
 }
 
-var LangStrings = make(map[string]string)
-var GlobalStringDefines = make(map[string]string)
-var GlobalIntDefines = make(map[string]int)
-var GlobalFloatDefines = make(map[string]float64)
-var EraInfos = make(map[string]*TypeInfo)
-var SpeedInfos = make(map[string]*TypeInfo)
-var CalendarInfos = make(map[string]*TypeInfo)
-var GameOptionInfos = make(map[string]*TypeInfo)
-var GameMPInfos = make(map[string]*TypeInfo)
-var ForceControlInfos = make(map[string]*TypeInfo)
-var VictoryInfos = make(map[string]*TypeInfo)
-
-// GetLangString returns language string by key. If it's not found, returns key itself
-func GetLangString(key string) string {
+// RouteInfo is a route (road, railroad) defined in CIV4RouteInfos.xml
+type RouteInfo struct {
+	TypeInfo
+	Yields       []int
+	Movement     int
+	FlatMovement int
+	// PrereqBonus is required to build the route, as well as any of PrereqOrBonuses (if set)
+	PrereqBonus     string
+	PrereqOrBonuses []string
+}
+
+// UnitInfo is a unit type defined in CIV4UnitInfos.xml
+type UnitInfo struct {
+	TypeInfo
+	Class         string
+	Combat        string
+	Domain        string
+	DefaultUnitAI string
+	// UnitAIs is a list of AI types the unit can be assigned to (see Unit.UnitAIType)
+	UnitAIs         []string
+	PrereqBuilding  string
+	PrereqTech      string
+	PrereqTechs     []string
+	PrereqBonus     string
+	PrereqOrBonuses []string
+	FreePromotions  []string
+	Cost            int
+	Moves           int
+	Strength        int
+	Animal          bool
+	Found           bool
+	OnlyDefensive   bool
+}
+
+// PromotionInfo is a unit promotion defined in CIV4PromotionInfos.xml
+type PromotionInfo struct {
+	TypeInfo
+	PrereqPromotion string
+	// PrereqOrPromotions are alternatives, unit needs at least one of them (if set)
+	PrereqOrPromotions  []string
+	PrereqTech          string
+	PrereqStateReligion string
+	// UnitCombats is a list of unit combat types (UNITCOMBAT_MELEE etc.) which can acquire the promotion
+	UnitCombats []string
+	Leader      bool
+}
+
+// BuildingInfo is a building or wonder defined in CIV4BuildingInfos.xml
+type BuildingInfo struct {
+	TypeInfo
+	Class               string
+	SpecialBuildingType string
+	PrereqTech          string
+	PrereqTechs         []string
+	ObsoleteTech        string
+	PrereqBonus         string
+	PrereqOrBonuses     []string
+	PrereqReligion      string
+	HolyCity            string
+	Religion            string
+	StateReligion       string
+	FreeStartEra        string
+	MaxStartEra         string
+	Cost                int
+	Water               bool
+	River               bool
+	Capital             bool
+}
+
+// ProjectInfo is a team project (wonder built once per team or world) defined in CIV4ProjectInfo.xml
+type ProjectInfo struct {
+	TypeInfo
+	TechPrereq    string
+	VictoryPrereq string
+	// AnyoneProjectPrereq must be built by any team before this project is available
+	AnyoneProjectPrereq string
+	// PrereqProjects are projects (and their counts) the team must have
+	PrereqProjects map[string]int
+	// MaxGlobalInstances and MaxTeamInstances are -1 if not limited
+	MaxGlobalInstances int
+	MaxTeamInstances   int
+	Cost               int
+	Spaceship          bool
+}
+
+// TechInfo is a technology defined in CIV4TechInfos.xml
+type TechInfo struct {
+	TypeInfo
+	Era  string
+	Cost int
+	// AndPrereqs must all be known to research the tech, and at least one of OrPrereqs (if set)
+	AndPrereqs []string
+	OrPrereqs  []string
+	GridX      int
+	GridY      int
+}
+
+// CivilizationInfo is a civilization defined in CIV4CivilizationInfos.xml
+type CivilizationInfo struct {
+	TypeInfo
+	ShortDescription   string
+	Adjective          string
+	DefaultPlayerColor string
+	ArtStyle           string
+	Playable           bool
+	AIPlayable         bool
+	// Cities is a default list of city names (see Player.CityList)
+	Cities []string
+	// Leaders is a list of leader types available for the civilization
+	Leaders       []string
+	FreeTechs     []string
+	InitialCivics []string
+}
+
+// LeaderHeadInfo is a leader defined in CIV4LeaderHeadInfos.xml
+type LeaderHeadInfo struct {
+	TypeInfo
+	Traits           []string
+	FavoriteCivic    string
+	FavoriteReligion string
+}
+
+// PlayerColorInfo is a player color set defined in CIV4PlayerColorInfos.xml.
+// Color values are references to ColorVals (see CIV4ColorVals.xml)
+type PlayerColorInfo struct {
+	TypeInfo
+	PrimaryColor   string
+	SecondaryColor string
+	TextColor      string
+}
+
+// ColorVal is an RGBA color defined in CIV4ColorVals.xml. All components are in range 0..1
+type ColorVal struct {
+	Type  string
+	Red   float64
+	Green float64
+	Blue  float64
+	Alpha float64
+}
+
+// ReligionInfo is a religion defined in CIV4ReligionInfo.xml
+type ReligionInfo struct {
+	TypeInfo
+	Adjective  string
+	TechPrereq string
+	FreeUnit   string
+}
+
+// CivicInfo is a civic defined in CIV4CivicInfos.xml. Every civic belongs to a CivicOption (government, legal etc.)
+type CivicInfo struct {
+	TypeInfo
+	CivicOption string
+	TechPrereq  string
+	Upkeep      string
+}
+
+// HandicapInfo is a difficulty level defined in CIV4HandicapInfo.xml
+type HandicapInfo struct {
+	TypeInfo
+	StartingGold int
+	FreeUnits    int
+}
+
+// GameRegistries contains typed game information loaded from XML files (see LoadGameRegistries).
+// Registries are not modified after loading, so they can be read from any goroutine without locking.
+type GameRegistries struct {
+	// LangStrings contains game texts by language and then by tag (e.g. LangStrings["English"]["TXT_KEY_UNIT_WARRIOR"])
+	LangStrings map[string]map[string]string
+	// XmlTypeSources contains a path to the XML file every type (e.g. UNIT_WARRIOR) was loaded from
+	XmlTypeSources map[string]string
+	// XmlLoadReport describes problems found while loading XML files
+	XmlLoadReport *XmlLoadReport
+	// XmlTypeOrder contains an index of every type in loading order, it's the order the game uses for type IDs (see GetChoices)
+	XmlTypeOrder        map[string]int
+	GlobalStringDefines map[string]string
+	GlobalIntDefines    map[string]int
+	GlobalFloatDefines  map[string]float64
+	EraInfos            map[string]*TypeInfo
+	SpeedInfos          map[string]*SpeedInfo
+	CalendarInfos       map[string]*TypeInfo
+	GameOptionInfos     map[string]*TypeInfo
+	GameMPInfos         map[string]*TypeInfo
+	ForceControlInfos   map[string]*TypeInfo
+	VictoryInfos        map[string]*TypeInfo
+	TerrainInfos        map[string]*TerrainInfo
+	FeatureInfos        map[string]*FeatureInfo
+	BonusInfos          map[string]*BonusInfo
+	ImprovementInfos    map[string]*ImprovementInfo
+	RouteInfos          map[string]*RouteInfo
+	UnitInfos           map[string]*UnitInfo
+	PromotionInfos      map[string]*PromotionInfo
+	BuildingInfos       map[string]*BuildingInfo
+	TechInfos           map[string]*TechInfo
+	ProjectInfos        map[string]*ProjectInfo
+	CivilizationInfos   map[string]*CivilizationInfo
+	LeaderHeadInfos     map[string]*LeaderHeadInfo
+	PlayerColorInfos    map[string]*PlayerColorInfo
+	ColorVals           map[string]*ColorVal
+	ReligionInfos       map[string]*ReligionInfo
+	CivicInfos          map[string]*CivicInfo
+	CivicOptionInfos    map[string]*TypeInfo
+	HandicapInfos       map[string]*HandicapInfo
+	ArtStyleInfos       map[string]*TypeInfo
+}
+
+// NewGameRegistries returns empty registries
+func NewGameRegistries() *GameRegistries {
+	return &GameRegistries{
+		LangStrings:         make(map[string]map[string]string),
+		XmlTypeSources:      make(map[string]string),
+		XmlTypeOrder:        make(map[string]int),
+		XmlLoadReport:       &XmlLoadReport{},
+		GlobalStringDefines: make(map[string]string),
+		GlobalIntDefines:    make(map[string]int),
+		GlobalFloatDefines:  make(map[string]float64),
+		EraInfos:            make(map[string]*TypeInfo),
+		SpeedInfos:          make(map[string]*SpeedInfo),
+		CalendarInfos:       make(map[string]*TypeInfo),
+		GameOptionInfos:     make(map[string]*TypeInfo),
+		GameMPInfos:         make(map[string]*TypeInfo),
+		ForceControlInfos:   make(map[string]*TypeInfo),
+		VictoryInfos:        make(map[string]*TypeInfo),
+		TerrainInfos:        make(map[string]*TerrainInfo),
+		FeatureInfos:        make(map[string]*FeatureInfo),
+		BonusInfos:          make(map[string]*BonusInfo),
+		ImprovementInfos:    make(map[string]*ImprovementInfo),
+		RouteInfos:          make(map[string]*RouteInfo),
+		UnitInfos:           make(map[string]*UnitInfo),
+		PromotionInfos:      make(map[string]*PromotionInfo),
+		BuildingInfos:       make(map[string]*BuildingInfo),
+		TechInfos:           make(map[string]*TechInfo),
+		ProjectInfos:        make(map[string]*ProjectInfo),
+		CivilizationInfos:   make(map[string]*CivilizationInfo),
+		LeaderHeadInfos:     make(map[string]*LeaderHeadInfo),
+		PlayerColorInfos:    make(map[string]*PlayerColorInfo),
+		ColorVals:           make(map[string]*ColorVal),
+		ReligionInfos:       make(map[string]*ReligionInfo),
+		CivicInfos:          make(map[string]*CivicInfo),
+		CivicOptionInfos:    make(map[string]*TypeInfo),
+		HandicapInfos:       make(map[string]*HandicapInfo),
+		ArtStyleInfos:       make(map[string]*TypeInfo),
+	}
+}
+
+// GetLangString returns language string by key in the configured language (see GetLanguage).
+// If it's not found in the language or its fallbacks, returns key itself
+func (r *GameRegistries) GetLangString(key string) string {
+	return r.GetLangStringFor(key, GetLanguage())
+}
+
+// GetLangStringFor returns language string by key in the specified language, trying fallback languages (see GetLanguageChain).
+// If it's not found, returns key itself
+func (r *GameRegistries) GetLangStringFor(key string, language string) string {
 	if key == "" {
 		return ""
 	}
 
-	// Support keys with and without TXT_KEY_ prefix as automatic fallback
-	for _, variant := range []string{key, "TXT_KEY_" + key} {
-		value, ok := LangStrings[variant]
-		if ok {
-			return value
+	for _, lang := range GetLanguageChain(language) {
+		// Support keys with and without TXT_KEY_ prefix as automatic fallback
+		for _, variant := range []string{key, "TXT_KEY_" + key} {
+			value, ok := r.LangStrings[lang][variant]
+			if ok {
+				return value
+			}
 		}
 	}
 
 	return key
 }
 
-func GetEraNames() []string {
-	var result []string
-	for _, era := range EraInfos {
-		result = append(result, era.Type)
+// GetCivilizationLeaders returns leader types available for the civilization
+func (r *GameRegistries) GetCivilizationLeaders(civType string) []string {
+	civ, ok := r.CivilizationInfos[civType]
+	if !ok {
+		return nil
 	}
-	return result
+
+	return civ.Leaders
 }
 
-func GetSpeedNames() []string {
+// GetLeaderCivilizations returns civilization types the leader belongs to (usually only one)
+func (r *GameRegistries) GetLeaderCivilizations(leaderType string) []string {
 	var result []string
-	for _, speed := range SpeedInfos {
-		result = append(result, speed.Type)
+	for _, civType := range SortKeys(r.CivilizationInfos) {
+		if IsInSlice(r.CivilizationInfos[civType].Leaders, leaderType) {
+			result = append(result, civType)
+		}
 	}
 	return result
 }
 
-func GetCalendarNames() []string {
+// GetCivicsByOption returns civic types belonging to the civic option (e.g. CIVICOPTION_GOVERNMENT)
+func (r *GameRegistries) GetCivicsByOption(civicOption string) []string {
 	var result []string
-	for _, calendar := range CalendarInfos {
-		result = append(result, calendar.Type)
+	for _, civicType := range SortKeys(r.CivicInfos) {
+		if r.CivicInfos[civicType].CivicOption == civicOption {
+			result = append(result, civicType)
+		}
 	}
 	return result
 }
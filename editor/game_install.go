@@ -0,0 +1,285 @@
+package editor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+const VanillaExe = "Civilization4.exe"
+const WarlordsExe = "Civ4Warlords.exe"
+
+// Editions of the game (see GameInstall)
+const (
+	EditionVanilla  = "Vanilla"
+	EditionWarlords = "Warlords"
+	EditionBts      = "Beyond the Sword"
+	// EditionComplete is the original game with both expansions in its subdirectories (Complete, Steam and GOG releases)
+	EditionComplete = "Complete"
+)
+
+// Sources of game installations
+const (
+	InstallSteam  = "Steam"
+	InstallGOG    = "GOG"
+	InstallRetail = "Retail"
+)
+
+// steamGamePattern matches game directories of Steam library: the original game and its expansions are separate apps
+var steamGamePattern = filepath.Join("steamapps", "common", "Sid Meier's Civilization IV*")
+
+// defaultInstallDirs are directories used by installers, relative to a drive root (or drive_c of Wine prefix)
+var defaultInstallDirs = []struct {
+	dir    string
+	source string
+}{
+	{filepath.Join("Program Files (x86)", "Firaxis Games", "Sid Meier's Civilization 4"), InstallRetail},
+	{filepath.Join("Program Files", "Firaxis Games", "Sid Meier's Civilization 4"), InstallRetail},
+	{filepath.Join("Program Files (x86)", "2K Games", "Firaxis Games", "Sid Meier's Civilization 4 Complete"), InstallRetail},
+	{filepath.Join("Program Files", "2K Games", "Firaxis Games", "Sid Meier's Civilization 4 Complete"), InstallRetail},
+	{filepath.Join("GOG Games", "Civilization IV Complete"), InstallGOG},
+	{filepath.Join("Program Files (x86)", "GOG Galaxy", "Games", "Civilization IV Complete"), InstallGOG},
+}
+
+// steamDirInPrefix is a directory of Steam installed into Wine prefix
+var steamDirInPrefix = filepath.Join("drive_c", "Program Files (x86)", "Steam")
+
+// libraryFolderPattern matches library paths of libraryfolders.vdf: "path" values, or numbered values of the old format.
+// A key and its value are on the same line
+var libraryFolderPattern = regexp.MustCompile(`"(path|\d+)"[ \t]+"((?:[^"\\]|\\.)*)"`)
+
+// GameInstall is a game installation found by FindGameInstalls
+type GameInstall struct {
+	// Dir is a game directory for Config.GameDir, the directory of the latest expansion
+	Dir     string `json:"dir"`
+	Edition string `json:"edition"`
+	// Version is a file version of the game executable, e.g. "3.1.9.0", empty if it's unknown
+	Version string `json:"version"`
+	// Source is a store or installer of the game: InstallSteam, InstallGOG or InstallRetail
+	Source string `json:"source"`
+	// WinePrefix and Proton are set if the game is installed into Wine prefix or played with Proton
+	WinePrefix string `json:"wine_prefix"`
+	Proton     string `json:"proton"`
+	// Supported is true if the editor can use the installation, it needs Beyond the Sword
+	Supported bool `json:"supported"`
+}
+
+// installCandidate is a directory that may contain the game and its expansions
+type installCandidate struct {
+	dir    string
+	source string
+	prefix string
+	proton string
+}
+
+// FindGameInstalls returns game installations of Steam libraries, default directories of installers and Wine prefixes.
+// Supported installations go first
+func FindGameInstalls() []GameInstall {
+	var candidates []installCandidate
+	for _, root := range getSteamRoots() {
+		candidates = append(candidates, getSteamCandidates(root, "")...)
+	}
+
+	if runtime.GOOS == "windows" {
+		for _, drive := range getWindowsDrives() {
+			candidates = append(candidates, getDefaultCandidates(drive, "")...)
+		}
+	} else {
+		for _, prefix := range getWinePrefixes() {
+			candidates = append(candidates, getSteamCandidates(filepath.Join(prefix, steamDirInPrefix), prefix)...)
+			candidates = append(candidates, getDefaultCandidates(filepath.Join(prefix, "drive_c"), prefix)...)
+		}
+	}
+
+	var installs []GameInstall
+	var found []string
+	for _, candidate := range candidates {
+		for _, install := range detectInstalls(candidate) {
+			if dir := resolveLinks(install.Dir); !IsInSlice(found, dir) {
+				installs, found = append(installs, install), append(found, dir)
+			}
+		}
+	}
+
+	slices.SortStableFunc(installs, func(a, b GameInstall) int {
+		if a.Supported == b.Supported {
+			return 0
+		} else if a.Supported {
+			return -1
+		}
+		return 1
+	})
+	return installs
+}
+
+// getSteamRoots returns Steam directories: default ones on Windows, native and Flatpak ones of the user on other platforms.
+// Links to the same directory are skipped
+func getSteamRoots() []string {
+	var candidates []string
+	if runtime.GOOS == "windows" {
+		candidates = []string{`C:\Program Files (x86)\Steam`, `C:\Program Files\Steam`}
+	} else if home, err := os.UserHomeDir(); err == nil {
+		candidates = []string{
+			filepath.Join(home, ".steam", "steam"),
+			filepath.Join(home, ".local", "share", "Steam"),
+			filepath.Join(home, ".var", "app", "com.valvesoftware.Steam", ".local", "share", "Steam"),
+		}
+	}
+
+	var roots, resolved []string
+	for _, root := range candidates {
+		real, err := filepath.EvalSymlinks(root)
+		if err != nil || IsInSlice(resolved, real) || !isDir(filepath.Join(real, "steamapps")) {
+			continue
+		}
+		roots, resolved = append(roots, root), append(resolved, real)
+	}
+	return roots
+}
+
+// getSteamCandidates returns game directories of all libraries of Steam. Paths of Steam installed into Wine prefix are translated
+func getSteamCandidates(root string, prefix string) []installCandidate {
+	var candidates []installCandidate
+	for _, library := range getSteamLibraries(root, prefix) {
+		dirs, _ := filepath.Glob(filepath.Join(library, steamGamePattern))
+		for _, dir := range dirs {
+			candidate := installCandidate{dir: dir, source: InstallSteam, prefix: prefix}
+			if prefix == "" && runtime.GOOS != "windows" {
+				wine := DetectWine(dir)
+				candidate.prefix, candidate.proton = wine.WinePrefix, wine.Proton
+			}
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates
+}
+
+// getSteamLibraries returns Steam directory and libraries listed in its libraryfolders.vdf
+func getSteamLibraries(root string, prefix string) []string {
+	if !isDir(root) {
+		return nil
+	}
+
+	libraries := []string{root}
+	// Old versions of Steam keep the file in config directory
+	for _, file := range []string{filepath.Join(root, "steamapps", "libraryfolders.vdf"), filepath.Join(root, "config", "libraryfolders.vdf")} {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for _, library := range parseLibraryFolders(data) {
+			if prefix != "" {
+				library = FromWinePath(prefix, library)
+			}
+			if !IsInSlice(libraries, library) {
+				libraries = append(libraries, library)
+			}
+		}
+	}
+	return libraries
+}
+
+// parseLibraryFolders returns library paths of libraryfolders.vdf
+func parseLibraryFolders(data []byte) []string {
+	var libraries []string
+	for _, match := range libraryFolderPattern.FindAllSubmatch(data, -1) {
+		library := strings.NewReplacer(`\\`, `\`, `\"`, `"`).Replace(string(match[2]))
+		// Numbered values are also used for sizes of apps, paths contain separators
+		if string(match[1]) != "path" && !strings.ContainsAny(library, `/\`) {
+			continue
+		}
+		libraries = append(libraries, library)
+	}
+	return libraries
+}
+
+// getDefaultCandidates returns default directories of installers on the drive
+func getDefaultCandidates(drive string, prefix string) []installCandidate {
+	candidates := make([]installCandidate, 0, len(defaultInstallDirs))
+	for _, dir := range defaultInstallDirs {
+		candidates = append(candidates, installCandidate{dir: filepath.Join(drive, dir.dir), source: dir.source, prefix: prefix})
+	}
+	return candidates
+}
+
+// getWindowsDrives returns roots of existing drives
+func getWindowsDrives() []string {
+	var drives []string
+	for letter := 'C'; letter <= 'Z'; letter++ {
+		if drive := string(letter) + ":\\"; isDir(drive) {
+			drives = append(drives, drive)
+		}
+	}
+	return drives
+}
+
+// detectInstalls returns games of the directory: Complete edition if it contains the original game with both expansions
+// in subdirectories, or each edition found separately
+func detectInstalls(candidate installCandidate) []GameInstall {
+	base := candidate.dir
+	// findExe returns a directory of the executable: the subdirectory of expansion or the directory itself
+	findExe := func(exe string, subdir string) string {
+		if subdir != "" && isFile(filepath.Join(base, subdir, exe)) {
+			return filepath.Join(base, subdir)
+		}
+		if isFile(filepath.Join(base, exe)) {
+			return base
+		}
+		return ""
+	}
+
+	vanilla, warlords, bts := findExe(VanillaExe, ""), findExe(WarlordsExe, WarlordsDir), findExe(BtsExe, BtsDir)
+	newInstall := func(dir, exe, edition string) GameInstall {
+		return GameInstall{
+			Dir:        dir,
+			Edition:    edition,
+			Version:    GetExeVersion(filepath.Join(dir, exe)),
+			Source:     candidate.source,
+			WinePrefix: candidate.prefix,
+			Proton:     candidate.proton,
+			Supported:  exe == BtsExe,
+		}
+	}
+
+	if vanilla != "" && warlords != "" && bts != "" && warlords != base && bts != base {
+		return []GameInstall{newInstall(bts, BtsExe, EditionComplete)}
+	}
+
+	var installs []GameInstall
+	if bts != "" {
+		installs = append(installs, newInstall(bts, BtsExe, EditionBts))
+	}
+	if warlords != "" {
+		installs = append(installs, newInstall(warlords, WarlordsExe, EditionWarlords))
+	}
+	if vanilla != "" {
+		installs = append(installs, newInstall(vanilla, VanillaExe, EditionVanilla))
+	}
+	return installs
+}
+
+// GetExeVersion returns file version of Windows executable (VS_FIXEDFILEINFO of its version resource),
+// or empty string if it's not found
+func GetExeVersion(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	// VS_FIXEDFILEINFO starts with signature and structure version 1.0, followed by file version
+	signature := []byte{0xBD, 0x04, 0xEF, 0xFE, 0x00, 0x00, 0x01, 0x00}
+	i := bytes.Index(data, signature)
+	if i < 0 || i+16 > len(data) {
+		return ""
+	}
+
+	major := binary.LittleEndian.Uint32(data[i+8:])
+	minor := binary.LittleEndian.Uint32(data[i+12:])
+	return fmt.Sprintf("%d.%d.%d.%d", major>>16, major&0xFFFF, minor>>16, minor&0xFFFF)
+}
@@ -0,0 +1,124 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeTestExe writes a fake executable with version resource of the version (major, minor, build, revision)
+func writeTestExe(t *testing.T, path string, version ...uint16) {
+	data := []byte("MZ fake executable")
+	if len(version) == 4 {
+		data = append(data, 0xBD, 0x04, 0xEF, 0xFE, 0x00, 0x00, 0x01, 0x00)
+		for _, part := range []uint16{version[1], version[0], version[3], version[2]} {
+			data = append(data, byte(part), byte(part>>8))
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetExeVersion(t *testing.T) {
+	dir := t.TempDir()
+	writeTestExe(t, filepath.Join(dir, "versioned.exe"), 3, 19, 0, 1)
+	writeTestExe(t, filepath.Join(dir, "plain.exe"))
+
+	if version := GetExeVersion(filepath.Join(dir, "versioned.exe")); version != "3.19.0.1" {
+		t.Errorf("Expected 3.19.0.1, got %s", version)
+	}
+	if version := GetExeVersion(filepath.Join(dir, "plain.exe")); version != "" {
+		t.Errorf("Expected no version, got %s", version)
+	}
+	if version := GetExeVersion(filepath.Join(dir, "missing.exe")); version != "" {
+		t.Errorf("Expected no version of missing file, got %s", version)
+	}
+}
+
+func TestParseLibraryFolders(t *testing.T) {
+	newFormat := `"libraryfolders"
+{
+	"0"
+	{
+		"path"		"C:\\Program Files (x86)\\Steam"
+		"apps"
+		{
+			"8800"		"1456248832"
+		}
+	}
+	"1"
+	{
+		"path"		"/mnt/games/SteamLibrary"
+	}
+}`
+	oldFormat := `"LibraryFolders"
+{
+	"TimeNextStatsReport"		"1234567890"
+	"1"		"D:\\SteamLibrary"
+}`
+
+	if libraries := parseLibraryFolders([]byte(newFormat)); !slices.Equal(libraries, []string{`C:\Program Files (x86)\Steam`, "/mnt/games/SteamLibrary"}) {
+		t.Errorf("Unexpected libraries of new format: %q", libraries)
+	}
+	if libraries := parseLibraryFolders([]byte(oldFormat)); !slices.Equal(libraries, []string{`D:\SteamLibrary`}) {
+		t.Errorf("Unexpected libraries of old format: %q", libraries)
+	}
+}
+
+func TestFindGameInstalls(t *testing.T) {
+	home := useTestHome(t)
+
+	// Steam with Complete edition, linked from ~/.steam/steam, and a library with the original game only
+	steam := filepath.Join(home, ".local", "share", "Steam")
+	complete := filepath.Join(steam, "steamapps", "common", "Sid Meier's Civilization IV Beyond the Sword")
+	writeTestExe(t, filepath.Join(complete, VanillaExe))
+	writeTestExe(t, filepath.Join(complete, WarlordsDir, WarlordsExe))
+	writeTestExe(t, filepath.Join(complete, BtsDir, BtsExe), 3, 19, 0, 0)
+	library := filepath.Join(home, "library")
+	writeTestExe(t, filepath.Join(library, "steamapps", "common", "Sid Meier's Civilization IV", VanillaExe))
+	vdf := `"libraryfolders" { "0" { "path" "` + steam + `" } "1" { "path" "` + library + `" } }`
+	if err := os.WriteFile(filepath.Join(steam, "steamapps", "libraryfolders.vdf"), []byte(vdf), 0644); err != nil {
+		t.Fatal(err)
+	}
+	makeDirs(t, home, ".steam")
+	if err := os.Symlink(steam, filepath.Join(home, ".steam", "steam")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Wine prefix with GOG release and Steam, whose library is on drive D:
+	prefix := filepath.Join(home, ".wine")
+	gog := filepath.Join(prefix, "drive_c", "GOG Games", "Civilization IV Complete", BtsDir)
+	writeTestExe(t, filepath.Join(gog, BtsExe))
+	wineSteam := filepath.Join(prefix, steamDirInPrefix)
+	makeDirs(t, wineSteam, "steamapps")
+	if err := os.WriteFile(filepath.Join(wineSteam, "steamapps", "libraryfolders.vdf"), []byte(`"1" "D:\\Games"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	makeDirs(t, prefix, "dosdevices")
+	if err := os.Symlink(filepath.Join(home, "drive_d"), filepath.Join(prefix, "dosdevices", "d:")); err != nil {
+		t.Fatal(err)
+	}
+	warlords := filepath.Join(home, "drive_d", "Games", "steamapps", "common", "Sid Meier's Civilization IV Warlords")
+	writeTestExe(t, filepath.Join(warlords, WarlordsExe))
+
+	expected := []GameInstall{
+		{Dir: filepath.Join(home, ".steam", "steam", "steamapps", "common", "Sid Meier's Civilization IV Beyond the Sword", BtsDir), Edition: EditionComplete, Version: "3.19.0.0", Source: InstallSteam, Supported: true},
+		{Dir: gog, Edition: EditionBts, Source: InstallGOG, WinePrefix: prefix, Supported: true},
+		{Dir: filepath.Join(library, "steamapps", "common", "Sid Meier's Civilization IV"), Edition: EditionVanilla, Source: InstallSteam},
+		{Dir: warlords, Edition: EditionWarlords, Source: InstallSteam, WinePrefix: prefix},
+	}
+	installs := FindGameInstalls()
+	if !slices.Equal(installs, expected) {
+		t.Errorf("Unexpected installs:\n%+v\nexpected:\n%+v", installs, expected)
+	}
+
+	if config := GetDefaultConfig(); config.GameDir != expected[0].Dir {
+		t.Errorf("Expected the first supported install in default config, got %s", config.GameDir)
+	}
+}
@@ -0,0 +1,325 @@
+package editor
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// LaunchMode is a way the game opens the map
+type LaunchMode string
+
+const (
+	// LaunchNewGame starts a new game on the map
+	LaunchNewGame LaunchMode = "game"
+	// LaunchWorldBuilder opens the map in WorldBuilder
+	LaunchWorldBuilder LaunchMode = "worldbuilder"
+)
+
+// DefaultLaunchProfile is a name of the profile used when no profiles are configured
+const DefaultLaunchProfile = "Default"
+
+// DefaultWineCommand runs Windows executables on other platforms if the profile doesn't set a wrapper
+const DefaultWineCommand = "wine"
+
+// UserGameDir is a directory of game settings and logs inside user documents
+var UserGameDir = filepath.Join("My Games", "Beyond the Sword")
+
+// loadMapArg is a game argument loading the map (or save) file
+const loadMapArg = "/FXSLOAD="
+
+// defaultWorldBuilderArgs are added to open the map in WorldBuilder if the profile doesn't set them
+var defaultWorldBuilderArgs = []string{"/WB"}
+
+// LaunchProfile is a named way to start the game, e.g. another executable, mod or Wine prefix
+type LaunchProfile struct {
+	Name string `json:"name"`
+	// Executable is a game executable, absolute or relative to game directory. Empty means BtsExe
+	Executable string `json:"executable"`
+	// Mod is a mod to load, empty means Config.Mod
+	Mod string `json:"mod"`
+	// Args are extra game arguments, each one is quoted if needed
+	Args []string `json:"args"`
+	// WorldBuilderArgs are added to open the map in WorldBuilder, empty means defaultWorldBuilderArgs
+	WorldBuilderArgs []string `json:"world_builder_args"`
+	// WorkDir is a working directory of the game, empty means game directory
+	WorkDir string `json:"work_dir"`
+	// Env are extra environment variables of the game
+	Env map[string]string `json:"env"`
+	// Wrapper is a command with arguments running Windows executable on other platforms, e.g. ["gamemoderun", "wine"].
+	// Empty means "proton run" if Proton is set, otherwise DefaultWineCommand
+	Wrapper []string `json:"wrapper"`
+	// WinePrefix is a Wine prefix the game is installed to (for Proton, "pfx" directory in Steam compatdata).
+	// Empty means the default one
+	WinePrefix string `json:"wine_prefix"`
+	// Proton is a directory of Steam Proton running the game instead of Wine
+	Proton string `json:"proton"`
+	// LogsDir is a directory of game logs tailed while the game runs, empty means the one in user documents (see GetLogsDir)
+	LogsDir string `json:"logs_dir"`
+	// Elevated runs the game as administrator on Windows. Elevated game is not supervised (its exit and logs
+	// are not tracked, see GameStatus.Unsupervised) and Env is not passed to it
+	Elevated bool `json:"elevated"`
+}
+
+// gameArg is a game argument, value is quoted for Windows command line after the prefix (e.g. /FXSLOAD="C:\My Maps\a.wbs")
+type gameArg struct {
+	prefix string
+	value  string
+}
+
+// GetLaunchProfiles returns configured launch profiles, or the default profile if there are none
+func (c *Config) GetLaunchProfiles() []LaunchProfile {
+	if len(c.LaunchProfiles) == 0 {
+		return []LaunchProfile{c.getDefaultLaunchProfile()}
+	}
+	return c.LaunchProfiles
+}
+
+// GetLaunchProfile returns the profile by name, empty name means the selected profile (Config.LaunchProfile)
+func (c *Config) GetLaunchProfile(name string) (LaunchProfile, error) {
+	if name == "" {
+		name = c.LaunchProfile
+	}
+
+	profiles := c.GetLaunchProfiles()
+	if name == "" {
+		return profiles[0], nil
+	}
+	for _, profile := range profiles {
+		if profile.Name == name {
+			return profile, nil
+		}
+	}
+	return LaunchProfile{}, errors.New("launch profile " + name + " not found")
+}
+
+// getDefaultLaunchProfile returns a profile starting BtsExe with the configured mod. It's not elevated, so the game
+// is supervised; elevation is enabled in configured profiles. On other platforms the game is run with Wine or Proton
+// of its directory (see DetectWine)
+func (c *Config) getDefaultLaunchProfile() LaunchProfile {
+	profile := LaunchProfile{Name: DefaultLaunchProfile}
+	if runtime.GOOS != "windows" && c.GameDir != "" {
+		install := DetectWine(c.GameDir)
+		profile.WinePrefix, profile.Proton = install.WinePrefix, install.Proton
+	}
+	return profile
+}
+
+// GetExecutable returns an absolute path to the game executable of the profile
+func (p *LaunchProfile) GetExecutable(gameDir string) string {
+	exe := p.Executable
+	if exe == "" {
+		exe = BtsExe
+	}
+	if !filepath.IsAbs(exe) {
+		exe = filepath.Join(gameDir, exe)
+	}
+	return exe
+}
+
+// GetCommand returns a command starting the game of the directory with the map (empty path starts the game as is).
+// Profile mod replaces the given mod
+func (p *LaunchProfile) GetCommand(gameDir, mod, mapPath string, mode LaunchMode) *exec.Cmd {
+	return p.getCommand(runtime.GOOS, gameDir, mod, mapPath, mode)
+}
+
+func (p *LaunchProfile) getCommand(goos, gameDir, mod, mapPath string, mode LaunchMode) *exec.Cmd {
+	exe := p.GetExecutable(gameDir)
+	wine := goos != "windows" && strings.EqualFold(filepath.Ext(exe), ".exe")
+	if wine && mapPath != "" {
+		mapPath = ToWinePath(p.WinePrefix, mapPath)
+	}
+	args := p.getGameArgs(mod, mapPath, mode)
+
+	var cmd *exec.Cmd
+	if goos == "windows" {
+		cmd = exec.Command(exe)
+		setCommandLine(cmd, getWindowsCommandLine(exe, args))
+	} else if wine {
+		wrapper := p.getWrapper()
+		cmd = exec.Command(wrapper[0], append(append(wrapper[1:], exe), joinGameArgs(args)...)...)
+	} else {
+		cmd = exec.Command(exe, joinGameArgs(args)...)
+	}
+
+	cmd.Dir = p.WorkDir
+	if cmd.Dir == "" {
+		cmd.Dir = gameDir
+	}
+
+	cmd.Env = os.Environ()
+	if wine {
+		cmd.Env = append(cmd.Env, p.getWineEnv()...)
+	}
+	for _, key := range SortKeys(p.Env) {
+		cmd.Env = append(cmd.Env, key+"="+p.Env[key])
+	}
+	return cmd
+}
+
+// getWrapper returns a command with arguments running Windows executable
+func (p *LaunchProfile) getWrapper() []string {
+	if len(p.Wrapper) > 0 {
+		return slices.Clone(p.Wrapper)
+	}
+	if p.Proton != "" {
+		return []string{filepath.Join(p.Proton, "proton"), "run"}
+	}
+	return []string{DefaultWineCommand}
+}
+
+// getWineEnv returns environment variables selecting Wine prefix, Proton needs Steam directories instead
+func (p *LaunchProfile) getWineEnv() []string {
+	if p.Proton == "" {
+		if p.WinePrefix == "" {
+			return nil
+		}
+		return []string{"WINEPREFIX=" + p.WinePrefix}
+	}
+
+	var env []string
+	if p.WinePrefix != "" {
+		compatData := p.WinePrefix
+		if filepath.Base(compatData) == "pfx" {
+			compatData = filepath.Dir(compatData)
+		}
+		env = append(env, "STEAM_COMPAT_DATA_PATH="+compatData)
+	}
+	// Proton is installed as a Steam game: <steam>/steamapps/common/<proton>
+	steam := filepath.Dir(filepath.Dir(filepath.Dir(p.Proton)))
+	return append(env, "STEAM_COMPAT_CLIENT_INSTALL_PATH="+steam)
+}
+
+// GetLogsDir returns a directory of game logs, they are written if LoggingEnabled is set in the game ini.
+// Returns empty string if the directory is unknown
+func (p *LaunchProfile) GetLogsDir() string {
+	if p.LogsDir != "" {
+		return p.LogsDir
+	}
+
+	if runtime.GOOS == "windows" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		return filepath.Join(home, "Documents", UserGameDir, "Logs")
+	}
+
+	prefix := p.WinePrefix
+	if prefix == "" {
+		prefix = getDefaultWinePrefix()
+	}
+	// Old Wine versions name the documents directory "My Documents"
+	for _, documents := range []string{"Documents", "My Documents"} {
+		pattern := filepath.Join(prefix, "drive_c", "users", "*", documents, UserGameDir, "Logs")
+		if dirs, _ := filepath.Glob(pattern); len(dirs) > 0 {
+			return dirs[0]
+		}
+	}
+	return ""
+}
+
+// getGameArgs returns arguments loading the mod and the map, followed by extra arguments of the profile
+func (p *LaunchProfile) getGameArgs(mod, mapPath string, mode LaunchMode) []gameArg {
+	var args []gameArg
+	if p.Mod != "" {
+		mod = p.Mod
+	}
+	if mod != "" {
+		// The game expects mod name as a separate argument: mod= "My Mod"
+		args = append(args, gameArg{value: "mod="}, gameArg{value: mod})
+	}
+
+	if mapPath != "" {
+		args = append(args, gameArg{prefix: loadMapArg, value: mapPath})
+		if mode == LaunchWorldBuilder {
+			worldBuilderArgs := p.WorldBuilderArgs
+			if len(worldBuilderArgs) == 0 {
+				worldBuilderArgs = defaultWorldBuilderArgs
+			}
+			for _, arg := range worldBuilderArgs {
+				args = append(args, gameArg{value: arg})
+			}
+		}
+	}
+
+	for _, arg := range p.Args {
+		args = append(args, gameArg{value: arg})
+	}
+	return args
+}
+
+// joinGameArgs returns arguments as argv, quoting is left to the started program (e.g. Wine)
+func joinGameArgs(args []gameArg) []string {
+	argv := make([]string, len(args))
+	for i, arg := range args {
+		argv[i] = arg.prefix + arg.value
+	}
+	return argv
+}
+
+// getWindowsArgs returns arguments quoted for Windows command line
+func getWindowsArgs(args []gameArg) []string {
+	argv := make([]string, len(args))
+	for i, arg := range args {
+		argv[i] = arg.prefix + quoteWindowsArg(arg.value)
+	}
+	return argv
+}
+
+// getWindowsCommandLine returns Windows command line of the executable, it's always quoted
+func getWindowsCommandLine(exe string, args []gameArg) string {
+	return strings.Join(append([]string{"\"" + exe + "\""}, getWindowsArgs(args)...), " ")
+}
+
+// quoteWindowsArg quotes the argument if it's empty or contains spaces or quotes, the same way CommandLineToArgvW parses it
+func quoteWindowsArg(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\"") {
+		return arg
+	}
+
+	quoted := strings.Builder{}
+	quoted.WriteByte('"')
+	slashes := 0
+	for _, c := range arg {
+		switch c {
+		case '\\':
+			slashes++
+		case '"':
+			// Backslashes before a quote are escaped, as well as the quote
+			quoted.WriteString(strings.Repeat("\\", slashes+1))
+			slashes = 0
+		default:
+			slashes = 0
+		}
+		quoted.WriteRune(c)
+	}
+	// Trailing backslashes would escape the closing quote
+	quoted.WriteString(strings.Repeat("\\", slashes))
+	quoted.WriteByte('"')
+	return quoted.String()
+}
+
+// LaunchGame starts the game of current configuration using the profile and supervises it (see GetGameStatus).
+// Empty map path starts the game as is
+func LaunchGame(profile LaunchProfile, mapFileName string, mode LaunchMode) error {
+	if err := CheckGameDirectory(GlobalConfig.GameDir); err != nil {
+		return err
+	}
+
+	if mapFileName != "" {
+		if absPath, err := filepath.Abs(mapFileName); err == nil {
+			mapFileName = absPath
+		}
+	}
+
+	if runtime.GOOS == "windows" && profile.Elevated {
+		return games.startElevated(profile, GlobalConfig.GameDir, GlobalConfig.Mod, mapFileName, mode)
+	}
+
+	return games.start(profile, GlobalConfig.GameDir, GlobalConfig.Mod, mapFileName, mode)
+}
@@ -0,0 +1,97 @@
+package editor
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestQuoteWindowsArg(t *testing.T) {
+	tests := map[string]string{
+		"mod=":               "mod=",
+		"":                   `""`,
+		"My Mod":             `"My Mod"`,
+		`C:\My Maps\a.wbs`:   `"C:\My Maps\a.wbs"`,
+		`C:\My Maps\`:        `"C:\My Maps\\"`,
+		`say "hi"`:           `"say \"hi\""`,
+		`a\"b c`:             `"a\\\"b c"`,
+		`C:\NoSpaces\a.wbs`:  `C:\NoSpaces\a.wbs`,
+		"tab\tseparated arg": "\"tab\tseparated arg\"",
+	}
+	for arg, expected := range tests {
+		if quoted := quoteWindowsArg(arg); quoted != expected {
+			t.Errorf("quoteWindowsArg(%s) = %s, expected %s", arg, quoted, expected)
+		}
+	}
+}
+
+func TestGetWindowsCommandLine(t *testing.T) {
+	profile := LaunchProfile{Args: []string{"/ALTROOT=C:\\Civ 4"}}
+	args := profile.getGameArgs("My Mod", `C:\My Maps\test.CivBeyondSwordWBSave`, LaunchWorldBuilder)
+	line := getWindowsCommandLine(`C:\Games\Civ4\Civ4BeyondSword.exe`, args)
+	expected := `"C:\Games\Civ4\Civ4BeyondSword.exe" mod= "My Mod" /FXSLOAD="C:\My Maps\test.CivBeyondSwordWBSave" /WB "/ALTROOT=C:\Civ 4"`
+	if line != expected {
+		t.Errorf("Unexpected command line:\n%s\nexpected:\n%s", line, expected)
+	}
+
+	profile = LaunchProfile{Mod: "Other", WorldBuilderArgs: []string{"-wb"}}
+	line = getWindowsCommandLine("Civ4.exe", profile.getGameArgs("My Mod", "", LaunchWorldBuilder))
+	if line != `"Civ4.exe" mod= Other` {
+		t.Errorf("Profile mod must replace configured one and WorldBuilder needs a map: %s", line)
+	}
+}
+
+func TestLaunchProfileCommand(t *testing.T) {
+	gameDir := filepath.Join(t.TempDir(), "Civ4")
+	profile := LaunchProfile{
+		WinePrefix: "/home/user/.wine-civ4",
+		Env:        map[string]string{"WINEDEBUG": "-all", "A": "1"},
+	}
+
+	cmd := profile.getCommand("linux", gameDir, "My Mod", "/maps/test.wbs", LaunchNewGame)
+	expectedArgs := []string{DefaultWineCommand, filepath.Join(gameDir, BtsExe), "mod=", "My Mod", `/FXSLOAD=Z:\maps\test.wbs`}
+	if !slices.Equal(cmd.Args, expectedArgs) {
+		t.Errorf("Unexpected args %q", cmd.Args)
+	}
+	if cmd.Dir != gameDir {
+		t.Errorf("Expected game directory as working one, got %s", cmd.Dir)
+	}
+	env := cmd.Env[len(cmd.Env)-3:]
+	if !slices.Equal(env, []string{"WINEPREFIX=/home/user/.wine-civ4", "A=1", "WINEDEBUG=-all"}) {
+		t.Errorf("Unexpected environment %q", env)
+	}
+
+	profile = LaunchProfile{Executable: "/usr/bin/civ4", WorkDir: "/tmp", Wrapper: []string{"wine64"}}
+	cmd = profile.getCommand("linux", gameDir, "", "", LaunchNewGame)
+	if !slices.Equal(cmd.Args, []string{"/usr/bin/civ4"}) || cmd.Dir != "/tmp" {
+		t.Errorf("Native executable must be started without Wine: %q in %s", cmd.Args, cmd.Dir)
+	}
+
+	profile = LaunchProfile{Executable: "Civ4Warlords.exe", Wrapper: []string{"wine64"}}
+	cmd = profile.getCommand("windows", gameDir, "", "", LaunchNewGame)
+	if cmd.Args[0] != filepath.Join(gameDir, "Civ4Warlords.exe") {
+		t.Errorf("Windows executable must be started directly: %q", cmd.Args)
+	}
+}
+
+func TestGetLaunchProfile(t *testing.T) {
+	config := &Config{}
+	if profile, err := config.GetLaunchProfile(""); err != nil || profile.Name != DefaultLaunchProfile || profile.Elevated {
+		t.Errorf("Expected default profile, got %+v, %v", profile, err)
+	}
+
+	config.LaunchProfiles = []LaunchProfile{{Name: "Wine"}, {Name: "Mod"}}
+	if profile, _ := config.GetLaunchProfile(""); profile.Name != "Wine" {
+		t.Errorf("Expected first profile, got %s", profile.Name)
+	}
+	config.LaunchProfile = "Mod"
+	if profile, _ := config.GetLaunchProfile(""); profile.Name != "Mod" {
+		t.Errorf("Expected selected profile, got %s", profile.Name)
+	}
+	if profile, _ := config.GetLaunchProfile("Wine"); profile.Name != "Wine" {
+		t.Errorf("Expected requested profile, got %s", profile.Name)
+	}
+	if _, err := config.GetLaunchProfile(DefaultLaunchProfile); err == nil {
+		t.Error("Default profile must not be used when profiles are configured")
+	}
+}
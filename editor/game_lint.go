@@ -0,0 +1,224 @@
+package editor
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// TxtKeyPrefix is a prefix of game text keys
+const TxtKeyPrefix = "TXT_KEY_"
+
+// typeReferenceRegex matches values that look like a type (e.g. TECH_WHEEL), they are checked if their prefix is known
+var typeReferenceRegex = regexp.MustCompile(`^([A-Z0-9]+_)[A-Z0-9_]+$`)
+
+// LintIssueKind is a kind of problem found by linter
+type LintIssueKind string
+
+const (
+	// LintMissingText means text key (TXT_KEY_*) has no text in any language
+	LintMissingText LintIssueKind = "missing_text"
+	// LintBrokenReference means type is referenced but not defined in any loaded file
+	LintBrokenReference LintIssueKind = "broken_reference"
+)
+
+// LintIssue is a problem found in XML file
+type LintIssue struct {
+	Path string        `json:"path"`
+	Line int           `json:"line"`
+	Kind LintIssueKind `json:"kind"`
+	// Element is the tag containing the value (e.g. PrereqTech)
+	Element string `json:"element"`
+	Value   string `json:"value"`
+}
+
+// String returns issue in "path:line: message" format, like compilers do
+func (i *LintIssue) String() string {
+	message := "unknown type " + i.Value
+	if i.Kind == LintMissingText {
+		message = "missing text " + i.Value
+	}
+
+	return fmt.Sprintf("%s:%d: %s in <%s>", i.Path, i.Line, message, i.Element)
+}
+
+// LintReport is a result of checking XML files (see LintGameData)
+type LintReport struct {
+	// Files is a number of checked files
+	Files  int          `json:"files"`
+	Issues []*LintIssue `json:"issues"`
+	// Errors are files that cannot be checked
+	Errors []string `json:"errors"`
+}
+
+// GetFileIssues returns issues grouped by file path
+func (r *LintReport) GetFileIssues() map[string][]*LintIssue {
+	files := make(map[string][]*LintIssue)
+	for _, issue := range r.Issues {
+		files[issue.Path] = append(files[issue.Path], issue)
+	}
+	return files
+}
+
+// String returns report as text: issues grouped by file and a summary line
+func (r *LintReport) String() string {
+	builder := strings.Builder{}
+	files := r.GetFileIssues()
+	for _, path := range SortKeys(files) {
+		for _, issue := range files[path] {
+			builder.WriteString(issue.String() + "\n")
+		}
+	}
+	for _, e := range r.Errors {
+		builder.WriteString(e + "\n")
+	}
+
+	builder.WriteString(fmt.Sprintf("Checked %d files: %d issues in %d files, %d errors\n", r.Files, len(r.Issues), len(files), len(r.Errors)))
+	return builder.String()
+}
+
+// RunLintMod loads game data for the configured game directory and mod, checks it and writes the report to output.
+// Returns process exit code: 0 if no issues found, 1 if there are issues, 2 if game data cannot be loaded
+func RunLintMod(output io.Writer) int {
+	config, _ := GetConfig()
+	GlobalConfig = &config
+
+	data, err := LoadGameData(context.Background(), config.GameDir, config.Mod)
+	if err != nil {
+		_, _ = fmt.Fprintln(output, err.Error())
+		return 2
+	}
+
+	loadReport := data.Registries().XmlLoadReport
+	for _, file := range loadReport.GetFiles(XmlFileFailed) {
+		_, _ = fmt.Fprintf(output, "%s: cannot parse: %s\n", file.Path, file.Reason)
+	}
+
+	report := LintGameData(data)
+	_, _ = fmt.Fprint(output, report.String())
+	if len(report.Issues) > 0 || len(report.Errors) > 0 || len(loadReport.GetFiles(XmlFileFailed)) > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+// LintGameData checks XML files of the mod (or all files if mod is not set) for text keys without texts
+// and references to types which are not defined. Only successfully loaded files are checked (see XmlLoadReport).
+func LintGameData(data *GameData) *LintReport {
+	r := data.Registries()
+	report := &LintReport{}
+
+	modDir := ""
+	if data != nil && data.Mod != "" {
+		modDir = filepath.Join(data.GameDir, ModsDir, data.Mod) + string(os.PathSeparator)
+	}
+
+	// Type prefixes (e.g. TECH_) are taken from loaded types, so values like UNITAI_* which are not loaded are not reported
+	prefixes := make(map[string]bool)
+	for t := range r.XmlTypeSources {
+		if matches := typeReferenceRegex.FindStringSubmatch(t); matches != nil {
+			prefixes[matches[1]] = true
+		}
+	}
+
+	for _, file := range r.XmlLoadReport.GetFiles(XmlFileParsed) {
+		if file.XmlType == "Civ4GameText" || (modDir != "" && !strings.HasPrefix(file.Path, modDir)) {
+			continue
+		}
+
+		issues, err := lintXMLFile(r, file.Path, prefixes)
+		if err != nil {
+			report.Errors = append(report.Errors, file.Path+": "+err.Error())
+			continue
+		}
+
+		report.Files++
+		report.Issues = append(report.Issues, issues...)
+	}
+
+	return report
+}
+
+// HasLangString returns true if the text key has a text in any language
+func (r *GameRegistries) HasLangString(key string) bool {
+	for _, texts := range r.LangStrings {
+		if _, ok := texts[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// lintXMLFile checks text values of all elements in the file. Type elements are definitions, not references, so they are skipped
+func lintXMLFile(r *GameRegistries, path string, prefixes map[string]bool) ([]*LintIssue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder, _ := ParseXMLFromBytes(data)
+	var issues []*LintIssue
+	var element string
+	var line int
+	var value bytes.Buffer
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return issues, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			element = t.Name.Local
+			line, _ = decoder.InputPos()
+			value.Reset()
+		case xml.CharData:
+			value.Write(t)
+		case xml.EndElement:
+			if t.Name.Local != element || element == "Type" {
+				element = ""
+				continue
+			}
+
+			if issue := lintValue(r, strings.TrimSpace(value.String()), prefixes); issue != nil {
+				issue.Path, issue.Line, issue.Element = path, line, element
+				issues = append(issues, issue)
+			}
+			element = ""
+		}
+	}
+
+	return issues, nil
+}
+
+// lintValue returns an issue if the value is a text key without text or a reference to unknown type
+func lintValue(r *GameRegistries, value string, prefixes map[string]bool) *LintIssue {
+	if strings.HasPrefix(value, TxtKeyPrefix) {
+		if !r.HasLangString(value) {
+			return &LintIssue{Kind: LintMissingText, Value: value}
+		}
+		return nil
+	}
+
+	matches := typeReferenceRegex.FindStringSubmatch(value)
+	if matches == nil || !prefixes[matches[1]] {
+		return nil
+	}
+
+	if _, ok := r.XmlTypeSources[value]; !ok {
+		return &LintIssue{Kind: LintBrokenReference, Value: value}
+	}
+	return nil
+}
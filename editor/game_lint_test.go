@@ -0,0 +1,52 @@
+package editor
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestLintGameData(t *testing.T) {
+	root := t.TempDir()
+	modTechXml := strings.ReplaceAll(testTechXml, "<Type>TECH_TEST_WHEEL</Type>", "<Type>TECH_TEST_AGRICULTURE</Type>")
+	modTechXml = strings.ReplaceAll(modTechXml, "TXT_KEY_TECH_THE_WHEEL", "TXT_KEY_TEST_ROME")
+	writeTestFiles(t, root, map[string]string{
+		"Assets/XML/Technologies/CIV4TechInfos.xml":               testTechXml,
+		"Assets/XML/Text/CIV4GameText_Test.xml":                   testGameTextXml,
+		"Mods/Test/Assets/XML/Technologies/CIV4TechInfos_Mod.xml": modTechXml,
+	})
+
+	useTestXmlCache(t, "")
+
+	// Base game: text of the wheel and both prerequisites are missing
+	data, err := LoadGameData(context.Background(), root, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := LintGameData(data)
+	if report.Files != 1 || len(report.Issues) != 3 {
+		t.Fatalf("Unexpected base game report: %s", report)
+	}
+
+	issue := report.Issues[0]
+	if issue.Kind != LintMissingText || issue.Line != 6 || issue.Element != "Description" || issue.Value != "TXT_KEY_TECH_THE_WHEEL" {
+		t.Errorf("Unexpected issue %s", issue)
+	}
+
+	// Mod defines agriculture, only its own file is checked
+	data, err = LoadGameData(context.Background(), root, "Test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report = LintGameData(data)
+	if report.Files != 1 || len(report.Issues) != 1 {
+		t.Fatalf("Unexpected mod report: %s", report)
+	}
+
+	issue = report.Issues[0]
+	if issue.Kind != LintBrokenReference || issue.Line != 11 || issue.Value != "TECH_TEST_HUNTING" || !strings.HasSuffix(issue.Path, "CIV4TechInfos_Mod.xml") {
+		t.Errorf("Unexpected issue %s", issue)
+	}
+}
@@ -0,0 +1,376 @@
+package editor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GameLogLines is a number of the latest game log lines attached to a crash report
+const GameLogLines = 30
+
+// gameLogInterval is how often game logs are checked for new lines
+var gameLogInterval = 500 * time.Millisecond
+
+// gameWaitDelay is how long to wait for game output after the game exits. Wine children inherit stdout and stderr
+// and may keep them open, so without a limit the game would be reported as running until they exit
+const gameWaitDelay = 5 * time.Second
+
+// errGameNotRunning is returned by KillGame if the game has already exited
+var errGameNotRunning = errors.New("game is not running")
+
+// gameOutputSource is a source of lines written by the game to stdout and stderr (e.g. Wine messages)
+const gameOutputSource = "output"
+
+// GameStatus is a state of the game started by LaunchGame, reported to listeners (see SubscribeGame)
+type GameStatus struct {
+	Running bool       `json:"running"`
+	Profile string     `json:"profile"`
+	MapPath string     `json:"map_path"`
+	Mode    LaunchMode `json:"mode"`
+	PID     int        `json:"pid"`
+	// ExitCode is set when the game is over, -1 means it was terminated by a signal
+	ExitCode int `json:"exit_code"`
+	// Killed is true if the game was stopped by KillGame or RestartGame
+	Killed bool `json:"killed"`
+	// Crashed is true if the game failed without being killed, LastLines are set then
+	Crashed   bool     `json:"crashed"`
+	LastLines []string `json:"last_lines"`
+	Error     string   `json:"error"`
+	// Unsupervised is true if the game was launched as administrator, its exit and logs are not tracked then
+	Unsupervised bool `json:"unsupervised"`
+}
+
+// gameListener is a listener of game status (see SubscribeGame)
+type gameListener struct {
+	id int
+	fn func(GameStatus)
+}
+
+// gameSupervisor tracks the game process, only one game runs at a time
+type gameSupervisor struct {
+	mu      sync.Mutex
+	status  GameStatus
+	profile LaunchProfile
+	process *os.Process
+	// done is closed when the process exits and its status is updated
+	done      chan struct{}
+	nextID    int
+	listeners []gameListener
+}
+
+var games = &gameSupervisor{}
+
+// GetGameStatus returns the state of the running game, or of the last one if it's over
+func GetGameStatus() GameStatus {
+	games.mu.Lock()
+	defer games.mu.Unlock()
+	status := games.status
+	status.LastLines = slices.Clone(status.LastLines)
+	return status
+}
+
+// SubscribeGame adds a listener of game status changes (start and exit).
+// The listener is called from the supervisor goroutine and must not block. Returns a function to unsubscribe
+func SubscribeGame(listener func(GameStatus)) func() {
+	games.mu.Lock()
+	defer games.mu.Unlock()
+	id := games.nextID
+	games.nextID++
+	games.listeners = append(games.listeners, gameListener{id: id, fn: listener})
+
+	return func() {
+		games.mu.Lock()
+		defer games.mu.Unlock()
+		games.listeners = slices.DeleteFunc(games.listeners, func(l gameListener) bool { return l.id == id })
+	}
+}
+
+// KillGame stops the running game with all processes it started (e.g. Wine or Proton wrapper and the game itself),
+// it's not reported as crashed
+func KillGame() error {
+	games.mu.Lock()
+	defer games.mu.Unlock()
+	if !games.status.Running {
+		return errGameNotRunning
+	}
+
+	games.status.Killed = true
+	return killProcessGroup(games.process)
+}
+
+// RestartGame stops the running game and starts it again with the same profile and map
+func RestartGame() error {
+	games.mu.Lock()
+	status, profile, done := games.status, games.profile, games.done
+	games.mu.Unlock()
+	if status.Unsupervised {
+		return errors.New("game launched as administrator can't be restarted")
+	}
+	if done == nil {
+		return errors.New("game was not started")
+	}
+
+	if status.Running {
+		// The game may exit after its status is read, then it's launched at once
+		if err := KillGame(); err != nil && !errors.Is(err, errGameNotRunning) {
+			return err
+		}
+		<-done
+	}
+	return LaunchGame(profile, status.MapPath, status.Mode)
+}
+
+// start starts the game of the directory and supervises it until exit
+func (s *gameSupervisor) start(profile LaunchProfile, gameDir, mod, mapPath string, mode LaunchMode) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status.Running {
+		return errors.New("game is already running")
+	}
+
+	cmd := profile.GetCommand(gameDir, mod, mapPath, mode)
+	logs := newGameLog(profile.GetLogsDir())
+	cmd.Stdout, cmd.Stderr = logs, logs
+	cmd.WaitDelay = gameWaitDelay
+	setProcessGroup(cmd)
+	Logger.Info("Launching game", "profile", profile.Name, "command", cmd.String())
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	s.profile = profile
+	s.status = GameStatus{Running: true, Profile: profile.Name, MapPath: mapPath, Mode: mode, PID: cmd.Process.Pid}
+	s.process = cmd.Process
+	s.done = make(chan struct{})
+	s.notify()
+
+	go s.wait(cmd, logs, s.done)
+	return nil
+}
+
+// startElevated runs the game as administrator. Elevated process can't be waited for, so it's reported as unsupervised
+func (s *gameSupervisor) startElevated(profile LaunchProfile, gameDir, mod, mapPath string, mode LaunchMode) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status.Running {
+		return errors.New("game is already running")
+	}
+
+	dir := profile.WorkDir
+	if dir == "" {
+		dir = gameDir
+	}
+	args := getWindowsArgs(profile.getGameArgs(mod, mapPath, mode))
+	Logger.Warn("Launching game as administrator, it's not supervised and its logs are not captured", "profile", profile.Name)
+	if err := RunElevated(profile.GetExecutable(gameDir), args, dir); err != nil {
+		return err
+	}
+
+	s.profile = profile
+	s.status = GameStatus{Profile: profile.Name, MapPath: mapPath, Mode: mode, Unsupervised: true}
+	s.process, s.done = nil, nil
+	s.notify()
+	return nil
+}
+
+// wait tails game logs until the process exits, then reports its status
+func (s *gameSupervisor) wait(cmd *exec.Cmd, logs *gameLog, done chan struct{}) {
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(gameLogInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				logs.poll()
+			}
+		}
+	}()
+
+	err := cmd.Wait()
+	close(stop)
+	<-stopped
+	logs.poll()
+
+	s.mu.Lock()
+	s.status.Running = false
+	s.status.ExitCode = -1
+	if cmd.ProcessState != nil {
+		s.status.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		s.status.Error = err.Error()
+	}
+	s.status.Crashed = !s.status.Killed && (s.status.ExitCode != 0 || s.status.Error != "")
+	if s.status.Crashed {
+		s.status.LastLines = logs.getLastLines()
+	}
+	status := s.status
+	s.notify()
+	close(done)
+	s.mu.Unlock()
+
+	if status.Crashed {
+		Logger.Error("Game crashed", "code", status.ExitCode, "error", status.Error, "log", strings.Join(status.LastLines, "\n"))
+	} else {
+		Logger.Info("Game exited", "code", status.ExitCode, "killed", status.Killed)
+	}
+}
+
+// notify sends the current status to listeners, must be called with locked mutex
+func (s *gameSupervisor) notify() {
+	for _, listener := range s.listeners {
+		status := s.status
+		status.LastLines = slices.Clone(status.LastLines)
+		listener.fn(status)
+	}
+}
+
+// gameLog passes game output and new lines of game log files to Logger, keeping the latest lines for crash reports
+type gameLog struct {
+	mu    sync.Mutex
+	dir   string
+	files map[string]*gameLogFile
+	// output is an incomplete line of game output
+	output string
+	last   []string
+}
+
+// gameLogFile is a read state of a game log file
+type gameLogFile struct {
+	offset  int64
+	modTime time.Time
+	// rewritten is true when the file is changed after the game start, the game rewrites its logs instead of appending
+	rewritten bool
+	partial   string
+}
+
+// newGameLog remembers existing log files of the directory, so only lines written by the started game are read
+func newGameLog(dir string) *gameLog {
+	l := &gameLog{dir: dir, files: make(map[string]*gameLogFile)}
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if info, err := entry.Info(); err == nil && !entry.IsDir() {
+			l.files[entry.Name()] = &gameLogFile{offset: info.Size(), modTime: info.ModTime()}
+		}
+	}
+	return l
+}
+
+// Write passes game output to Logger line by line
+func (l *gameLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var lines []string
+	lines, l.output = splitLines(l.output, p)
+	for _, line := range lines {
+		l.addLine(gameOutputSource, line, slog.LevelDebug)
+	}
+	return len(p), nil
+}
+
+// poll reads new lines of log files, logs don't exist until logging is enabled in the game ini
+func (l *gameLog) poll() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.dir == "" {
+		return
+	}
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() {
+			continue
+		}
+
+		name := entry.Name()
+		file := l.files[name]
+		if file == nil {
+			file = &gameLogFile{rewritten: true}
+			l.files[name] = file
+		}
+		if (!file.rewritten && !info.ModTime().Equal(file.modTime)) || info.Size() < file.offset {
+			file.rewritten, file.offset, file.partial = true, 0, ""
+		}
+		if info.Size() == file.offset {
+			continue
+		}
+
+		data, err := readFileFrom(filepath.Join(l.dir, name), file.offset)
+		if err != nil {
+			continue
+		}
+		file.offset += int64(len(data))
+
+		var lines []string
+		lines, file.partial = splitLines(file.partial, data)
+		level := slog.LevelInfo
+		if strings.HasPrefix(strings.ToLower(name), "pythonerr") {
+			level = slog.LevelWarn
+		}
+		for _, line := range lines {
+			l.addLine(name, line, level)
+		}
+	}
+}
+
+// addLine logs the line of the source and keeps it for crash report, must be called with locked mutex
+func (l *gameLog) addLine(source, line string, level slog.Level) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" {
+		return
+	}
+
+	Logger.Log(context.Background(), level, line, "game_log", source)
+	l.last = append(l.last, source+": "+line)
+	if len(l.last) > GameLogLines {
+		l.last = l.last[len(l.last)-GameLogLines:]
+	}
+}
+
+// getLastLines returns the latest lines of game output and logs
+func (l *gameLog) getLastLines() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Clone(l.last)
+}
+
+// readFileFrom reads the file from the offset to the end
+func readFileFrom(path string, offset int64) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if _, err = file.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(file)
+}
+
+// splitLines appends data to the incomplete line and returns complete lines and the rest
+func splitLines(partial string, data []byte) ([]string, string) {
+	lines := strings.Split(partial+string(data), "\n")
+	return lines[:len(lines)-1], lines[len(lines)-1]
+}
@@ -0,0 +1,245 @@
+package editor
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// gameHelperEnv makes the test binary act as the game (see TestGameHelperProcess)
+const gameHelperEnv = "CIV4_STUDIO_GAME_HELPER"
+
+// TestGameHelperProcess is not a real test: started by a launch profile, it writes game logs and exits with a code
+func TestGameHelperProcess(t *testing.T) {
+	mode := os.Getenv(gameHelperEnv)
+	if mode == "" {
+		return
+	}
+
+	logsDir := os.Getenv("LOGS_DIR")
+	_ = os.WriteFile(filepath.Join(logsDir, "xml.log"), []byte("Loading XML\nLoaded\n"), 0644)
+	_ = os.WriteFile(filepath.Join(logsDir, "PythonErr.log"), []byte("Traceback (most recent call last):\nNameError\n"), 0644)
+	println("fixme: wine output")
+
+	// Wrapper starts the game as a child sharing its output, like Wine or Proton does
+	if mode == "wrapper" {
+		cmd := exec.Command(os.Args[0], os.Args[1:]...)
+		cmd.Env = append(os.Environ(), gameHelperEnv+"=sleep")
+		cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+		if cmd.Start() != nil {
+			os.Exit(1)
+		}
+		_ = os.WriteFile(filepath.Join(filepath.Dir(logsDir), "child.pid"), []byte(strconv.Itoa(cmd.Process.Pid)), 0644)
+		mode = "sleep"
+	}
+	if mode == "sleep" {
+		time.Sleep(time.Minute)
+	}
+	code, _ := strconv.Atoi(mode)
+	os.Exit(code)
+}
+
+// startTestGame launches the test binary as the game in the mode of TestGameHelperProcess
+func startTestGame(t *testing.T, mode string) <-chan GameStatus {
+	root := t.TempDir()
+	for _, dir := range []string{AssetsDir, PublicMapsDir, "Logs"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, BtsExe), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	logsDir := filepath.Join(root, "Logs")
+	// Old log is left from the previous game, it must not be reported
+	if err := os.WriteFile(filepath.Join(logsDir, "xml.log"), []byte("Old line\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	oldConfig, oldInterval := GlobalConfig, gameLogInterval
+	GlobalConfig, gameLogInterval = &Config{GameDir: root}, 10*time.Millisecond
+	t.Cleanup(func() { GlobalConfig, gameLogInterval = oldConfig, oldInterval })
+
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile := LaunchProfile{
+		Name:       "Test",
+		Executable: exe,
+		Args:       []string{"-test.run=TestGameHelperProcess"},
+		Env:        map[string]string{gameHelperEnv: mode, "LOGS_DIR": logsDir},
+		LogsDir:    logsDir,
+	}
+
+	statuses := make(chan GameStatus, 10)
+	t.Cleanup(SubscribeGame(func(status GameStatus) { statuses <- status }))
+	if err = LaunchGame(profile, "", LaunchNewGame); err != nil {
+		t.Fatal(err)
+	}
+	if status := <-statuses; !status.Running || status.Profile != "Test" || status.PID == 0 {
+		t.Fatalf("Unexpected status on start: %+v", status)
+	}
+	return statuses
+}
+
+// waitGameExit returns the status of exited game
+func waitGameExit(t *testing.T, statuses <-chan GameStatus) GameStatus {
+	select {
+	case status := <-statuses:
+		if status.Running {
+			t.Fatalf("Expected game exit, got %+v", status)
+		}
+		return status
+	case <-time.After(10 * time.Second):
+		t.Fatal("Game didn't exit")
+	}
+	return GameStatus{}
+}
+
+func TestGameCrash(t *testing.T) {
+	statuses := startTestGame(t, "3")
+
+	status := waitGameExit(t, statuses)
+	if !status.Crashed || status.ExitCode != 3 || status.Killed {
+		t.Fatalf("Expected crash with code 3, got %+v", status)
+	}
+	lines := strings.Join(status.LastLines, "\n")
+	for _, expected := range []string{"xml.log: Loading XML", "PythonErr.log: NameError", "output: fixme: wine output"} {
+		if !strings.Contains(lines, expected) {
+			t.Errorf("Expected %q in last lines:\n%s", expected, lines)
+		}
+	}
+	if strings.Contains(lines, "Old line") {
+		t.Errorf("Old log lines must be skipped:\n%s", lines)
+	}
+	if !slices.Equal(GetGameStatus().LastLines, status.LastLines) {
+		t.Error("GetGameStatus must return the last status")
+	}
+}
+
+func TestGameExit(t *testing.T) {
+	statuses := startTestGame(t, "0")
+	if status := waitGameExit(t, statuses); status.Crashed || status.ExitCode != 0 || len(status.LastLines) != 0 {
+		t.Fatalf("Expected normal exit, got %+v", status)
+	}
+	if KillGame() == nil {
+		t.Error("Exited game must not be killed")
+	}
+}
+
+func TestGameKillAndRestart(t *testing.T) {
+	statuses := startTestGame(t, "sleep")
+	if err := LaunchGame(games.profile, "", LaunchNewGame); err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Fatalf("Second game must not be started while the first one runs: %v", err)
+	}
+
+	if err := RestartGame(); err != nil {
+		t.Fatal(err)
+	}
+	if status := waitGameExit(t, statuses); !status.Killed || status.Crashed {
+		t.Fatalf("Killed game must not be reported as crashed: %+v", status)
+	}
+	if status := <-statuses; !status.Running || status.Killed {
+		t.Fatalf("Expected restarted game, got %+v", status)
+	}
+
+	if err := KillGame(); err != nil {
+		t.Fatal(err)
+	}
+	if status := waitGameExit(t, statuses); !status.Killed || status.Crashed {
+		t.Fatalf("Killed game must not be reported as crashed: %+v", status)
+	}
+}
+
+func TestGameKillChildren(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The game is started without wrappers on Windows")
+	}
+	statuses := startTestGame(t, "wrapper")
+	pidPath := filepath.Join(GlobalConfig.GameDir, "child.pid")
+	var pid int
+	for i := 0; i < 100 && pid == 0; i++ {
+		time.Sleep(50 * time.Millisecond)
+		data, _ := os.ReadFile(pidPath)
+		pid, _ = strconv.Atoi(string(data))
+	}
+	if pid == 0 {
+		t.Fatal("Child process is not started")
+	}
+
+	// The game is reported as exited without waiting for the child holding its output
+	started := time.Now()
+	if err := KillGame(); err != nil {
+		t.Fatal(err)
+	}
+	if status := waitGameExit(t, statuses); !status.Killed || time.Since(started) >= gameWaitDelay {
+		t.Fatalf("Game must be killed at once: %+v", status)
+	}
+
+	child, _ := os.FindProcess(pid)
+	for i := 0; i < 100 && child.Signal(syscall.Signal(0)) == nil; i++ {
+		time.Sleep(50 * time.Millisecond)
+	}
+	if child.Signal(syscall.Signal(0)) == nil {
+		t.Error("Child process of the game must be killed")
+	}
+}
+
+func TestGameUnsupervised(t *testing.T) {
+	games.mu.Lock()
+	oldStatus := games.status
+	games.status = GameStatus{Profile: "Admin", Unsupervised: true}
+	games.mu.Unlock()
+	t.Cleanup(func() {
+		games.mu.Lock()
+		games.status = oldStatus
+		games.mu.Unlock()
+	})
+
+	// The game launched as administrator is not tracked, so it can't be killed or restarted
+	if err := RestartGame(); err == nil || !strings.Contains(err.Error(), "administrator") {
+		t.Errorf("Unsupervised game must not be restarted: %v", err)
+	}
+	if KillGame() == nil {
+		t.Error("Unsupervised game must not be killed")
+	}
+}
+
+func TestGameLogPoll(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "xml.log")
+	if err := os.WriteFile(path, []byte("old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	logs := newGameLog(dir)
+	logs.poll()
+	if len(logs.getLastLines()) != 0 {
+		t.Fatal("Existing lines must be skipped until the file is changed")
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	_, _ = file.WriteString("first\r\nsecond ")
+	// Changed file is read from the start, the game rewrites logs when started
+	_ = os.Chtimes(path, time.Now(), time.Now().Add(time.Second))
+	logs.poll()
+	_, _ = file.WriteString("part\n")
+	logs.poll()
+
+	expected := []string{"xml.log: old", "xml.log: first", "xml.log: second part"}
+	if lines := logs.getLastLines(); !slices.Equal(lines, expected) {
+		t.Errorf("Expected %q, got %q", expected, lines)
+	}
+}
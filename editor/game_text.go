@@ -0,0 +1,44 @@
+package editor
+
+// DefaultLanguage is used when language is not configured, and it's also the last fallback for any language
+const DefaultLanguage = "English"
+
+// GameLanguages is a list of languages supported by game text files (see Civ4GameText)
+var GameLanguages = []string{
+	"English", "French", "German", "Italian", "Spanish", "Polish", "Russian", "Czech", "Danish", "Greek", "Brazilian", "ChineseSimp",
+	"Korean", "Ukrainian", "Arabic", "Turkish", "Bulgarian", "Finnish", "Dutch", "Hungarian", "Japanese", "Portuguese", "Catalan", "ChineseTrad",
+}
+
+// LanguageFallbacks contains languages to try if the text is not translated to the selected language.
+// Mods are rarely translated to all languages, so it's better to show a close language than English
+var LanguageFallbacks = map[string][]string{
+	"Ukrainian":   {"Russian"},
+	"Bulgarian":   {"Russian"},
+	"Czech":       {"Polish"},
+	"Brazilian":   {"Portuguese", "Spanish"},
+	"Portuguese":  {"Brazilian", "Spanish"},
+	"Catalan":     {"Spanish", "French"},
+	"ChineseTrad": {"ChineseSimp"},
+	"ChineseSimp": {"ChineseTrad"},
+	"Danish":      {"German"},
+	"Dutch":       {"German"},
+}
+
+// GetLanguage returns the configured game text language
+func GetLanguage() string {
+	if GlobalConfig == nil || GlobalConfig.Language == "" {
+		return DefaultLanguage
+	}
+
+	return GlobalConfig.Language
+}
+
+// GetLanguageChain returns the language itself, its fallbacks and DefaultLanguage (without duplicates)
+func GetLanguageChain(language string) []string {
+	chain := []string{language}
+	for _, fallback := range LanguageFallbacks[language] {
+		chain = AddToSlice(chain, fallback)
+	}
+
+	return AddToSlice(chain, DefaultLanguage)
+}
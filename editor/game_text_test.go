@@ -0,0 +1,61 @@
+package editor
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+const testGameTextXml = `<?xml version="1.0"?>
+<Civ4GameText xmlns="http://www.firaxis.com">
+	<TEXT>
+		<Tag>TXT_KEY_TEST_ROME</Tag>
+		<English>Rome</English>
+		<French>
+			<Text>Rome</Text>
+			<Gender>Female</Gender>
+			<Plural>0</Plural>
+		</French>
+		<Russian>Рим</Russian>
+	</TEXT>
+</Civ4GameText>`
+
+func TestGameTextValue(t *testing.T) {
+	textStruct := &Civ4GameText{}
+	if err := xml.Unmarshal([]byte(testGameTextXml), textStruct); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(textStruct.TEXT) != 1 {
+		t.Fatal("Expected 1 text entry")
+	}
+
+	entry := textStruct.TEXT[0]
+	if entry.English.Text != "Rome" || entry.French.Text != "Rome" || entry.French.Gender != "Female" {
+		t.Errorf("Unexpected text entry: %+v", entry)
+	}
+
+	translations := entry.Translations()
+	if len(translations) != 3 || translations["Russian"] != "Рим" {
+		t.Errorf("Unexpected translations: %v", translations)
+	}
+}
+
+func TestGetLangStringFor(t *testing.T) {
+	r := NewGameRegistries()
+	r.LangStrings = map[string]map[string]string{
+		"English": {"TXT_KEY_TEST_ROME": "Rome", "TXT_KEY_TEST_ATHENS": "Athens"},
+		"Russian": {"TXT_KEY_TEST_ROME": "Рим"},
+	}
+
+	if r.GetLangStringFor("TXT_KEY_TEST_ROME", "Ukrainian") != "Рим" {
+		t.Error("Expected fallback from Ukrainian to Russian")
+	}
+
+	if r.GetLangStringFor("TEST_ATHENS", "Ukrainian") != "Athens" {
+		t.Error("Expected fallback to English without TXT_KEY_ prefix")
+	}
+
+	if r.GetLangStringFor("TXT_KEY_TEST_SPARTA", "English") != "TXT_KEY_TEST_SPARTA" {
+		t.Error("Expected key itself for unknown text")
+	}
+}
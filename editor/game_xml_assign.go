@@ -0,0 +1,614 @@
+package editor
+
+// This file contains functions converting raw XML structs (see game_xml_source_structs.go) to typed info registries.
+// Every function returns types of assigned entries, they are used for loading statistics and to track entry sources.
+
+// AssignTerrainInfos assigns all terrains from the Civ4TerrainInfos struct to the TerrainInfos registry.
+func (r *GameRegistries) AssignTerrainInfos(terrainStruct *Civ4TerrainInfos) []string {
+	var types []string
+	for _, terrain := range terrainStruct.TerrainInfos.TerrainInfo {
+		r.TerrainInfos[terrain.Type] = &TerrainInfo{
+			TypeInfo:         TypeInfo{Type: terrain.Type, Description: terrain.Description, Button: terrain.Button},
+			Yields:           ToIntSlice(terrain.Yields.IYield),
+			RiverYieldChange: ToIntSlice(terrain.RiverYieldChange.IYield),
+			HillsYieldChange: ToIntSlice(terrain.HillsYieldChange.IYield),
+			Water:            ToBool(terrain.BWater),
+			Impassable:       ToBool(terrain.BImpassable),
+			Found:            ToBool(terrain.BFound),
+			FoundCoast:       ToBool(terrain.BFoundCoast),
+			FoundFreshWater:  ToBool(terrain.BFoundFreshWater),
+			Movement:         ToInt(terrain.IMovement),
+			Defense:          ToInt(terrain.IDefense),
+		}
+		types = append(types, terrain.Type)
+	}
+
+	return types
+}
+
+// AssignFeatureInfos assigns all features from the Civ4FeatureInfos struct to the FeatureInfos registry.
+func (r *GameRegistries) AssignFeatureInfos(featureStruct *Civ4FeatureInfos) []string {
+	var types []string
+	for _, feature := range featureStruct.FeatureInfos.FeatureInfo {
+		info := &FeatureInfo{
+			TypeInfo:          TypeInfo{Type: feature.Type, Description: feature.Description, Help: feature.Help},
+			YieldChanges:      ToIntSlice(feature.YieldChanges.IYieldChange),
+			RiverYieldChange:  ToIntSlice(feature.RiverYieldChange.IYield),
+			HillsYieldChange:  ToIntSlice(feature.HillsYieldChange.IYield),
+			Movement:          ToInt(feature.IMovement),
+			HealthPercent:     ToInt(feature.IHealthPercent),
+			NoCoast:           ToBool(feature.BNoCoast),
+			NoRiver:           ToBool(feature.BNoRiver),
+			NoAdjacent:        ToBool(feature.BNoAdjacent),
+			RequiresFlatlands: ToBool(feature.BRequiresFlatlands),
+			RequiresRiver:     ToBool(feature.BRequiresRiver),
+			AddsFreshWater:    ToBool(feature.BAddsFreshWater),
+			Impassable:        ToBool(feature.BImpassable),
+			NoCity:            ToBool(feature.BNoCity),
+			NoImprovement:     ToBool(feature.BNoImprovement),
+		}
+
+		for _, terrain := range feature.TerrainBooleans.TerrainBoolean {
+			if ToBool(terrain.BTerrain) {
+				info.ValidTerrains = append(info.ValidTerrains, terrain.TerrainType)
+			}
+		}
+
+		r.FeatureInfos[feature.Type] = info
+		types = append(types, feature.Type)
+	}
+
+	return types
+}
+
+// AssignBonusInfos assigns all bonuses from the Civ4BonusInfos struct to the BonusInfos registry.
+func (r *GameRegistries) AssignBonusInfos(bonusStruct *Civ4BonusInfos) []string {
+	var types []string
+	for _, bonus := range bonusStruct.BonusInfos.BonusInfo {
+		info := &BonusInfo{
+			TypeInfo:     TypeInfo{Type: bonus.Type, Description: bonus.Description},
+			BonusClass:   bonus.BonusClassType,
+			TechReveal:   bonus.TechReveal,
+			TechTrade:    bonus.TechCityTrade,
+			TechObsolete: bonus.TechObsolete,
+			YieldChanges: ToIntSlice(bonus.YieldChanges.IYieldChange),
+			Health:       ToInt(bonus.IHealth),
+			Happiness:    ToInt(bonus.IHappiness),
+			MinLatitude:  ToInt(bonus.IMinLatitude),
+			MaxLatitude:  ToInt(bonus.IMaxLatitude),
+			Hills:        ToBool(bonus.BHills),
+			Flatlands:    ToBool(bonus.BFlatlands),
+			NoRiverSide:  ToBool(bonus.BNoRiverSide),
+		}
+
+		for _, terrain := range bonus.TerrainBooleans.TerrainBoolean {
+			if ToBool(terrain.BTerrain) {
+				info.ValidTerrains = append(info.ValidTerrains, terrain.TerrainType)
+			}
+		}
+		for _, feature := range bonus.FeatureBooleans.FeatureBoolean {
+			if ToBool(feature.BFeature) {
+				info.ValidFeatures = append(info.ValidFeatures, feature.FeatureType)
+			}
+		}
+		for _, terrain := range bonus.FeatureTerrainBooleans.FeatureTerrainBoolean {
+			if ToBool(terrain.BFeatureTerrain) {
+				info.ValidFeatureTerrains = append(info.ValidFeatureTerrains, terrain.TerrainType)
+			}
+		}
+
+		r.BonusInfos[bonus.Type] = info
+		types = append(types, bonus.Type)
+	}
+
+	return types
+}
+
+// AssignImprovementInfos assigns all improvements from the Civ4ImprovementInfos struct to the ImprovementInfos registry.
+func (r *GameRegistries) AssignImprovementInfos(improvementStruct *Civ4ImprovementInfos) []string {
+	var types []string
+	for _, improvement := range improvementStruct.ImprovementInfos.ImprovementInfo {
+		info := &ImprovementInfo{
+			TypeInfo:             TypeInfo{Type: improvement.Type, Description: improvement.Description, Button: improvement.Button},
+			PrereqNatureYields:   ToIntSlice(improvement.PrereqNatureYields.IYield),
+			YieldChanges:         ToIntSlice(improvement.YieldIncreases.IYield),
+			HillsMakesValid:      ToBool(improvement.BHillsMakesValid),
+			FreshWaterMakesValid: ToBool(improvement.BFreshWaterMakesValid),
+			RiverSideMakesValid:  ToBool(improvement.BRiverSideMakesValid),
+			NoFreshWater:         ToBool(improvement.BNoFreshWater),
+			RequiresFlatlands:    ToBool(improvement.BRequiresFlatlands),
+			RequiresRiverSide:    ToBool(improvement.BRequiresRiverSide),
+			RequiresIrrigation:   ToBool(improvement.BRequiresIrrigation),
+			RequiresFeature:      ToBool(improvement.BRequiresFeature),
+			Water:                ToBool(improvement.BWater),
+			Goody:                ToBool(improvement.BGoody),
+			Permanent:            ToBool(improvement.BPermanent),
+			OutsideBorders:       ToBool(improvement.BOutsideBorders),
+			Pillage:              improvement.ImprovementPillage,
+			Upgrade:              improvement.ImprovementUpgrade,
+		}
+
+		for _, terrain := range improvement.TerrainMakesValids.TerrainMakesValid {
+			if ToBool(terrain.BMakesValid) {
+				info.ValidTerrains = append(info.ValidTerrains, terrain.TerrainType)
+			}
+		}
+		for _, feature := range improvement.FeatureMakesValids.FeatureMakesValid {
+			if ToBool(feature.BMakesValid) {
+				info.ValidFeatures = append(info.ValidFeatures, feature.FeatureType)
+			}
+		}
+		for _, bonus := range improvement.BonusTypeStructs.BonusTypeStruct {
+			if ToBool(bonus.BBonusMakesValid) {
+				info.ValidBonuses = append(info.ValidBonuses, bonus.BonusType)
+			}
+			if ToBool(bonus.BBonusTrade) {
+				info.TradeBonuses = append(info.TradeBonuses, bonus.BonusType)
+			}
+		}
+
+		r.ImprovementInfos[improvement.Type] = info
+		types = append(types, improvement.Type)
+	}
+
+	return types
+}
+
+// AssignRouteInfos assigns all routes from the Civ4RouteInfos struct to the RouteInfos registry.
+func (r *GameRegistries) AssignRouteInfos(routeStruct *Civ4RouteInfos) []string {
+	var types []string
+	for _, route := range routeStruct.RouteInfos.RouteInfo {
+		r.RouteInfos[route.Type] = &RouteInfo{
+			TypeInfo:        TypeInfo{Type: route.Type, Description: route.Description, Button: route.Button},
+			Yields:          ToIntSlice(route.Yields.IYield),
+			Movement:        ToInt(route.IMovement),
+			FlatMovement:    ToInt(route.IFlatMovement),
+			PrereqBonus:     route.BonusType,
+			PrereqOrBonuses: route.PrereqOrBonuses.BonusType,
+		}
+		types = append(types, route.Type)
+	}
+
+	return types
+}
+
+// AssignUnitInfos assigns all units from the Civ4UnitInfos struct to the UnitInfos registry.
+func (r *GameRegistries) AssignUnitInfos(unitStruct *Civ4UnitInfos) []string {
+	var types []string
+	for _, unit := range unitStruct.UnitInfos.UnitInfo {
+		info := &UnitInfo{
+			TypeInfo:        TypeInfo{Type: unit.Type, Description: unit.Description, Button: unit.Button},
+			Class:           unit.Class,
+			Combat:          unit.Combat,
+			Domain:          unit.Domain,
+			DefaultUnitAI:   unit.DefaultUnitAI,
+			PrereqBuilding:  unit.PrereqBuilding,
+			PrereqTech:      unit.PrereqTech,
+			PrereqTechs:     unit.TechTypes.PrereqTech,
+			PrereqBonus:     unit.BonusType,
+			PrereqOrBonuses: unit.PrereqBonuses.BonusType,
+			Cost:            ToInt(unit.ICost),
+			Moves:           ToInt(unit.IMoves),
+			Strength:        ToInt(unit.ICombat),
+			Animal:          ToBool(unit.BAnimal),
+			Found:           ToBool(unit.BFound),
+			OnlyDefensive:   ToBool(unit.BOnlyDefensive),
+		}
+
+		for _, unitAI := range unit.UnitAIs.UnitAI {
+			if ToBool(unitAI.BUnitAI) {
+				info.UnitAIs = append(info.UnitAIs, unitAI.UnitAIType)
+			}
+		}
+		for _, promotion := range unit.FreePromotions.FreePromotion {
+			if ToBool(promotion.BFreePromotion) {
+				info.FreePromotions = append(info.FreePromotions, promotion.PromotionType)
+			}
+		}
+
+		r.UnitInfos[unit.Type] = info
+		types = append(types, unit.Type)
+	}
+
+	return types
+}
+
+// AssignPromotionInfos assigns all promotions from the Civ4PromotionInfos struct to the PromotionInfos registry.
+func (r *GameRegistries) AssignPromotionInfos(promotionStruct *Civ4PromotionInfos) []string {
+	var types []string
+	for _, promotion := range promotionStruct.PromotionInfos.PromotionInfo {
+		info := &PromotionInfo{
+			TypeInfo:            TypeInfo{Type: promotion.Type, Description: promotion.Description, Button: promotion.Button},
+			PrereqPromotion:     promotion.PromotionPrereq,
+			PrereqTech:          promotion.TechPrereq,
+			PrereqStateReligion: promotion.StateReligionPrereq,
+			Leader:              ToBool(promotion.BLeader),
+		}
+
+		for _, prereq := range []string{promotion.PromotionPrereqOr1, promotion.PromotionPrereqOr2} {
+			if prereq != "" && prereq != NoneType {
+				info.PrereqOrPromotions = append(info.PrereqOrPromotions, prereq)
+			}
+		}
+		for _, combat := range promotion.UnitCombats.UnitCombat {
+			if ToBool(combat.BUnitCombat) {
+				info.UnitCombats = append(info.UnitCombats, combat.UnitCombatType)
+			}
+		}
+
+		r.PromotionInfos[promotion.Type] = info
+		types = append(types, promotion.Type)
+	}
+
+	return types
+}
+
+// AssignBuildingInfos assigns all buildings from the Civ4BuildingInfos struct to the BuildingInfos registry.
+func (r *GameRegistries) AssignBuildingInfos(buildingStruct *Civ4BuildingInfos) []string {
+	var types []string
+	for _, building := range buildingStruct.BuildingInfos.BuildingInfo {
+		r.BuildingInfos[building.Type] = &BuildingInfo{
+			TypeInfo:            TypeInfo{Type: building.Type, Description: building.Description, Help: building.Help, Button: building.Button},
+			Class:               building.BuildingClass,
+			SpecialBuildingType: building.SpecialBuildingType,
+			PrereqTech:          building.PrereqTech,
+			PrereqTechs:         building.TechTypes.PrereqTech,
+			ObsoleteTech:        building.ObsoleteTech,
+			PrereqBonus:         building.Bonus,
+			PrereqOrBonuses:     building.PrereqBonuses.BonusType,
+			PrereqReligion:      building.PrereqReligion,
+			HolyCity:            building.HolyCity,
+			Religion:            building.ReligionType,
+			StateReligion:       building.StateReligion,
+			FreeStartEra:        building.FreeStartEra,
+			MaxStartEra:         building.MaxStartEra,
+			Cost:                ToInt(building.ICost),
+			Water:               ToBool(building.BWater),
+			River:               ToBool(building.BRiver),
+			Capital:             ToBool(building.BCapital),
+		}
+		types = append(types, building.Type)
+	}
+
+	return types
+}
+
+// AssignTechInfos assigns all technologies from the Civ4TechInfos struct to the TechInfos registry.
+func (r *GameRegistries) AssignTechInfos(techStruct *Civ4TechInfos) []string {
+	var types []string
+	for _, tech := range techStruct.TechInfos.TechInfo {
+		r.TechInfos[tech.Type] = &TechInfo{
+			TypeInfo:   TypeInfo{Type: tech.Type, Description: tech.Description, Help: tech.Help, Button: tech.Button},
+			Era:        tech.Era,
+			Cost:       ToInt(tech.ICost),
+			AndPrereqs: tech.AndPreReqs.PrereqTech,
+			OrPrereqs:  tech.OrPreReqs.PrereqTech,
+			GridX:      ToInt(tech.IGridX),
+			GridY:      ToInt(tech.IGridY),
+		}
+		types = append(types, tech.Type)
+	}
+
+	return types
+}
+
+// AssignProjectInfos assigns all projects from the Civ4ProjectInfo struct to the ProjectInfos registry.
+func (r *GameRegistries) AssignProjectInfos(projectStruct *Civ4ProjectInfo) []string {
+	var types []string
+	for _, project := range projectStruct.ProjectInfos.ProjectInfo {
+		info := &ProjectInfo{
+			TypeInfo:            TypeInfo{Type: project.Type, Description: project.Description, Button: project.Button},
+			TechPrereq:          project.TechPrereq,
+			VictoryPrereq:       project.VictoryPrereq,
+			AnyoneProjectPrereq: project.AnyoneProjectPrereq,
+			PrereqProjects:      make(map[string]int),
+			MaxGlobalInstances:  ToInt(project.IMaxGlobalInstances),
+			MaxTeamInstances:    ToInt(project.IMaxTeamInstances),
+			Cost:                ToInt(project.ICost),
+			Spaceship:           ToBool(project.BSpaceship),
+		}
+		for _, prereq := range project.PrereqProjects.PrereqProject {
+			info.PrereqProjects[prereq.ProjectType] = ToInt(prereq.INeeded)
+		}
+
+		r.ProjectInfos[project.Type] = info
+		types = append(types, project.Type)
+	}
+
+	return types
+}
+
+// AssignCivilizationInfos assigns all civilizations from the Civ4CivilizationInfos struct to the CivilizationInfos registry.
+func (r *GameRegistries) AssignCivilizationInfos(civStruct *Civ4CivilizationInfos) []string {
+	var types []string
+	for _, civ := range civStruct.CivilizationInfos.CivilizationInfo {
+		info := &CivilizationInfo{
+			TypeInfo:           TypeInfo{Type: civ.Type, Description: civ.Description, Button: civ.Button},
+			ShortDescription:   civ.ShortDescription,
+			Adjective:          civ.Adjective,
+			DefaultPlayerColor: civ.DefaultPlayerColor,
+			ArtStyle:           civ.ArtStyleType,
+			Playable:           ToBool(civ.BPlayable),
+			AIPlayable:         ToBool(civ.BAIPlayable),
+			Cities:             civ.Cities.City,
+			InitialCivics:      civ.InitialCivics.CivicType,
+		}
+
+		for _, leader := range civ.Leaders.Leader {
+			if ToBool(leader.BLeaderAvailability) {
+				info.Leaders = append(info.Leaders, leader.LeaderName)
+			}
+		}
+		for _, tech := range civ.FreeTechs.FreeTech {
+			if ToBool(tech.BFreeTech) {
+				info.FreeTechs = append(info.FreeTechs, tech.TechType)
+			}
+		}
+
+		r.CivilizationInfos[civ.Type] = info
+		types = append(types, civ.Type)
+	}
+
+	return types
+}
+
+// AssignLeaderHeadInfos assigns all leaders from the Civ4LeaderHeadInfos struct to the LeaderHeadInfos registry.
+func (r *GameRegistries) AssignLeaderHeadInfos(leaderStruct *Civ4LeaderHeadInfos) []string {
+	var types []string
+	for _, leader := range leaderStruct.LeaderHeadInfos.LeaderHeadInfo {
+		info := &LeaderHeadInfo{
+			TypeInfo:         TypeInfo{Type: leader.Type, Description: leader.Description},
+			FavoriteCivic:    leader.FavoriteCivic,
+			FavoriteReligion: leader.FavoriteReligion,
+		}
+
+		for _, trait := range leader.Traits.Trait {
+			if ToBool(trait.BTrait) {
+				info.Traits = append(info.Traits, trait.TraitType)
+			}
+		}
+
+		r.LeaderHeadInfos[leader.Type] = info
+		types = append(types, leader.Type)
+	}
+
+	return types
+}
+
+// AssignPlayerColorInfos assigns all player colors from the Civ4PlayerColorInfos struct to the PlayerColorInfos registry.
+func (r *GameRegistries) AssignPlayerColorInfos(colorStruct *Civ4PlayerColorInfos) []string {
+	var types []string
+	for _, color := range colorStruct.PlayerColorInfos.PlayerColorInfo {
+		r.PlayerColorInfos[color.Type] = &PlayerColorInfo{
+			TypeInfo:       TypeInfo{Type: color.Type, Description: color.Description},
+			PrimaryColor:   color.ColorTypePrimary,
+			SecondaryColor: color.ColorTypeSecondary,
+			TextColor:      color.TextColorType,
+		}
+		types = append(types, color.Type)
+	}
+
+	return types
+}
+
+// AssignColorVals assigns all colors from the Civ4ColorVals struct to the ColorVals registry.
+func (r *GameRegistries) AssignColorVals(colorStruct *Civ4ColorVals) []string {
+	var types []string
+	for _, color := range colorStruct.ColorVals.ColorVal {
+		r.ColorVals[color.Type] = &ColorVal{
+			Type:  color.Type,
+			Red:   ToFloat(color.FRed),
+			Green: ToFloat(color.FGreen),
+			Blue:  ToFloat(color.FBlue),
+			Alpha: ToFloat(color.FAlpha),
+		}
+		types = append(types, color.Type)
+	}
+
+	return types
+}
+
+// AssignReligionInfos assigns all religions from the Civ4ReligionInfo struct to the ReligionInfos registry.
+func (r *GameRegistries) AssignReligionInfos(religionStruct *Civ4ReligionInfo) []string {
+	var types []string
+	for _, religion := range religionStruct.ReligionInfos.ReligionInfo {
+		r.ReligionInfos[religion.Type] = &ReligionInfo{
+			TypeInfo:   TypeInfo{Type: religion.Type, Description: religion.Description, Button: religion.Button},
+			Adjective:  religion.Adjective,
+			TechPrereq: religion.TechPrereq,
+			FreeUnit:   religion.FreeUnit,
+		}
+		types = append(types, religion.Type)
+	}
+
+	return types
+}
+
+// AssignCivicInfos assigns all civics from the Civ4CivicInfos struct to the CivicInfos registry.
+func (r *GameRegistries) AssignCivicInfos(civicStruct *Civ4CivicInfos) []string {
+	var types []string
+	for _, civic := range civicStruct.CivicInfos.CivicInfo {
+		r.CivicInfos[civic.Type] = &CivicInfo{
+			TypeInfo:    TypeInfo{Type: civic.Type, Description: civic.Description, Help: civic.Help, Button: civic.Button},
+			CivicOption: civic.CivicOptionType,
+			TechPrereq:  civic.TechPrereq,
+			Upkeep:      civic.Upkeep,
+		}
+		types = append(types, civic.Type)
+	}
+
+	return types
+}
+
+// AssignCivicOptionInfos assigns all civic options from the Civ4CivicOptionInfos struct to the CivicOptionInfos registry.
+func (r *GameRegistries) AssignCivicOptionInfos(civicOptionStruct *Civ4CivicOptionInfos) []string {
+	var types []string
+	for _, option := range civicOptionStruct.CivicOptionInfos.CivicOptionInfo {
+		r.CivicOptionInfos[option.Type] = &TypeInfo{
+			Type:        option.Type,
+			Description: option.Description,
+		}
+		types = append(types, option.Type)
+	}
+
+	return types
+}
+
+// AssignHandicapInfos assigns all difficulty levels from the Civ4HandicapInfo struct to the HandicapInfos registry.
+func (r *GameRegistries) AssignHandicapInfos(handicapStruct *Civ4HandicapInfo) []string {
+	var types []string
+	for _, handicap := range handicapStruct.HandicapInfos.HandicapInfo {
+		r.HandicapInfos[handicap.Type] = &HandicapInfo{
+			TypeInfo:     TypeInfo{Type: handicap.Type, Description: handicap.Description, Help: handicap.Help},
+			StartingGold: ToInt(handicap.IStartingGold),
+			FreeUnits:    ToInt(handicap.IFreeUnits),
+		}
+		types = append(types, handicap.Type)
+	}
+
+	return types
+}
+
+// AssignArtStyleTypes assigns art styles from the Civ4Types struct (GlobalTypes.xml) to the ArtStyleInfos registry.
+// Art styles have no descriptions in game files, so type is used as description too.
+func (r *GameRegistries) AssignArtStyleTypes(typesStruct *Civ4Types) []string {
+	var types []string
+	for _, artStyle := range typesStruct.ArtStyleTypes.ArtStyleType {
+		r.ArtStyleInfos[artStyle] = &TypeInfo{
+			Type:        artStyle,
+			Description: artStyle,
+		}
+		types = append(types, artStyle)
+	}
+
+	return types
+}
+
+// AssignGameText assigns all translations from the Civ4GameText struct to the LangStrings registry.
+// Texts have no types, so it returns the number of assigned tags.
+func (r *GameRegistries) AssignGameText(textStruct *Civ4GameText) int32 {
+	var counter int32 = 0
+	for _, text := range textStruct.TEXT {
+		for language, value := range text.Translations() {
+			if r.LangStrings[language] == nil {
+				r.LangStrings[language] = make(map[string]string)
+			}
+			r.LangStrings[language][text.Tag] = value
+		}
+		counter++
+	}
+
+	return counter
+}
+
+// AssignEraInfos assigns all eras from the Civ4EraInfos struct to the EraInfos registry.
+func (r *GameRegistries) AssignEraInfos(eraStruct *Civ4EraInfos) []string {
+	var types []string
+	for _, era := range eraStruct.EraInfos.EraInfo {
+		r.EraInfos[era.Type] = &TypeInfo{
+			Type:        era.Type,
+			Description: era.Description,
+		}
+		types = append(types, era.Type)
+	}
+
+	return types
+}
+
+// AssignSpeedInfos assigns all game speeds from the Civ4GameSpeedInfo struct to the SpeedInfos registry.
+func (r *GameRegistries) AssignSpeedInfos(speedStruct *Civ4GameSpeedInfo) []string {
+	var types []string
+	for _, speed := range speedStruct.GameSpeedInfos.GameSpeedInfo {
+		info := &SpeedInfo{
+			TypeInfo:         TypeInfo{Type: speed.Type, Description: speed.Description, Help: speed.Help},
+			GrowthPercent:    ToInt(speed.IGrowthPercent),
+			TrainPercent:     ToInt(speed.ITrainPercent),
+			ConstructPercent: ToInt(speed.IConstructPercent),
+			ResearchPercent:  ToInt(speed.IResearchPercent),
+		}
+		for _, turn := range speed.GameTurnInfos.GameTurnInfo {
+			info.GameTurns = append(info.GameTurns, GameTurnInfo{
+				MonthIncrement:    ToInt(turn.IMonthIncrement),
+				TurnsPerIncrement: ToInt(turn.ITurnsPerIncrement),
+			})
+		}
+
+		r.SpeedInfos[speed.Type] = info
+		types = append(types, speed.Type)
+	}
+
+	return types
+}
+
+// AssignCalendarInfos assigns all calendars from the Civ4CalendarInfos struct to the CalendarInfos registry.
+func (r *GameRegistries) AssignCalendarInfos(calendarStruct *Civ4CalendarInfos) []string {
+	var types []string
+	for _, calendar := range calendarStruct.CalendarInfos.CalendarInfo {
+		r.CalendarInfos[calendar.Type] = &TypeInfo{
+			Type:        calendar.Type,
+			Description: calendar.Description,
+		}
+		types = append(types, calendar.Type)
+	}
+
+	return types
+}
+
+// AssignGameOptionInfos assigns all game options from the Civ4GameOptionInfos struct to the GameOptionInfos registry.
+func (r *GameRegistries) AssignGameOptionInfos(gameOptionsStruct *Civ4GameOptionInfos) []string {
+	var types []string
+	for _, option := range gameOptionsStruct.GameOptionInfos.GameOptionInfo {
+		r.GameOptionInfos[option.Type] = &TypeInfo{
+			Type:        option.Type,
+			Description: option.Description,
+			Help:        option.Help,
+		}
+		types = append(types, option.Type)
+	}
+
+	return types
+}
+
+// AssignMPOptionInfos assigns all multiplayer options from the Civ4MPOptionInfos struct to the GameMPInfos registry.
+func (r *GameRegistries) AssignMPOptionInfos(gameMpOptionsStruct *Civ4MPOptionInfos) []string {
+	var types []string
+	for _, option := range gameMpOptionsStruct.MPOptionInfos.MPOptionInfo {
+		r.GameMPInfos[option.Type] = &TypeInfo{
+			Type:        option.Type,
+			Description: option.Description,
+			Help:        option.Help,
+		}
+		types = append(types, option.Type)
+	}
+
+	return types
+}
+
+// AssignForceControlInfos assigns all force controls from the Civ4ForceControlInfos struct to the ForceControlInfos registry.
+func (r *GameRegistries) AssignForceControlInfos(forceControlStruct *Civ4ForceControlInfos) []string {
+	var types []string
+	for _, option := range forceControlStruct.ForceControlInfos.ForceControlInfo {
+		r.ForceControlInfos[option.Type] = &TypeInfo{
+			Type:        option.Type,
+			Description: option.Description,
+			Help:        option.Help,
+		}
+		types = append(types, option.Type)
+	}
+
+	return types
+}
+
+// AssignVictoryInfos assigns all victories from the Civ4VictoryInfo struct to the VictoryInfos registry.
+func (r *GameRegistries) AssignVictoryInfos(victoryStruct *Civ4VictoryInfo) []string {
+	var types []string
+	for _, option := range victoryStruct.VictoryInfos.VictoryInfo {
+		r.VictoryInfos[option.Type] = &TypeInfo{
+			Type:        option.Type,
+			Description: option.Description,
+		}
+		types = append(types, option.Type)
+	}
+
+	return types
+}
@@ -0,0 +1,170 @@
+package editor
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+const testTechXml = `<?xml version="1.0"?>
+<Civ4TechInfos xmlns="x-schema:CIV4TechnologiesSchema.xml">
+	<TechInfos>
+		<TechInfo>
+			<Type>TECH_TEST_WHEEL</Type>
+			<Description>TXT_KEY_TECH_THE_WHEEL</Description>
+			<Era>ERA_ANCIENT</Era>
+			<iCost>120</iCost>
+			<OrPreReqs>
+				<PrereqTech>TECH_TEST_AGRICULTURE</PrereqTech>
+				<PrereqTech>TECH_TEST_HUNTING</PrereqTech>
+			</OrPreReqs>
+			<AndPreReqs/>
+		</TechInfo>
+	</TechInfos>
+</Civ4TechInfos>`
+
+const testTerrainXml = `<?xml version="1.0"?>
+<Civ4TerrainInfos xmlns="x-schema:CIV4TerrainSchema.xml">
+	<TerrainInfos>
+		<TerrainInfo>
+			<Type>TERRAIN_TEST_GRASS</Type>
+			<Description>TXT_KEY_TERRAIN_GRASS</Description>
+			<Yields>
+				<iYield>2</iYield>
+				<iYield>0</iYield>
+				<iYield>0</iYield>
+			</Yields>
+			<bWater>0</bWater>
+			<bFound>1</bFound>
+		</TerrainInfo>
+	</TerrainInfos>
+</Civ4TerrainInfos>`
+
+func TestAssignTechInfos(t *testing.T) {
+	techStruct := &Civ4TechInfos{}
+	if err := xml.Unmarshal([]byte(testTechXml), techStruct); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewGameRegistries()
+	if len(r.AssignTechInfos(techStruct)) != 1 {
+		t.Fatal("AssignTechInfos failed: expected 1 tech")
+	}
+
+	tech, ok := r.TechInfos["TECH_TEST_WHEEL"]
+	if !ok {
+		t.Fatal("AssignTechInfos failed: tech not found in registry")
+	}
+
+	if tech.Cost != 120 || tech.Era != "ERA_ANCIENT" || len(tech.OrPrereqs) != 2 || len(tech.AndPrereqs) != 0 {
+		t.Errorf("AssignTechInfos failed: unexpected tech info %+v", tech)
+	}
+}
+
+func TestAssignTerrainInfos(t *testing.T) {
+	terrainStruct := &Civ4TerrainInfos{}
+	if err := xml.Unmarshal([]byte(testTerrainXml), terrainStruct); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewGameRegistries()
+	if len(r.AssignTerrainInfos(terrainStruct)) != 1 {
+		t.Fatal("AssignTerrainInfos failed: expected 1 terrain")
+	}
+
+	terrain := r.TerrainInfos["TERRAIN_TEST_GRASS"]
+	if terrain == nil || !terrain.Found || terrain.Water || len(terrain.Yields) != 3 || terrain.Yields[0] != 2 {
+		t.Errorf("AssignTerrainInfos failed: unexpected terrain info %+v", terrain)
+	}
+}
+
+const testCivilizationXml = `<?xml version="1.0"?>
+<Civ4CivilizationInfos xmlns="x-schema:CIV4CivilizationsSchema.xml">
+	<CivilizationInfos>
+		<CivilizationInfo>
+			<Type>CIVILIZATION_TEST_ROME</Type>
+			<Description>TXT_KEY_CIV_ROME_DESC</Description>
+			<DefaultPlayerColor>PLAYERCOLOR_PURPLE</DefaultPlayerColor>
+			<ArtStyleType>ARTSTYLE_GRECO_ROMAN</ArtStyleType>
+			<bPlayable>1</bPlayable>
+			<Cities>
+				<City>TXT_KEY_CITY_NAME_ROME</City>
+				<City>TXT_KEY_CITY_NAME_ANTIUM</City>
+			</Cities>
+			<Leaders>
+				<Leader>
+					<LeaderName>LEADER_TEST_AUGUSTUS</LeaderName>
+					<bLeaderAvailability>1</bLeaderAvailability>
+				</Leader>
+				<Leader>
+					<LeaderName>LEADER_TEST_CAESAR</LeaderName>
+					<bLeaderAvailability>0</bLeaderAvailability>
+				</Leader>
+			</Leaders>
+		</CivilizationInfo>
+	</CivilizationInfos>
+</Civ4CivilizationInfos>`
+
+func TestAssignCivilizationInfos(t *testing.T) {
+	civStruct := &Civ4CivilizationInfos{}
+	if err := xml.Unmarshal([]byte(testCivilizationXml), civStruct); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewGameRegistries()
+	if len(r.AssignCivilizationInfos(civStruct)) != 1 {
+		t.Fatal("AssignCivilizationInfos failed: expected 1 civilization")
+	}
+
+	civ := r.CivilizationInfos["CIVILIZATION_TEST_ROME"]
+	if civ == nil || !civ.Playable || civ.ArtStyle != "ARTSTYLE_GRECO_ROMAN" || len(civ.Cities) != 2 {
+		t.Fatalf("AssignCivilizationInfos failed: unexpected civilization info %+v", civ)
+	}
+
+	leaders := r.GetCivilizationLeaders("CIVILIZATION_TEST_ROME")
+	if len(leaders) != 1 || leaders[0] != "LEADER_TEST_AUGUSTUS" {
+		t.Errorf("GetCivilizationLeaders failed: %v", leaders)
+	}
+
+	civs := r.GetLeaderCivilizations("LEADER_TEST_AUGUSTUS")
+	if len(civs) != 1 || civs[0] != "CIVILIZATION_TEST_ROME" {
+		t.Errorf("GetLeaderCivilizations failed: %v", civs)
+	}
+}
+
+const testProjectXml = `<?xml version="1.0"?>
+<Civ4ProjectInfo xmlns="x-schema:CIV4GameInfoSchema.xml">
+	<ProjectInfos>
+		<ProjectInfo>
+			<Type>PROJECT_TEST_SS_CASING</Type>
+			<Description>TXT_KEY_PROJECT_SS_CASING</Description>
+			<TechPrereq>TECH_TEST_WHEEL</TechPrereq>
+			<iMaxGlobalInstances>-1</iMaxGlobalInstances>
+			<iMaxTeamInstances>5</iMaxTeamInstances>
+			<iCost>400</iCost>
+			<bSpaceship>1</bSpaceship>
+			<PrereqProjects>
+				<PrereqProject>
+					<ProjectType>PROJECT_TEST_APOLLO</ProjectType>
+					<iNeeded>1</iNeeded>
+				</PrereqProject>
+			</PrereqProjects>
+		</ProjectInfo>
+	</ProjectInfos>
+</Civ4ProjectInfo>`
+
+func TestAssignProjectInfos(t *testing.T) {
+	projectStruct := &Civ4ProjectInfo{}
+	if err := xml.Unmarshal([]byte(testProjectXml), projectStruct); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewGameRegistries()
+	if len(r.AssignProjectInfos(projectStruct)) != 1 {
+		t.Fatal("AssignProjectInfos failed: expected 1 project")
+	}
+
+	project := r.ProjectInfos["PROJECT_TEST_SS_CASING"]
+	if project == nil || !project.Spaceship || project.MaxTeamInstances != 5 || project.Cost != 400 || project.PrereqProjects["PROJECT_TEST_APOLLO"] != 1 {
+		t.Errorf("AssignProjectInfos failed: unexpected project info %+v", project)
+	}
+}
@@ -0,0 +1,114 @@
+package editor
+
+import (
+	"crypto/sha1"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// xmlCacheVersion must be increased when XML structs change, so old cache entries are not used
+const xmlCacheVersion = 3
+
+// XmlCacheDir is a directory where decoded XML files are cached. Empty value disables cache.
+var XmlCacheDir = defaultXMLCacheDir()
+
+// xmlCacheHeader is written before the decoded struct in cache file
+type xmlCacheHeader struct {
+	XmlType CivXmlType
+	Schema  string
+	HasData bool
+}
+
+// defaultXMLCacheDir returns a directory for XML cache inside user cache directory
+func defaultXMLCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "civ4-studio", fmt.Sprintf("xml-v%d", xmlCacheVersion))
+}
+
+// ClearXMLCache removes all cached XML files
+func ClearXMLCache() error {
+	if XmlCacheDir == "" {
+		return nil
+	}
+
+	return os.RemoveAll(XmlCacheDir)
+}
+
+// getXMLCachePath returns a path to the cache file for XML file. Key includes modification time and size,
+// so the cache entry is not used anymore when file is changed.
+func getXMLCachePath(path string, stat os.FileInfo) string {
+	hash := sha1.Sum([]byte(fmt.Sprintf("%s|%d|%d", path, stat.ModTime().UnixNano(), stat.Size())))
+	return filepath.Join(XmlCacheDir, hex.EncodeToString(hash[:])+".gob")
+}
+
+// readXMLCache returns cached decoding result for the file, or false if there is no valid cache entry
+func readXMLCache(path string, stat os.FileInfo) (result xmlDecodeResult, ok bool) {
+	if XmlCacheDir == "" {
+		return
+	}
+
+	file, err := os.Open(getXMLCachePath(path, stat))
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	header := xmlCacheHeader{}
+	decoder := gob.NewDecoder(file)
+	if err = decoder.Decode(&header); err != nil {
+		return
+	}
+
+	result.XmlType, result.Schema = header.XmlType, header.Schema
+	newStruct, known := xmlStructs[header.XmlType]
+	if header.HasData != known {
+		// The type became supported or unsupported since the entry was written
+		return
+	}
+
+	if header.HasData {
+		result.Data = newStruct()
+		if err = decoder.Decode(result.Data); err != nil {
+			return
+		}
+	}
+
+	return result, true
+}
+
+// writeXMLCache saves decoding result to the cache. Errors are ignored, the file will be decoded again next time.
+// File is written to temporary file first, so incomplete cache entries are never read.
+func writeXMLCache(path string, stat os.FileInfo, result xmlDecodeResult) {
+	if XmlCacheDir == "" || result.Err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(XmlCacheDir, 0755); err != nil {
+		return
+	}
+
+	tmp, err := os.CreateTemp(XmlCacheDir, "*.tmp")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	encoder := gob.NewEncoder(tmp)
+	err = encoder.Encode(xmlCacheHeader{XmlType: result.XmlType, Schema: result.Schema, HasData: result.Data != nil})
+	if err == nil && result.Data != nil {
+		err = encoder.Encode(result.Data)
+	}
+
+	if closeErr := tmp.Close(); err != nil || closeErr != nil {
+		return
+	}
+
+	_ = os.Rename(tmp.Name(), getXMLCachePath(path, stat))
+}
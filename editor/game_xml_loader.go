@@ -1,193 +1,302 @@
 package editor
 
 import (
-	"encoding/xml"
+	"context"
+	"os"
+	"runtime"
 	"strconv"
+	"strings"
+	"sync"
 )
 
 const XmlDir = "Assets/XML"
 const XmlExt = "xml"
 
-// LoadAllXML loads all XML files recursively from the game directory.
-// XML file type is automatically detected and assigned to the appropriate global variable.
-// progressHandler is a manual callback function that is called after each file is parsed (for UI updates).
-func LoadAllXML(progressHandler func(string)) error {
-	var decoder *xml.Decoder
-	var xmlType CivXmlType
+// XmlLoaderWorkers is a maximum number of XML files decoded at the same time
+var XmlLoaderWorkers = runtime.NumCPU()
 
-	files, err := GetFilesFromGameDirsRecursive(XmlDir, XmlExt)
+// xmlStructs contains constructors of structs for every supported XML root tag.
+// Files with other root tags (schemas etc.) are skipped.
+var xmlStructs = map[CivXmlType]func() any{
+	"Civ4Defines":           func() any { return &Civ4Defines{} },
+	"Civ4GameText":          func() any { return &Civ4GameText{} },
+	"Civ4EraInfos":          func() any { return &Civ4EraInfos{} },
+	"Civ4GameSpeedInfo":     func() any { return &Civ4GameSpeedInfo{} },
+	"Civ4CalendarInfos":     func() any { return &Civ4CalendarInfos{} },
+	"Civ4GameOptionInfos":   func() any { return &Civ4GameOptionInfos{} },
+	"Civ4MPOptionInfos":     func() any { return &Civ4MPOptionInfos{} },
+	"Civ4ForceControlInfos": func() any { return &Civ4ForceControlInfos{} },
+	"Civ4VictoryInfo":       func() any { return &Civ4VictoryInfo{} },
+	"Civ4TerrainInfos":      func() any { return &Civ4TerrainInfos{} },
+	"Civ4FeatureInfos":      func() any { return &Civ4FeatureInfos{} },
+	"Civ4BonusInfos":        func() any { return &Civ4BonusInfos{} },
+	"Civ4ImprovementInfos":  func() any { return &Civ4ImprovementInfos{} },
+	"Civ4RouteInfos":        func() any { return &Civ4RouteInfos{} },
+	"Civ4UnitInfos":         func() any { return &Civ4UnitInfos{} },
+	"Civ4PromotionInfos":    func() any { return &Civ4PromotionInfos{} },
+	"Civ4BuildingInfos":     func() any { return &Civ4BuildingInfos{} },
+	"Civ4TechInfos":         func() any { return &Civ4TechInfos{} },
+	"Civ4ProjectInfo":       func() any { return &Civ4ProjectInfo{} },
+	"Civ4CivilizationInfos": func() any { return &Civ4CivilizationInfos{} },
+	"Civ4LeaderHeadInfos":   func() any { return &Civ4LeaderHeadInfos{} },
+	"Civ4PlayerColorInfos":  func() any { return &Civ4PlayerColorInfos{} },
+	"Civ4ColorVals":         func() any { return &Civ4ColorVals{} },
+	"Civ4ReligionInfo":      func() any { return &Civ4ReligionInfo{} },
+	"Civ4CivicInfos":        func() any { return &Civ4CivicInfos{} },
+	"Civ4CivicOptionInfos":  func() any { return &Civ4CivicOptionInfos{} },
+	"Civ4HandicapInfo":      func() any { return &Civ4HandicapInfo{} },
+	"Civ4Types":             func() any { return &Civ4Types{} },
+}
+
+// xmlDecodeResult is a result of decoding a single XML file
+type xmlDecodeResult struct {
+	XmlType CivXmlType
+	// Schema is a schema file referenced by the root tag (see xmlSchemaRegex)
+	Schema string
+	// Data is a pointer to one of the structs from xmlStructs, or nil if the file type is not supported
+	Data any
+	Err  error
+}
+
+// LoadGameRegistries loads all XML files recursively from the game directory and mod (empty for base game)
+// with the same precedence the game uses (see GetXMLFilesFor). XML file type is automatically detected and assigned to the appropriate registry.
+// Files are decoded concurrently (and cached, see XmlCacheDir), but assigned in the files order, so the result is deterministic.
+// Source file of every loaded type is saved to XmlTypeSources, problems of every file are saved to XmlLoadReport.
+// Files that cannot be listed are skipped and reported, so error is returned only if no files are found at all.
+// Progress is reported to the reporter of the context (see ProgressFrom), loading stops if the context is cancelled.
+func LoadGameRegistries(ctx context.Context, gameDir string, mod string) (*GameRegistries, error) {
+	r := NewGameRegistries()
+	progress := ProgressFrom(ctx)
+	progress.Phase("Listing XML files", 0)
+	files, err := GetXMLFilesFor(gameDir, mod)
 	if err != nil {
-		ConsoleWrite(err.Error())
-		return err
+		Logger.Warn("Cannot list XML files", "error", err)
+		if len(files) == 0 {
+			return nil, err
+		}
+
+		r.XmlLoadReport.Errors = strings.Split(err.Error(), "\n")
+	}
+
+	// Show where the files are taken from, it helps to understand what mod overrides
+	roots := make(map[string]int)
+	for _, f := range files {
+		roots[f.Root]++
+	}
+	for _, root := range SortKeys(roots) {
+		Logger.Info("Using XML files", "count", roots[root], "root", root)
 	}
 
-	if progressHandler == nil {
-		progressHandler = func(s string) {}
+	results, err := decodeXMLFiles(ctx, files)
+	if err != nil {
+		return nil, err
 	}
+
+	progress.Phase("Loading registries", len(files))
 	counter := make(map[CivXmlType]int32)
+	definitions := make(map[string][]string)
 
-	for _, f := range files {
-		progressHandler("Parsing XML: " + f)
+	for i, f := range files {
+		result := results[i]
+		fileReport := newXmlFileReport(f.Path, result)
+		r.XmlLoadReport.Files = append(r.XmlLoadReport.Files, fileReport)
+		progress.Step(f.Path)
 
-		decoder, xmlType, err = ParseXMLFromFile(f)
-		if err != nil {
+		if result.Err != nil {
+			Logger.Warn("Cannot parse XML file", "type", result.XmlType, "path", f.Path, "error", result.Err)
 			continue
 		}
 
-		switch xmlType {
-		case "Schema":
-			break
-
-		case "Civ4Defines":
-			definesStruct := &Civ4Defines{}
-			err = decoder.Decode(definesStruct)
-			if err != nil {
-				break
-			}
-
-			counter[xmlType] += AssignGlobalDefines(definesStruct)
+		if result.Data == nil {
+			continue
+		}
 
-		case "Civ4GameText":
-			textStruct := &Civ4GameText{}
-			err = decoder.Decode(textStruct)
-			if err != nil {
-				break
-			}
+		types, count := r.AssignXMLStruct(result.Data)
+		counter[result.XmlType] += count
+		fileReport.Count = count
+		if count == 0 {
+			fileReport.Warnings = append(fileReport.Warnings, "no entries loaded, file structure may not match the schema")
+		}
 
-			for _, text := range textStruct.TEXT {
-				LangStrings[text.Tag] = text.English
-				// @todo multiple languages
-				counter[xmlType]++
-			}
+		for _, t := range types {
+			r.XmlTypeSources[t] = f.Path
+			definitions[t] = append(definitions[t], f.Path)
+		}
+	}
 
-		case "Civ4EraInfos":
-			eraStruct := &Civ4EraInfos{}
-			err = decoder.Decode(eraStruct)
-			if err != nil {
-				break
-			}
+	checkXmlSchemas(r.XmlLoadReport, files)
+	r.XmlLoadReport.Duplicates = getXmlDuplicates(definitions)
 
-			for _, era := range eraStruct.EraInfos.EraInfo {
-				EraInfos[era.Type] = &TypeInfo{
-					Type:        era.Type,
-					Description: era.Description,
-				}
-				counter[xmlType]++
-			}
+	for civXmlType, cnt := range counter {
+		Logger.Debug("Loaded XML entries", "type", civXmlType, "count", cnt)
+	}
+	Logger.Info(r.XmlLoadReport.Summary())
+	return r, nil
+}
 
-		case "Civ4GameSpeedInfo":
-			speedStruct := &Civ4GameSpeedInfo{}
-			err = decoder.Decode(speedStruct)
-			if err != nil {
-				break
-			}
+// decodeXMLFiles decodes files using a bounded pool of workers (see XmlLoaderWorkers).
+// Results have the same order as files, progress is reported from the current goroutine.
+// Files which are not started yet are skipped if the context is cancelled, its error is returned then
+func decodeXMLFiles(ctx context.Context, files []*GameFile) ([]xmlDecodeResult, error) {
+	progress := ProgressFrom(ctx)
+	progress.Phase("Parsing XML files", len(files))
+	results := make([]xmlDecodeResult, len(files))
+	jobs := make(chan int)
+	done := make(chan int)
 
-			for _, speed := range speedStruct.GameSpeedInfos.GameSpeedInfo {
-				SpeedInfos[speed.Type] = &TypeInfo{
-					Type:        speed.Type,
-					Description: speed.Description,
-				}
-				counter[xmlType]++
+	workers := max(XmlLoaderWorkers, 1)
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = decodeXMLFile(files[i].Path)
+				done <- i
 			}
+		}()
+	}
 
-		case "Civ4CalendarInfos":
-			calendarStruct := &Civ4CalendarInfos{}
-			err = decoder.Decode(calendarStruct)
-			if err != nil {
-				break
+	go func() {
+	feed:
+		for i := range files {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				break feed
 			}
+		}
+		close(jobs)
+		wg.Wait()
+		close(done)
+	}()
 
-			for _, calendar := range calendarStruct.CalendarInfos.CalendarInfo {
-				CalendarInfos[calendar.Type] = &TypeInfo{
-					Type:        calendar.Type,
-					Description: calendar.Description,
-				}
-				counter[xmlType]++
-			}
+	for i := range done {
+		progress.Step(files[i].Path)
+	}
 
-		case "Civ4GameOptionInfos":
-			gameOptionsStruct := &Civ4GameOptionInfos{}
-			err = decoder.Decode(gameOptionsStruct)
-			if err != nil {
-				break
-			}
+	return results, ctx.Err()
+}
 
-			for _, option := range gameOptionsStruct.GameOptionInfos.GameOptionInfo {
-				GameOptionInfos[option.Type] = &TypeInfo{
-					Type:        option.Type,
-					Description: option.Description,
-				}
-				counter[xmlType]++
-			}
+// decodeXMLFile decodes a single XML file to the struct matching its root tag, using cache if possible
+func decodeXMLFile(path string) (result xmlDecodeResult) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		result.Err = err
+		return
+	}
 
-		case "Civ4MPOptionInfos":
-			gameMpOptionsStruct := &Civ4MPOptionInfos{}
-			err = decoder.Decode(gameMpOptionsStruct)
-			if err != nil {
-				break
-			}
+	if cached, ok := readXMLCache(path, stat); ok {
+		return cached
+	}
 
-			for _, option := range gameMpOptionsStruct.MPOptionInfos.MPOptionInfo {
-				GameMPInfos[option.Type] = &TypeInfo{
-					Type:        option.Type,
-					Description: option.Description,
-				}
-				counter[xmlType]++
-			}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		result.Err = err
+		return
+	}
 
-		case "Civ4ForceControlInfos":
-			forceControlStruct := &Civ4ForceControlInfos{}
-			err = decoder.Decode(forceControlStruct)
-			if err != nil {
-				break
-			}
+	decoder, xmlType := ParseXMLFromBytes(data)
+	result.XmlType = xmlType
+	if matches := xmlSchemaRegex.FindSubmatch(data); len(matches) > 1 {
+		result.Schema = string(matches[1])
+	}
 
-			for _, option := range forceControlStruct.ForceControlInfos.ForceControlInfo {
-				ForceControlInfos[option.Type] = &TypeInfo{
-					Type:        option.Type,
-					Description: option.Description,
-				}
-				counter[xmlType]++
-			}
+	newStruct, ok := xmlStructs[xmlType]
+	if ok {
+		result.Data = newStruct()
+		result.Err = decoder.Decode(result.Data)
+		if result.Err != nil {
+			return
+		}
+	}
 
-		case "Civ4VictoryInfo":
-			victoryStruct := &Civ4VictoryInfo{}
-			err = decoder.Decode(victoryStruct)
-			if err != nil {
-				break
-			}
+	writeXMLCache(path, stat, result)
+	return
+}
 
-			for _, option := range victoryStruct.VictoryInfos.VictoryInfo {
-				VictoryInfos[option.Type] = &TypeInfo{
-					Type:        option.Type,
-					Description: option.Description,
-				}
-				counter[xmlType]++
-			}
-		}
+// AssignXMLStruct assigns a decoded XML struct (see xmlStructs) to the appropriate registry.
+// Returns types of assigned entries and the number of assigned entries (defines and texts have no types, so only the number is returned)
+func (r *GameRegistries) AssignXMLStruct(data any) (types []string, count int32) {
+	switch v := data.(type) {
+	case *Civ4Defines:
+		return nil, r.AssignGlobalDefines(v)
+	case *Civ4GameText:
+		return nil, r.AssignGameText(v)
+	case *Civ4EraInfos:
+		types = r.AssignEraInfos(v)
+	case *Civ4GameSpeedInfo:
+		types = r.AssignSpeedInfos(v)
+	case *Civ4CalendarInfos:
+		types = r.AssignCalendarInfos(v)
+	case *Civ4GameOptionInfos:
+		types = r.AssignGameOptionInfos(v)
+	case *Civ4MPOptionInfos:
+		types = r.AssignMPOptionInfos(v)
+	case *Civ4ForceControlInfos:
+		types = r.AssignForceControlInfos(v)
+	case *Civ4VictoryInfo:
+		types = r.AssignVictoryInfos(v)
+	case *Civ4TerrainInfos:
+		types = r.AssignTerrainInfos(v)
+	case *Civ4FeatureInfos:
+		types = r.AssignFeatureInfos(v)
+	case *Civ4BonusInfos:
+		types = r.AssignBonusInfos(v)
+	case *Civ4ImprovementInfos:
+		types = r.AssignImprovementInfos(v)
+	case *Civ4RouteInfos:
+		types = r.AssignRouteInfos(v)
+	case *Civ4UnitInfos:
+		types = r.AssignUnitInfos(v)
+	case *Civ4PromotionInfos:
+		types = r.AssignPromotionInfos(v)
+	case *Civ4BuildingInfos:
+		types = r.AssignBuildingInfos(v)
+	case *Civ4TechInfos:
+		types = r.AssignTechInfos(v)
+	case *Civ4ProjectInfo:
+		types = r.AssignProjectInfos(v)
+	case *Civ4CivilizationInfos:
+		types = r.AssignCivilizationInfos(v)
+	case *Civ4LeaderHeadInfos:
+		types = r.AssignLeaderHeadInfos(v)
+	case *Civ4PlayerColorInfos:
+		types = r.AssignPlayerColorInfos(v)
+	case *Civ4ColorVals:
+		types = r.AssignColorVals(v)
+	case *Civ4ReligionInfo:
+		types = r.AssignReligionInfos(v)
+	case *Civ4CivicInfos:
+		types = r.AssignCivicInfos(v)
+	case *Civ4CivicOptionInfos:
+		types = r.AssignCivicOptionInfos(v)
+	case *Civ4HandicapInfo:
+		types = r.AssignHandicapInfos(v)
+	case *Civ4Types:
+		types = r.AssignArtStyleTypes(v)
+	}
 
-		if err != nil {
-			ConsoleWrite("Cannot parse %s: %s (%s)", xmlType, err.Error(), f)
-			continue
+	// Redefined types keep their original position, like in the game
+	for _, t := range types {
+		if _, ok := r.XmlTypeOrder[t]; !ok {
+			r.XmlTypeOrder[t] = len(r.XmlTypeOrder)
 		}
 	}
 
-	for civXmlType, cnt := range counter {
-		ConsoleWrite("Loaded %d %s", cnt, civXmlType)
-	}
-	return nil
+	return types, int32(len(types))
 }
 
-// AssignGlobalDefines assigns all defines from the Civ4Defines struct to the global variables.
-func AssignGlobalDefines(definesStruct *Civ4Defines) int32 {
+// AssignGlobalDefines assigns all defines from the Civ4Defines struct to the defines registries.
+func (r *GameRegistries) AssignGlobalDefines(definesStruct *Civ4Defines) int32 {
 	var counter int32 = 0
 	for _, define := range definesStruct.Define {
 		if define.IDefineIntVal != "" {
-			GlobalIntDefines[define.DefineName], _ = strconv.Atoi(define.IDefineIntVal)
+			r.GlobalIntDefines[define.DefineName], _ = strconv.Atoi(define.IDefineIntVal)
 		} else if define.FDefineFloatVal != "" {
-			GlobalFloatDefines[define.DefineName], _ = strconv.ParseFloat(define.FDefineFloatVal, 64)
+			r.GlobalFloatDefines[define.DefineName], _ = strconv.ParseFloat(define.FDefineFloatVal, 64)
 		} else if define.DefineTextVal != "" {
-			GlobalStringDefines[define.DefineName] = define.DefineTextVal
+			r.GlobalStringDefines[define.DefineName] = define.DefineTextVal
 		} else {
 			continue
 		}
@@ -0,0 +1,212 @@
+package editor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// writeTestFiles writes files (relative path => content) into the root directory
+func writeTestFiles(t *testing.T, root string, files map[string]string) {
+	for path, content := range files {
+		fullPath := filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// useTestXmlCache sets XmlCacheDir for the test, empty directory disables cache
+func useTestXmlCache(t *testing.T, dir string) {
+	cacheDir := XmlCacheDir
+	XmlCacheDir = dir
+	t.Cleanup(func() { XmlCacheDir = cacheDir })
+}
+
+func TestLoadGameData(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"Assets/XML/Technologies/CIV4TechInfos.xml": testTechXml,
+		"Assets/XML/Terrain/CIV4TerrainInfos.xml":   testTerrainXml,
+		"Assets/XML/Text/CIV4GameText_Test.xml":     testGameTextXml,
+		// Later file with the same type overrides the entry
+		"Assets/XML/Text/CIV4GameText_Test_Override.xml": strings.ReplaceAll(testGameTextXml, "<English>Rome</English>", "<English>Roma</English>"),
+		"Assets/XML/Schema/CIV4TechnologiesSchema.xml":   `<Schema xmlns="urn:schemas-microsoft-com:xml-data"></Schema>`,
+	})
+
+	useTestXmlCache(t, t.TempDir())
+
+	// The second run uses cache
+	for run := 0; run < 2; run++ {
+		parsed := 0
+		unsubscribe := SubscribeProgress(func(p Progress) {
+			if p.Phase == "Parsing XML files" {
+				parsed = p.Done
+			}
+		})
+		ctx, reporter := StartProgress(context.Background(), "Loading game data")
+		data, err := LoadGameData(ctx, root, "")
+		reporter.Finish(err)
+		unsubscribe()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r := data.Registries()
+		if parsed != 5 {
+			t.Errorf("Run %d: expected progress for 5 files, got %d", run, parsed)
+		}
+		if r.TechInfos["TECH_TEST_WHEEL"] == nil || r.TerrainInfos["TERRAIN_TEST_GRASS"] == nil {
+			t.Errorf("Run %d: expected tech and terrain to be loaded", run)
+		}
+		if r.LangStrings["English"]["TXT_KEY_TEST_ROME"] != "Roma" {
+			t.Errorf("Run %d: expected text from the last file, got %s", run, r.LangStrings["English"]["TXT_KEY_TEST_ROME"])
+		}
+		if !strings.HasSuffix(r.XmlTypeSources["TECH_TEST_WHEEL"], "CIV4TechInfos.xml") {
+			t.Errorf("Run %d: unexpected tech source %s", run, r.XmlTypeSources["TECH_TEST_WHEEL"])
+		}
+	}
+
+	cached, _ := os.ReadDir(XmlCacheDir)
+	if len(cached) != 5 {
+		t.Errorf("Expected 5 cache entries, got %d", len(cached))
+	}
+}
+
+func TestXmlCacheSupportedTypes(t *testing.T) {
+	useTestXmlCache(t, t.TempDir())
+
+	path := filepath.Join(t.TempDir(), "CIV4TechInfos.xml")
+	writeTestFiles(t, filepath.Dir(path), map[string]string{"CIV4TechInfos.xml": testTechXml})
+	stat, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Entry without data was written when the type was not supported yet
+	writeXMLCache(path, stat, xmlDecodeResult{XmlType: "Civ4TechInfos"})
+	if _, ok := readXMLCache(path, stat); ok {
+		t.Error("Entry without data of supported type must not be used")
+	}
+
+	writeXMLCache(path, stat, xmlDecodeResult{XmlType: "Civ4UnknownInfos"})
+	if result, ok := readXMLCache(path, stat); !ok || result.XmlType != "Civ4UnknownInfos" || result.Data != nil {
+		t.Errorf("Unexpected cache entry of unsupported type: %+v", result)
+	}
+}
+
+func TestGameDataMods(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"Assets/XML/Technologies/CIV4TechInfos.xml":             testTechXml,
+		"Mods/Test/Assets/XML/Technologies/CIV4TechInfos.xml":   strings.ReplaceAll(testTechXml, "<iCost>120</iCost>", "<iCost>240</iCost>"),
+		"Mods/Test/Assets/XML/Terrain/CIV4TerrainInfos.xml":     testTerrainXml,
+		"Mods/Other/Assets/XML/Technologies/CIV4TechInfos.xml":  strings.ReplaceAll(testTechXml, "TECH_TEST_WHEEL", "TECH_TEST_BRONZE"),
+		"Mods/Other/Assets/XML/Technologies/CIV4TechInfos2.xml": "<broken",
+	})
+
+	useTestXmlCache(t, "")
+
+	// Several game data instances are independent and can be loaded at once
+	mods := []string{"", "Test", "Other"}
+	loaded := make([]*GameData, len(mods))
+	wg := sync.WaitGroup{}
+	for i := range mods {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			loaded[i], _ = LoadGameData(context.Background(), root, mods[i])
+		}()
+	}
+	wg.Wait()
+
+	base, mod, other := loaded[0].Registries(), loaded[1].Registries(), loaded[2].Registries()
+	if base.TechInfos["TECH_TEST_WHEEL"].Cost != 120 || mod.TechInfos["TECH_TEST_WHEEL"].Cost != 240 {
+		t.Error("Expected mod to override tech cost without changing base game data")
+	}
+	if len(base.TerrainInfos) != 0 || len(mod.TerrainInfos) != 1 {
+		t.Error("Expected terrain to be loaded for mod only")
+	}
+	if other.TechInfos["TECH_TEST_BRONZE"] == nil || other.TechInfos["TECH_TEST_WHEEL"] != nil {
+		t.Error("Expected other mod to replace tech file")
+	}
+
+	// Reload replaces registries, but previously taken registries stay unchanged
+	writeTestFiles(t, root, map[string]string{
+		"Mods/Test/Assets/XML/Technologies/CIV4TechInfos.xml": strings.ReplaceAll(testTechXml, "<iCost>120</iCost>", "<iCost>360</iCost>"),
+	})
+
+	reading := make(chan struct{})
+	go func() {
+		defer close(reading)
+		for i := 0; i < 100; i++ {
+			if loaded[1].Registries().TechInfos["TECH_TEST_WHEEL"] == nil {
+				t.Error("Expected tech to be available during reload")
+				return
+			}
+		}
+	}()
+
+	if err := loaded[1].Reload(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	<-reading
+
+	if loaded[1].Registries().TechInfos["TECH_TEST_WHEEL"].Cost != 360 || mod.TechInfos["TECH_TEST_WHEEL"].Cost != 240 {
+		t.Error("Expected reload to replace registries")
+	}
+}
+
+func TestXmlLoadReport(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"Assets/XML/Technologies/CIV4TechInfos.xml":          testTechXml,
+		"Assets/XML/Technologies/CIV4TechnologiesSchema.xml": `<Schema xmlns="urn:schemas-microsoft-com:xml-data"></Schema>`,
+		"Assets/XML/Technologies/CIV4TechInfos_Extra.xml":    testTechXml,
+		"Assets/XML/Terrain/CIV4TerrainInfos.xml":            testTerrainXml,
+		"Assets/XML/Misc/CIV4Unknown.xml":                    `<Civ4UnknownInfos></Civ4UnknownInfos>`,
+		"Assets/XML/Misc/CIV4Broken.xml":                     `<Civ4EraInfos><EraInfos><EraInfo>`,
+		"Assets/XML/Misc/CIV4Empty.xml":                      `<Civ4EraInfos><OtherInfos></OtherInfos></Civ4EraInfos>`,
+	})
+
+	useTestXmlCache(t, "")
+
+	data, err := LoadGameData(context.Background(), root, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := data.Registries().XmlLoadReport
+	if len(report.Files) != 7 {
+		t.Fatalf("Expected 7 files in report, got %d", len(report.Files))
+	}
+
+	failed := report.GetFiles(XmlFileFailed)
+	if len(failed) != 1 || !strings.HasSuffix(failed[0].Path, "CIV4Broken.xml") || failed[0].Reason == "" {
+		t.Errorf("Unexpected failed files %+v", failed)
+	}
+
+	if unknown := report.GetUnknownRootTags(); len(unknown) != 1 || unknown[0] != "Civ4UnknownInfos" {
+		t.Errorf("Unexpected unknown root tags %v", unknown)
+	}
+
+	if len(report.Duplicates) != 1 || report.Duplicates[0].Type != "TECH_TEST_WHEEL" || len(report.Duplicates[0].Files) != 2 {
+		t.Errorf("Unexpected duplicates %+v", report.Duplicates)
+	}
+
+	// Terrain schema is missing, era file has no entries matching the struct
+	warnings := report.GetFilesWithWarnings()
+	if len(warnings) != 2 || !strings.HasSuffix(warnings[0].Path, "CIV4Empty.xml") || !strings.HasSuffix(warnings[1].Path, "CIV4TerrainInfos.xml") {
+		for _, w := range warnings {
+			t.Log(w.Path, w.Warnings)
+		}
+		t.Errorf("Unexpected files with warnings")
+	}
+}
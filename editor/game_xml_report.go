@@ -0,0 +1,144 @@
+package editor
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// SchemaXmlType is a root tag of XML schema files, they are not loaded but referenced by other files
+const SchemaXmlType CivXmlType = "Schema"
+
+// xmlSchemaRegex matches a schema reference in the root tag (e.g. xmlns="x-schema:CIV4TechnologiesSchema.xml")
+var xmlSchemaRegex = regexp.MustCompile(`xmlns\s*=\s*["']x-schema:([^"']+)["']`)
+
+// XmlFileStatus is a result of loading XML file
+type XmlFileStatus string
+
+const (
+	// XmlFileParsed means file was decoded and its entries were assigned to registries
+	XmlFileParsed XmlFileStatus = "parsed"
+	// XmlFileSkipped means file is not supported (schema or unknown root tag)
+	XmlFileSkipped XmlFileStatus = "skipped"
+	// XmlFileFailed means file cannot be read or decoded
+	XmlFileFailed XmlFileStatus = "failed"
+)
+
+// XmlLoadReport describes the result of loading XML files (see LoadGameRegistries)
+type XmlLoadReport struct {
+	Files []*XmlFileReport `json:"files"`
+	// Duplicates are types defined more than once, the last definition is used (as in the game)
+	Duplicates []*XmlDuplicateType `json:"duplicates"`
+	// Errors are errors of listing files (e.g. permission denied), such files are not loaded at all
+	Errors []string `json:"errors"`
+}
+
+// XmlFileReport is a loading result of a single XML file
+type XmlFileReport struct {
+	Path    string     `json:"path"`
+	XmlType CivXmlType `json:"xml_type"`
+	// Schema is a schema file name referenced by the file, empty if not set
+	Schema string        `json:"schema"`
+	Status XmlFileStatus `json:"status"`
+	// Reason explains why file is skipped or failed
+	Reason string `json:"reason"`
+	// Count is a number of assigned entries
+	Count int32 `json:"count"`
+	// Warnings are problems that don't prevent loading, but probably break the game (e.g. missing schema)
+	Warnings []string `json:"warnings"`
+}
+
+// XmlDuplicateType is a type defined in several files (or several times in one file)
+type XmlDuplicateType struct {
+	Type  string   `json:"type"`
+	Files []string `json:"files"`
+}
+
+// GetFiles returns reports of files with the status
+func (r *XmlLoadReport) GetFiles(status XmlFileStatus) []*XmlFileReport {
+	var files []*XmlFileReport
+	for _, file := range r.Files {
+		if file.Status == status {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// GetFilesWithWarnings returns reports of files having warnings
+func (r *XmlLoadReport) GetFilesWithWarnings() []*XmlFileReport {
+	var files []*XmlFileReport
+	for _, file := range r.Files {
+		if len(file.Warnings) > 0 {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// GetUnknownRootTags returns sorted root tags of skipped files, except schemas
+func (r *XmlLoadReport) GetUnknownRootTags() []string {
+	tags := make(map[string]bool)
+	for _, file := range r.GetFiles(XmlFileSkipped) {
+		if file.XmlType != SchemaXmlType && file.XmlType != "" {
+			tags[string(file.XmlType)] = true
+		}
+	}
+	return SortKeys(tags)
+}
+
+// Summary returns a short text description of the report
+func (r *XmlLoadReport) Summary() string {
+	return fmt.Sprintf("XML files: %d parsed, %d skipped, %d failed, %d with warnings; %d duplicate types, %d listing errors",
+		len(r.GetFiles(XmlFileParsed)), len(r.GetFiles(XmlFileSkipped)), len(r.GetFiles(XmlFileFailed)),
+		len(r.GetFilesWithWarnings()), len(r.Duplicates), len(r.Errors))
+}
+
+// newXmlFileReport returns a report of the decoded file before assigning it
+func newXmlFileReport(path string, result xmlDecodeResult) *XmlFileReport {
+	report := &XmlFileReport{Path: path, XmlType: result.XmlType, Schema: result.Schema, Status: XmlFileParsed}
+	switch {
+	case result.Err != nil:
+		report.Status, report.Reason = XmlFileFailed, result.Err.Error()
+	case result.XmlType == "":
+		report.Status, report.Reason = XmlFileSkipped, "root tag not found"
+	case result.XmlType == SchemaXmlType:
+		report.Status, report.Reason = XmlFileSkipped, "schema file"
+	case result.Data == nil:
+		report.Status, report.Reason = XmlFileSkipped, "unknown root tag "+string(result.XmlType)
+	}
+
+	return report
+}
+
+// checkXmlSchemas adds warnings to files referencing schemas that don't exist.
+// The game looks for the schema in the directory of the file, with the same overlay precedence as for other files.
+func checkXmlSchemas(report *XmlLoadReport, files []*GameFile) {
+	known := make(map[string]bool, len(files))
+	for _, f := range files {
+		known[strings.ToLower(filepath.ToSlash(f.RelativePath))] = true
+	}
+
+	for i, fileReport := range report.Files {
+		if fileReport.Schema == "" || fileReport.Status != XmlFileParsed {
+			continue
+		}
+
+		schemaPath := filepath.Join(filepath.Dir(files[i].RelativePath), filepath.FromSlash(fileReport.Schema))
+		if !known[strings.ToLower(filepath.ToSlash(schemaPath))] {
+			fileReport.Warnings = append(fileReport.Warnings, "schema "+fileReport.Schema+" not found")
+		}
+	}
+}
+
+// getXmlDuplicates returns types defined more than once, sorted by type
+func getXmlDuplicates(definitions map[string][]string) []*XmlDuplicateType {
+	var duplicates []*XmlDuplicateType
+	for _, t := range SortKeys(definitions) {
+		if len(definitions[t]) > 1 {
+			duplicates = append(duplicates, &XmlDuplicateType{Type: t, Files: definitions[t]})
+		}
+	}
+	return duplicates
+}
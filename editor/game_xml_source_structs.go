@@ -8,36 +8,113 @@ import "encoding/xml"
 type CivXmlType string
 
 type Civ4GameText struct {
-	XMLName xml.Name `xml:"Civ4GameText"`
-	Text    string   `xml:",chardata"`
-	TEXT    []struct {
-		Text        string `xml:",chardata"`
-		Tag         string `xml:"Tag"`
-		English     string `xml:"English"`
-		French      string `xml:"French"`
-		German      string `xml:"German"`
-		Italian     string `xml:"Italian"`
-		Spanish     string `xml:"Spanish"`
-		Polish      string `xml:"Polish"`
-		Russian     string `xml:"Russian"`
-		Czech       string `xml:"Czech"`
-		Danish      string `xml:"Danish"`
-		Greek       string `xml:"Greek"`
-		Brazilian   string `xml:"Brazilian"`
-		ChineseSimp string `xml:"ChineseSimp"`
-		Korean      string `xml:"Korean"`
-		Ukrainian   string `xml:"Ukrainian"`
-		Arabic      string `xml:"Arabic"`
-		Turkish     string `xml:"Turkish"`
-		Bulgarian   string `xml:"Bulgarian"`
-		Finnish     string `xml:"Finnish"`
-		Dutch       string `xml:"Dutch"`
-		Hungarian   string `xml:"Hungarian"`
-		Japanese    string `xml:"Japanese"`
-		Portuguese  string `xml:"Portuguese"`
-		Catalan     string `xml:"Catalan"`
-		ChineseTrad string `xml:"ChineseTrad"`
-	} `xml:"TEXT"`
+	XMLName xml.Name        `xml:"Civ4GameText"`
+	Text    string          `xml:",chardata"`
+	TEXT    []GameTextEntry `xml:"TEXT"`
+}
+
+// GameTextEntry is a single text tag with all its translations
+type GameTextEntry struct {
+	Text        string        `xml:",chardata"`
+	Tag         string        `xml:"Tag"`
+	English     GameTextValue `xml:"English"`
+	French      GameTextValue `xml:"French"`
+	German      GameTextValue `xml:"German"`
+	Italian     GameTextValue `xml:"Italian"`
+	Spanish     GameTextValue `xml:"Spanish"`
+	Polish      GameTextValue `xml:"Polish"`
+	Russian     GameTextValue `xml:"Russian"`
+	Czech       GameTextValue `xml:"Czech"`
+	Danish      GameTextValue `xml:"Danish"`
+	Greek       GameTextValue `xml:"Greek"`
+	Brazilian   GameTextValue `xml:"Brazilian"`
+	ChineseSimp GameTextValue `xml:"ChineseSimp"`
+	Korean      GameTextValue `xml:"Korean"`
+	Ukrainian   GameTextValue `xml:"Ukrainian"`
+	Arabic      GameTextValue `xml:"Arabic"`
+	Turkish     GameTextValue `xml:"Turkish"`
+	Bulgarian   GameTextValue `xml:"Bulgarian"`
+	Finnish     GameTextValue `xml:"Finnish"`
+	Dutch       GameTextValue `xml:"Dutch"`
+	Hungarian   GameTextValue `xml:"Hungarian"`
+	Japanese    GameTextValue `xml:"Japanese"`
+	Portuguese  GameTextValue `xml:"Portuguese"`
+	Catalan     GameTextValue `xml:"Catalan"`
+	ChineseTrad GameTextValue `xml:"ChineseTrad"`
+}
+
+// GameTextValue is a translation of the game text. Usually it's a plain text, like <English>Rome</English>,
+// but some languages use a nested form with grammatical forms: <French><Text>Rome</Text><Gender>Female</Gender><Plural>0</Plural></French>
+type GameTextValue struct {
+	Text   string
+	Gender string
+	Plural string
+}
+
+// UnmarshalXML decodes both plain and nested forms of the translation
+func (v *GameTextValue) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var raw struct {
+		Chardata string   `xml:",chardata"`
+		Text     []string `xml:"Text"`
+		Gender   []string `xml:"Gender"`
+		Plural   []string `xml:"Plural"`
+	}
+
+	if err := d.DecodeElement(&raw, &start); err != nil {
+		return err
+	}
+
+	if len(raw.Text) == 0 {
+		v.Text = raw.Chardata
+		return nil
+	}
+
+	// Only the first form is used, as the editor doesn't need grammatical variants
+	v.Text = raw.Text[0]
+	if len(raw.Gender) > 0 {
+		v.Gender = raw.Gender[0]
+	}
+	if len(raw.Plural) > 0 {
+		v.Plural = raw.Plural[0]
+	}
+	return nil
+}
+
+// Translations returns all non-empty translations of the entry by language name (see GameLanguages)
+func (e *GameTextEntry) Translations() map[string]string {
+	result := make(map[string]string)
+	for language, value := range map[string]GameTextValue{
+		"English":     e.English,
+		"French":      e.French,
+		"German":      e.German,
+		"Italian":     e.Italian,
+		"Spanish":     e.Spanish,
+		"Polish":      e.Polish,
+		"Russian":     e.Russian,
+		"Czech":       e.Czech,
+		"Danish":      e.Danish,
+		"Greek":       e.Greek,
+		"Brazilian":   e.Brazilian,
+		"ChineseSimp": e.ChineseSimp,
+		"Korean":      e.Korean,
+		"Ukrainian":   e.Ukrainian,
+		"Arabic":      e.Arabic,
+		"Turkish":     e.Turkish,
+		"Bulgarian":   e.Bulgarian,
+		"Finnish":     e.Finnish,
+		"Dutch":       e.Dutch,
+		"Hungarian":   e.Hungarian,
+		"Japanese":    e.Japanese,
+		"Portuguese":  e.Portuguese,
+		"Catalan":     e.Catalan,
+		"ChineseTrad": e.ChineseTrad,
+	} {
+		if value.Text != "" {
+			result[language] = value.Text
+		}
+	}
+
+	return result
 }
 
 type Civ4Defines struct {
@@ -243,3 +320,874 @@ type Civ4VictoryInfo struct {
 		} `xml:"VictoryInfo"`
 	} `xml:"VictoryInfos"`
 }
+
+type Civ4TerrainInfos struct {
+	XMLName      xml.Name `xml:"Civ4TerrainInfos"`
+	Text         string   `xml:",chardata"`
+	Xmlns        string   `xml:"xmlns,attr"`
+	TerrainInfos struct {
+		Text        string `xml:",chardata"`
+		TerrainInfo []struct {
+			Text         string `xml:",chardata"`
+			Type         string `xml:"Type"`
+			Description  string `xml:"Description"`
+			Civilopedia  string `xml:"Civilopedia"`
+			ArtDefineTag string `xml:"ArtDefineTag"`
+			Yields       struct {
+				Text   string   `xml:",chardata"`
+				IYield []string `xml:"iYield"`
+			} `xml:"Yields"`
+			RiverYieldChange struct {
+				Text   string   `xml:",chardata"`
+				IYield []string `xml:"iYield"`
+			} `xml:"RiverYieldChange"`
+			HillsYieldChange struct {
+				Text   string   `xml:",chardata"`
+				IYield []string `xml:"iYield"`
+			} `xml:"HillsYieldChange"`
+			BWater           string `xml:"bWater"`
+			BImpassable      string `xml:"bImpassable"`
+			BFound           string `xml:"bFound"`
+			BFoundCoast      string `xml:"bFoundCoast"`
+			BFoundFreshWater string `xml:"bFoundFreshWater"`
+			IMovement        string `xml:"iMovement"`
+			ISeeFrom         string `xml:"iSeeFrom"`
+			ISeeThrough      string `xml:"iSeeThrough"`
+			IBuildModifier   string `xml:"iBuildModifier"`
+			IDefense         string `xml:"iDefense"`
+			Button           string `xml:"Button"`
+		} `xml:"TerrainInfo"`
+	} `xml:"TerrainInfos"`
+}
+
+type Civ4FeatureInfos struct {
+	XMLName      xml.Name `xml:"Civ4FeatureInfos"`
+	Text         string   `xml:",chardata"`
+	Xmlns        string   `xml:"xmlns,attr"`
+	FeatureInfos struct {
+		Text        string `xml:",chardata"`
+		FeatureInfo []struct {
+			Text         string `xml:",chardata"`
+			Type         string `xml:"Type"`
+			Description  string `xml:"Description"`
+			Civilopedia  string `xml:"Civilopedia"`
+			Help         string `xml:"Help"`
+			ArtDefineTag string `xml:"ArtDefineTag"`
+			YieldChanges struct {
+				Text         string   `xml:",chardata"`
+				IYieldChange []string `xml:"iYieldChange"`
+			} `xml:"YieldChanges"`
+			RiverYieldChange struct {
+				Text   string   `xml:",chardata"`
+				IYield []string `xml:"iYield"`
+			} `xml:"RiverYieldChange"`
+			HillsYieldChange struct {
+				Text   string   `xml:",chardata"`
+				IYield []string `xml:"iYield"`
+			} `xml:"HillsYieldChange"`
+			IMovement          string `xml:"iMovement"`
+			ISeeThrough        string `xml:"iSeeThrough"`
+			IHealthPercent     string `xml:"iHealthPercent"`
+			IAppearance        string `xml:"iAppearance"`
+			IDisappearance     string `xml:"iDisappearance"`
+			IGrowth            string `xml:"iGrowth"`
+			ITurnDamage        string `xml:"iTurnDamage"`
+			BNoCoast           string `xml:"bNoCoast"`
+			BNoRiver           string `xml:"bNoRiver"`
+			BNoAdjacent        string `xml:"bNoAdjacent"`
+			BRequiresFlatlands string `xml:"bRequiresFlatlands"`
+			BRequiresRiver     string `xml:"bRequiresRiver"`
+			BAddsFreshWater    string `xml:"bAddsFreshWater"`
+			BImpassable        string `xml:"bImpassable"`
+			BNoCity            string `xml:"bNoCity"`
+			BNoImprovement     string `xml:"bNoImprovement"`
+			BVisibleAlways     string `xml:"bVisibleAlways"`
+			BNukeImmune        string `xml:"bNukeImmune"`
+			OnUnitChangeTo     string `xml:"OnUnitChangeTo"`
+			TerrainBooleans    struct {
+				Text           string `xml:",chardata"`
+				TerrainBoolean []struct {
+					Text        string `xml:",chardata"`
+					TerrainType string `xml:"TerrainType"`
+					BTerrain    string `xml:"bTerrain"`
+				} `xml:"TerrainBoolean"`
+			} `xml:"TerrainBooleans"`
+		} `xml:"FeatureInfo"`
+	} `xml:"FeatureInfos"`
+}
+
+type Civ4BonusInfos struct {
+	XMLName    xml.Name `xml:"Civ4BonusInfos"`
+	Text       string   `xml:",chardata"`
+	Xmlns      string   `xml:"xmlns,attr"`
+	BonusInfos struct {
+		Text      string `xml:",chardata"`
+		BonusInfo []struct {
+			Text           string `xml:",chardata"`
+			Type           string `xml:"Type"`
+			Description    string `xml:"Description"`
+			Civilopedia    string `xml:"Civilopedia"`
+			BonusClassType string `xml:"BonusClassType"`
+			ArtDefineTag   string `xml:"ArtDefineTag"`
+			TechReveal     string `xml:"TechReveal"`
+			TechCityTrade  string `xml:"TechCityTrade"`
+			TechObsolete   string `xml:"TechObsolete"`
+			YieldChanges   struct {
+				Text         string   `xml:",chardata"`
+				IYieldChange []string `xml:"iYieldChange"`
+			} `xml:"YieldChanges"`
+			IAITradeModifier string `xml:"iAITradeModifier"`
+			IHealth          string `xml:"iHealth"`
+			IHappiness       string `xml:"iHappiness"`
+			IPlacementOrder  string `xml:"iPlacementOrder"`
+			IConstAppearance string `xml:"iConstAppearance"`
+			IMinAreaSize     string `xml:"iMinAreaSize"`
+			IMinLatitude     string `xml:"iMinLatitude"`
+			IMaxLatitude     string `xml:"iMaxLatitude"`
+			IPlayer          string `xml:"iPlayer"`
+			ITilesPer        string `xml:"iTilesPer"`
+			IMinLandPercent  string `xml:"iMinLandPercent"`
+			IUnique          string `xml:"iUnique"`
+			IGroupRange      string `xml:"iGroupRange"`
+			IGroupRand       string `xml:"iGroupRand"`
+			BArea            string `xml:"bArea"`
+			BHills           string `xml:"bHills"`
+			BFlatlands       string `xml:"bFlatlands"`
+			BNoRiverSide     string `xml:"bNoRiverSide"`
+			BNormalize       string `xml:"bNormalize"`
+			TerrainBooleans  struct {
+				Text           string `xml:",chardata"`
+				TerrainBoolean []struct {
+					Text        string `xml:",chardata"`
+					TerrainType string `xml:"TerrainType"`
+					BTerrain    string `xml:"bTerrain"`
+				} `xml:"TerrainBoolean"`
+			} `xml:"TerrainBooleans"`
+			FeatureBooleans struct {
+				Text           string `xml:",chardata"`
+				FeatureBoolean []struct {
+					Text        string `xml:",chardata"`
+					FeatureType string `xml:"FeatureType"`
+					BFeature    string `xml:"bFeature"`
+				} `xml:"FeatureBoolean"`
+			} `xml:"FeatureBooleans"`
+			FeatureTerrainBooleans struct {
+				Text                  string `xml:",chardata"`
+				FeatureTerrainBoolean []struct {
+					Text            string `xml:",chardata"`
+					TerrainType     string `xml:"TerrainType"`
+					BFeatureTerrain string `xml:"bFeatureTerrain"`
+				} `xml:"FeatureTerrainBoolean"`
+			} `xml:"FeatureTerrainBooleans"`
+		} `xml:"BonusInfo"`
+	} `xml:"BonusInfos"`
+}
+
+type Civ4ImprovementInfos struct {
+	XMLName          xml.Name `xml:"Civ4ImprovementInfos"`
+	Text             string   `xml:",chardata"`
+	Xmlns            string   `xml:"xmlns,attr"`
+	ImprovementInfos struct {
+		Text            string `xml:",chardata"`
+		ImprovementInfo []struct {
+			Text               string `xml:",chardata"`
+			Type               string `xml:"Type"`
+			Description        string `xml:"Description"`
+			Civilopedia        string `xml:"Civilopedia"`
+			ArtDefineTag       string `xml:"ArtDefineTag"`
+			PrereqNatureYields struct {
+				Text   string   `xml:",chardata"`
+				IYield []string `xml:"iYield"`
+			} `xml:"PrereqNatureYields"`
+			YieldIncreases struct {
+				Text   string   `xml:",chardata"`
+				IYield []string `xml:"iYield"`
+			} `xml:"YieldIncreases"`
+			RiverSideYieldChange struct {
+				Text   string   `xml:",chardata"`
+				IYield []string `xml:"iYield"`
+			} `xml:"RiverSideYieldChange"`
+			HillsYieldChange struct {
+				Text   string   `xml:",chardata"`
+				IYield []string `xml:"iYield"`
+			} `xml:"HillsYieldChange"`
+			IrrigatedYieldChange struct {
+				Text   string   `xml:",chardata"`
+				IYield []string `xml:"iYield"`
+			} `xml:"IrrigatedYieldChange"`
+			BHillsMakesValid      string `xml:"bHillsMakesValid"`
+			BFreshWaterMakesValid string `xml:"bFreshWaterMakesValid"`
+			BRiverSideMakesValid  string `xml:"bRiverSideMakesValid"`
+			BNoFreshWater         string `xml:"bNoFreshWater"`
+			BRequiresFlatlands    string `xml:"bRequiresFlatlands"`
+			BRequiresRiverSide    string `xml:"bRequiresRiverSide"`
+			BRequiresIrrigation   string `xml:"bRequiresIrrigation"`
+			BCarriesIrrigation    string `xml:"bCarriesIrrigation"`
+			BRequiresFeature      string `xml:"bRequiresFeature"`
+			BWater                string `xml:"bWater"`
+			BGoody                string `xml:"bGoody"`
+			BPermanent            string `xml:"bPermanent"`
+			IAdvancedStartCost    string `xml:"iAdvancedStartCost"`
+			ITilesPerGoody        string `xml:"iTilesPerGoody"`
+			IGoodyRange           string `xml:"iGoodyRange"`
+			IFeatureGrowth        string `xml:"iFeatureGrowth"`
+			IUpkeep               string `xml:"iUpkeep"`
+			IHealthPercent        string `xml:"iHealthPercent"`
+			IDefenseModifier      string `xml:"iDefenseModifier"`
+			IHappiness            string `xml:"iHappiness"`
+			IPillageGold          string `xml:"iPillageGold"`
+			BOutsideBorders       string `xml:"bOutsideBorders"`
+			TerrainMakesValids    struct {
+				Text              string `xml:",chardata"`
+				TerrainMakesValid []struct {
+					Text        string `xml:",chardata"`
+					TerrainType string `xml:"TerrainType"`
+					BMakesValid string `xml:"bMakesValid"`
+				} `xml:"TerrainMakesValid"`
+			} `xml:"TerrainMakesValids"`
+			FeatureMakesValids struct {
+				Text              string `xml:",chardata"`
+				FeatureMakesValid []struct {
+					Text        string `xml:",chardata"`
+					FeatureType string `xml:"FeatureType"`
+					BMakesValid string `xml:"bMakesValid"`
+				} `xml:"FeatureMakesValid"`
+			} `xml:"FeatureMakesValids"`
+			BonusTypeStructs struct {
+				Text            string `xml:",chardata"`
+				BonusTypeStruct []struct {
+					Text             string `xml:",chardata"`
+					BonusType        string `xml:"BonusType"`
+					BBonusMakesValid string `xml:"bBonusMakesValid"`
+					BBonusTrade      string `xml:"bBonusTrade"`
+					IDiscoverRand    string `xml:"iDiscoverRand"`
+					YieldIncreases   struct {
+						Text   string   `xml:",chardata"`
+						IYield []string `xml:"iYield"`
+					} `xml:"YieldIncreases"`
+				} `xml:"BonusTypeStruct"`
+			} `xml:"BonusTypeStructs"`
+			ImprovementPillage string `xml:"ImprovementPillage"`
+			ImprovementUpgrade string `xml:"ImprovementUpgrade"`
+			IUpgradeTime       string `xml:"iUpgradeTime"`
+			IAIWeight          string `xml:"iAIWeight"`
+			Button             string `xml:"Button"`
+		} `xml:"ImprovementInfo"`
+	} `xml:"ImprovementInfos"`
+}
+
+type Civ4RouteInfos struct {
+	XMLName    xml.Name `xml:"Civ4RouteInfos"`
+	Text       string   `xml:",chardata"`
+	Xmlns      string   `xml:"xmlns,attr"`
+	RouteInfos struct {
+		Text      string `xml:",chardata"`
+		RouteInfo []struct {
+			Text               string `xml:",chardata"`
+			Type               string `xml:"Type"`
+			Description        string `xml:"Description"`
+			Civilopedia        string `xml:"Civilopedia"`
+			IAdvancedStartCost string `xml:"iAdvancedStartCost"`
+			IValue             string `xml:"iValue"`
+			IMovement          string `xml:"iMovement"`
+			IFlatMovement      string `xml:"iFlatMovement"`
+			BonusType          string `xml:"BonusType"`
+			PrereqOrBonuses    struct {
+				Text      string   `xml:",chardata"`
+				BonusType []string `xml:"BonusType"`
+			} `xml:"PrereqOrBonuses"`
+			Yields struct {
+				Text   string   `xml:",chardata"`
+				IYield []string `xml:"iYield"`
+			} `xml:"Yields"`
+			Button string `xml:"Button"`
+		} `xml:"RouteInfo"`
+	} `xml:"RouteInfos"`
+}
+
+type Civ4UnitInfos struct {
+	XMLName   xml.Name `xml:"Civ4UnitInfos"`
+	Text      string   `xml:",chardata"`
+	Xmlns     string   `xml:"xmlns,attr"`
+	UnitInfos struct {
+		Text     string `xml:",chardata"`
+		UnitInfo []struct {
+			Text               string `xml:",chardata"`
+			Class              string `xml:"Class"`
+			Type               string `xml:"Type"`
+			Special            string `xml:"Special"`
+			Capture            string `xml:"Capture"`
+			Combat             string `xml:"Combat"`
+			Domain             string `xml:"Domain"`
+			DefaultUnitAI      string `xml:"DefaultUnitAI"`
+			Invisible          string `xml:"Invisible"`
+			SeeInvisible       string `xml:"SeeInvisible"`
+			Description        string `xml:"Description"`
+			Civilopedia        string `xml:"Civilopedia"`
+			Strategy           string `xml:"Strategy"`
+			Advisor            string `xml:"Advisor"`
+			BAnimal            string `xml:"bAnimal"`
+			BFood              string `xml:"bFood"`
+			BNoBadGoodies      string `xml:"bNoBadGoodies"`
+			BOnlyDefensive     string `xml:"bOnlyDefensive"`
+			BNoCapture         string `xml:"bNoCapture"`
+			BFound             string `xml:"bFound"`
+			BMilitaryHappiness string `xml:"bMilitaryHappiness"`
+			UnitAIs            struct {
+				Text   string `xml:",chardata"`
+				UnitAI []struct {
+					Text       string `xml:",chardata"`
+					UnitAIType string `xml:"UnitAIType"`
+					BUnitAI    string `xml:"bUnitAI"`
+				} `xml:"UnitAI"`
+			} `xml:"UnitAIs"`
+			PrereqBuilding string `xml:"PrereqBuilding"`
+			PrereqTech     string `xml:"PrereqTech"`
+			TechTypes      struct {
+				Text       string   `xml:",chardata"`
+				PrereqTech []string `xml:"PrereqTech"`
+			} `xml:"TechTypes"`
+			BonusType     string `xml:"BonusType"`
+			PrereqBonuses struct {
+				Text      string   `xml:",chardata"`
+				BonusType []string `xml:"BonusType"`
+			} `xml:"PrereqBonuses"`
+			ICost              string `xml:"iCost"`
+			IAdvancedStartCost string `xml:"iAdvancedStartCost"`
+			IMinAreaSize       string `xml:"iMinAreaSize"`
+			IMoves             string `xml:"iMoves"`
+			IAirRange          string `xml:"iAirRange"`
+			ICombat            string `xml:"iCombat"`
+			IAirCombat         string `xml:"iAirCombat"`
+			IXPValueAttack     string `xml:"iXPValueAttack"`
+			IXPValueDefense    string `xml:"iXPValueDefense"`
+			FreePromotions     struct {
+				Text          string `xml:",chardata"`
+				FreePromotion []struct {
+					Text           string `xml:",chardata"`
+					PromotionType  string `xml:"PromotionType"`
+					BFreePromotion string `xml:"bFreePromotion"`
+				} `xml:"FreePromotion"`
+			} `xml:"FreePromotions"`
+			Button string `xml:"Button"`
+		} `xml:"UnitInfo"`
+	} `xml:"UnitInfos"`
+}
+
+type Civ4PromotionInfos struct {
+	XMLName        xml.Name `xml:"Civ4PromotionInfos"`
+	Text           string   `xml:",chardata"`
+	Xmlns          string   `xml:"xmlns,attr"`
+	PromotionInfos struct {
+		Text          string `xml:",chardata"`
+		PromotionInfo []struct {
+			Text                  string `xml:",chardata"`
+			Type                  string `xml:"Type"`
+			Description           string `xml:"Description"`
+			Sound                 string `xml:"Sound"`
+			PromotionPrereq       string `xml:"PromotionPrereq"`
+			PromotionPrereqOr1    string `xml:"PromotionPrereqOr1"`
+			PromotionPrereqOr2    string `xml:"PromotionPrereqOr2"`
+			TechPrereq            string `xml:"TechPrereq"`
+			StateReligionPrereq   string `xml:"StateReligionPrereq"`
+			BLeader               string `xml:"bLeader"`
+			BBlitz                string `xml:"bBlitz"`
+			BAmphib               string `xml:"bAmphib"`
+			BRiver                string `xml:"bRiver"`
+			BEnemyRoute           string `xml:"bEnemyRoute"`
+			BAlwaysHeal           string `xml:"bAlwaysHeal"`
+			BHillsDoubleMove      string `xml:"bHillsDoubleMove"`
+			BImmuneToFirstStrikes string `xml:"bImmuneToFirstStrikes"`
+			IVisibilityChange     string `xml:"iVisibilityChange"`
+			IMovesChange          string `xml:"iMovesChange"`
+			ICombatPercent        string `xml:"iCombatPercent"`
+			UnitCombats           struct {
+				Text       string `xml:",chardata"`
+				UnitCombat []struct {
+					Text           string `xml:",chardata"`
+					UnitCombatType string `xml:"UnitCombatType"`
+					BUnitCombat    string `xml:"bUnitCombat"`
+				} `xml:"UnitCombat"`
+			} `xml:"UnitCombats"`
+			Button string `xml:"Button"`
+		} `xml:"PromotionInfo"`
+	} `xml:"PromotionInfos"`
+}
+
+type Civ4BuildingInfos struct {
+	XMLName       xml.Name `xml:"Civ4BuildingInfos"`
+	Text          string   `xml:",chardata"`
+	Xmlns         string   `xml:"xmlns,attr"`
+	BuildingInfos struct {
+		Text         string `xml:",chardata"`
+		BuildingInfo []struct {
+			Text                string `xml:",chardata"`
+			BuildingClass       string `xml:"BuildingClass"`
+			Type                string `xml:"Type"`
+			SpecialBuildingType string `xml:"SpecialBuildingType"`
+			Description         string `xml:"Description"`
+			Civilopedia         string `xml:"Civilopedia"`
+			Strategy            string `xml:"Strategy"`
+			Help                string `xml:"Help"`
+			Advisor             string `xml:"Advisor"`
+			ArtDefineTag        string `xml:"ArtDefineTag"`
+			HolyCity            string `xml:"HolyCity"`
+			ReligionType        string `xml:"ReligionType"`
+			StateReligion       string `xml:"StateReligion"`
+			BStateReligion      string `xml:"bStateReligion"`
+			PrereqReligion      string `xml:"PrereqReligion"`
+			VictoryPrereq       string `xml:"VictoryPrereq"`
+			FreeStartEra        string `xml:"FreeStartEra"`
+			MaxStartEra         string `xml:"MaxStartEra"`
+			ObsoleteTech        string `xml:"ObsoleteTech"`
+			PrereqTech          string `xml:"PrereqTech"`
+			TechTypes           struct {
+				Text       string   `xml:",chardata"`
+				PrereqTech []string `xml:"PrereqTech"`
+			} `xml:"TechTypes"`
+			Bonus         string `xml:"Bonus"`
+			PrereqBonuses struct {
+				Text      string   `xml:",chardata"`
+				BonusType []string `xml:"BonusType"`
+			} `xml:"PrereqBonuses"`
+			ICost              string `xml:"iCost"`
+			IAdvancedStartCost string `xml:"iAdvancedStartCost"`
+			IMinAreaSize       string `xml:"iMinAreaSize"`
+			BWater             string `xml:"bWater"`
+			BRiver             string `xml:"bRiver"`
+			BCapital           string `xml:"bCapital"`
+			BGovernmentCenter  string `xml:"bGovernmentCenter"`
+			BNukeImmune        string `xml:"bNukeImmune"`
+			IHealth            string `xml:"iHealth"`
+			IHappiness         string `xml:"iHappiness"`
+			IDefense           string `xml:"iDefense"`
+			YieldChanges       struct {
+				Text         string   `xml:",chardata"`
+				IYieldChange []string `xml:"iYieldChange"`
+			} `xml:"YieldChanges"`
+			Button string `xml:"Button"`
+		} `xml:"BuildingInfo"`
+	} `xml:"BuildingInfos"`
+}
+
+type Civ4TechInfos struct {
+	XMLName   xml.Name `xml:"Civ4TechInfos"`
+	Text      string   `xml:",chardata"`
+	Xmlns     string   `xml:"xmlns,attr"`
+	TechInfos struct {
+		Text     string `xml:",chardata"`
+		TechInfo []struct {
+			Text                       string `xml:",chardata"`
+			Type                       string `xml:"Type"`
+			Description                string `xml:"Description"`
+			Civilopedia                string `xml:"Civilopedia"`
+			Help                       string `xml:"Help"`
+			Strategy                   string `xml:"Strategy"`
+			Advisor                    string `xml:"Advisor"`
+			IAIWeight                  string `xml:"iAIWeight"`
+			IAITradeModifier           string `xml:"iAITradeModifier"`
+			ICost                      string `xml:"iCost"`
+			IAdvancedStartCost         string `xml:"iAdvancedStartCost"`
+			IAdvancedStartCostIncrease string `xml:"iAdvancedStartCostIncrease"`
+			Era                        string `xml:"Era"`
+			FirstFreeUnitClass         string `xml:"FirstFreeUnitClass"`
+			IFeatureProductionModifier string `xml:"iFeatureProductionModifier"`
+			IWorkerSpeedModifier       string `xml:"iWorkerSpeedModifier"`
+			ITradeRoutes               string `xml:"iTradeRoutes"`
+			IHealth                    string `xml:"iHealth"`
+			IHappiness                 string `xml:"iHappiness"`
+			IFirstFreeTechs            string `xml:"iFirstFreeTechs"`
+			IAsset                     string `xml:"iAsset"`
+			IPower                     string `xml:"iPower"`
+			BRepeat                    string `xml:"bRepeat"`
+			BTrade                     string `xml:"bTrade"`
+			BDisable                   string `xml:"bDisable"`
+			BGoodyTech                 string `xml:"bGoodyTech"`
+			BExtraWaterSeeFrom         string `xml:"bExtraWaterSeeFrom"`
+			BMapCentering              string `xml:"bMapCentering"`
+			BMapVisible                string `xml:"bMapVisible"`
+			BMapTrading                string `xml:"bMapTrading"`
+			BTechTrading               string `xml:"bTechTrading"`
+			BGoldTrading               string `xml:"bGoldTrading"`
+			BOpenBordersTrading        string `xml:"bOpenBordersTrading"`
+			BDefensivePactTrading      string `xml:"bDefensivePactTrading"`
+			BPermanentAllianceTrading  string `xml:"bPermanentAllianceTrading"`
+			BVassalTrading             string `xml:"bVassalTrading"`
+			BBridgeBuilding            string `xml:"bBridgeBuilding"`
+			BIrrigation                string `xml:"bIrrigation"`
+			BIgnoreIrrigation          string `xml:"bIgnoreIrrigation"`
+			BWaterWork                 string `xml:"bWaterWork"`
+			IGridX                     string `xml:"iGridX"`
+			IGridY                     string `xml:"iGridY"`
+			OrPreReqs                  struct {
+				Text       string   `xml:",chardata"`
+				PrereqTech []string `xml:"PrereqTech"`
+			} `xml:"OrPreReqs"`
+			AndPreReqs struct {
+				Text       string   `xml:",chardata"`
+				PrereqTech []string `xml:"PrereqTech"`
+			} `xml:"AndPreReqs"`
+			Button string `xml:"Button"`
+		} `xml:"TechInfo"`
+	} `xml:"TechInfos"`
+}
+
+type Civ4CivilizationInfos struct {
+	XMLName           xml.Name `xml:"Civ4CivilizationInfos"`
+	Text              string   `xml:",chardata"`
+	Xmlns             string   `xml:"xmlns,attr"`
+	CivilizationInfos struct {
+		Text             string `xml:",chardata"`
+		CivilizationInfo []struct {
+			Text                    string `xml:",chardata"`
+			Type                    string `xml:"Type"`
+			Description             string `xml:"Description"`
+			ShortDescription        string `xml:"ShortDescription"`
+			Adjective               string `xml:"Adjective"`
+			Civilopedia             string `xml:"Civilopedia"`
+			DefaultPlayerColor      string `xml:"DefaultPlayerColor"`
+			ArtDefineTag            string `xml:"ArtDefineTag"`
+			ArtStyleType            string `xml:"ArtStyleType"`
+			UnitArtStyleType        string `xml:"UnitArtStyleType"`
+			IActionSoundScriptId    string `xml:"iActionSoundScriptId"`
+			ISelectionSoundScriptId string `xml:"iSelectionSoundScriptId"`
+			BPlayable               string `xml:"bPlayable"`
+			BAIPlayable             string `xml:"bAIPlayable"`
+			Cities                  struct {
+				Text string   `xml:",chardata"`
+				City []string `xml:"City"`
+			} `xml:"Cities"`
+			Buildings struct {
+				Text     string `xml:",chardata"`
+				Building []struct {
+					Text              string `xml:",chardata"`
+					BuildingClassType string `xml:"BuildingClassType"`
+					BuildingType      string `xml:"BuildingType"`
+				} `xml:"Building"`
+			} `xml:"Buildings"`
+			Units struct {
+				Text string `xml:",chardata"`
+				Unit []struct {
+					Text          string `xml:",chardata"`
+					UnitClassType string `xml:"UnitClassType"`
+					UnitType      string `xml:"UnitType"`
+				} `xml:"Unit"`
+			} `xml:"Units"`
+			FreeTechs struct {
+				Text     string `xml:",chardata"`
+				FreeTech []struct {
+					Text      string `xml:",chardata"`
+					TechType  string `xml:"TechType"`
+					BFreeTech string `xml:"bFreeTech"`
+				} `xml:"FreeTech"`
+			} `xml:"FreeTechs"`
+			InitialCivics struct {
+				Text      string   `xml:",chardata"`
+				CivicType []string `xml:"CivicType"`
+			} `xml:"InitialCivics"`
+			Leaders struct {
+				Text   string `xml:",chardata"`
+				Leader []struct {
+					Text                string `xml:",chardata"`
+					LeaderName          string `xml:"LeaderName"`
+					BLeaderAvailability string `xml:"bLeaderAvailability"`
+				} `xml:"Leader"`
+			} `xml:"Leaders"`
+			CivilizationSelectionSound string `xml:"CivilizationSelectionSound"`
+			CivilizationActionSound    string `xml:"CivilizationActionSound"`
+			Button                     string `xml:"Button"`
+		} `xml:"CivilizationInfo"`
+	} `xml:"CivilizationInfos"`
+}
+
+type Civ4LeaderHeadInfos struct {
+	XMLName         xml.Name `xml:"Civ4LeaderHeadInfos"`
+	Text            string   `xml:",chardata"`
+	Xmlns           string   `xml:"xmlns,attr"`
+	LeaderHeadInfos struct {
+		Text           string `xml:",chardata"`
+		LeaderHeadInfo []struct {
+			Text                 string `xml:",chardata"`
+			Type                 string `xml:"Type"`
+			Description          string `xml:"Description"`
+			Civilopedia          string `xml:"Civilopedia"`
+			ArtDefineTag         string `xml:"ArtDefineTag"`
+			IWonderConstructRand string `xml:"iWonderConstructRand"`
+			IBaseAttitude        string `xml:"iBaseAttitude"`
+			IBasePeaceWeight     string `xml:"iBasePeaceWeight"`
+			IPeaceWeightRand     string `xml:"iPeaceWeightRand"`
+			IWarmongerRespect    string `xml:"iWarmongerRespect"`
+			Traits               struct {
+				Text  string `xml:",chardata"`
+				Trait []struct {
+					Text      string `xml:",chardata"`
+					TraitType string `xml:"TraitType"`
+					BTrait    string `xml:"bTrait"`
+				} `xml:"Trait"`
+			} `xml:"Traits"`
+			FavoriteCivic    string `xml:"FavoriteCivic"`
+			FavoriteReligion string `xml:"FavoriteReligion"`
+		} `xml:"LeaderHeadInfo"`
+	} `xml:"LeaderHeadInfos"`
+}
+
+type Civ4PlayerColorInfos struct {
+	XMLName          xml.Name `xml:"Civ4PlayerColorInfos"`
+	Text             string   `xml:",chardata"`
+	Xmlns            string   `xml:"xmlns,attr"`
+	PlayerColorInfos struct {
+		Text            string `xml:",chardata"`
+		PlayerColorInfo []struct {
+			Text               string `xml:",chardata"`
+			Type               string `xml:"Type"`
+			Description        string `xml:"Description"`
+			ColorTypePrimary   string `xml:"ColorTypePrimary"`
+			ColorTypeSecondary string `xml:"ColorTypeSecondary"`
+			TextColorType      string `xml:"TextColorType"`
+		} `xml:"PlayerColorInfo"`
+	} `xml:"PlayerColorInfos"`
+}
+
+type Civ4ColorVals struct {
+	XMLName   xml.Name `xml:"Civ4ColorVals"`
+	Text      string   `xml:",chardata"`
+	Xmlns     string   `xml:"xmlns,attr"`
+	ColorVals struct {
+		Text     string `xml:",chardata"`
+		ColorVal []struct {
+			Text   string `xml:",chardata"`
+			Type   string `xml:"Type"`
+			FRed   string `xml:"fRed"`
+			FGreen string `xml:"fGreen"`
+			FBlue  string `xml:"fBlue"`
+			FAlpha string `xml:"fAlpha"`
+		} `xml:"ColorVal"`
+	} `xml:"ColorVals"`
+}
+
+type Civ4ReligionInfo struct {
+	XMLName       xml.Name `xml:"Civ4ReligionInfo"`
+	Text          string   `xml:",chardata"`
+	Xmlns         string   `xml:"xmlns,attr"`
+	ReligionInfos struct {
+		Text         string `xml:",chardata"`
+		ReligionInfo []struct {
+			Text              string `xml:",chardata"`
+			Type              string `xml:"Type"`
+			Description       string `xml:"Description"`
+			Civilopedia       string `xml:"Civilopedia"`
+			Adjective         string `xml:"Adjective"`
+			TechPrereq        string `xml:"TechPrereq"`
+			FreeUnit          string `xml:"FreeUnit"`
+			IFreeUnits        string `xml:"iFreeUnits"`
+			ISpreadFactor     string `xml:"iSpreadFactor"`
+			IMissionType      string `xml:"iMissionType"`
+			Button            string `xml:"Button"`
+			TechButton        string `xml:"TechButton"`
+			GenericTechButton string `xml:"GenericTechButton"`
+			MovieFile         string `xml:"MovieFile"`
+			MovieSound        string `xml:"MovieSound"`
+			Sound             string `xml:"Sound"`
+		} `xml:"ReligionInfo"`
+	} `xml:"ReligionInfos"`
+}
+
+type Civ4CivicInfos struct {
+	XMLName    xml.Name `xml:"Civ4CivicInfos"`
+	Text       string   `xml:",chardata"`
+	Xmlns      string   `xml:"xmlns,attr"`
+	CivicInfos struct {
+		Text      string `xml:",chardata"`
+		CivicInfo []struct {
+			Text                      string `xml:",chardata"`
+			CivicOptionType           string `xml:"CivicOptionType"`
+			Type                      string `xml:"Type"`
+			Description               string `xml:"Description"`
+			Civilopedia               string `xml:"Civilopedia"`
+			Strategy                  string `xml:"Strategy"`
+			Help                      string `xml:"Help"`
+			TechPrereq                string `xml:"TechPrereq"`
+			IAnarchyLength            string `xml:"iAnarchyLength"`
+			Upkeep                    string `xml:"Upkeep"`
+			IAIWeight                 string `xml:"iAIWeight"`
+			BMilitaryFoodProduction   string `xml:"bMilitaryFoodProduction"`
+			BNoUnhealthyPopulation    string `xml:"bNoUnhealthyPopulation"`
+			BBuildingOnlyHealthy      string `xml:"bBuildingOnlyHealthy"`
+			BNoForeignTrade           string `xml:"bNoForeignTrade"`
+			BNoCorporations           string `xml:"bNoCorporations"`
+			BNoForeignCorporations    string `xml:"bNoForeignCorporations"`
+			BStateReligion            string `xml:"bStateReligion"`
+			BNoNonStateReligionSpread string `xml:"bNoNonStateReligionSpread"`
+			Button                    string `xml:"Button"`
+		} `xml:"CivicInfo"`
+	} `xml:"CivicInfos"`
+}
+
+type Civ4ProjectInfo struct {
+	XMLName      xml.Name `xml:"Civ4ProjectInfo"`
+	Text         string   `xml:",chardata"`
+	Xmlns        string   `xml:"xmlns,attr"`
+	ProjectInfos struct {
+		Text        string `xml:",chardata"`
+		ProjectInfo []struct {
+			Text                    string `xml:",chardata"`
+			Type                    string `xml:"Type"`
+			Description             string `xml:"Description"`
+			Civilopedia             string `xml:"Civilopedia"`
+			Strategy                string `xml:"Strategy"`
+			VictoryPrereq           string `xml:"VictoryPrereq"`
+			TechPrereq              string `xml:"TechPrereq"`
+			AnyoneProjectPrereq     string `xml:"AnyoneProjectPrereq"`
+			IMaxGlobalInstances     string `xml:"iMaxGlobalInstances"`
+			IMaxTeamInstances       string `xml:"iMaxTeamInstances"`
+			ICost                   string `xml:"iCost"`
+			INukeInterception       string `xml:"iNukeInterception"`
+			ITechShare              string `xml:"iTechShare"`
+			EveryoneSpecialUnit     string `xml:"EveryoneSpecialUnit"`
+			EveryoneSpecialBuilding string `xml:"EveryoneSpecialBuilding"`
+			BSpaceship              string `xml:"bSpaceship"`
+			BAllowsNukes            string `xml:"bAllowsNukes"`
+			CreateSound             string `xml:"CreateSound"`
+			MovieDefineTag          string `xml:"MovieDefineTag"`
+			IVictoryDelayPercent    string `xml:"iVictoryDelayPercent"`
+			ISuccessRate            string `xml:"iSuccessRate"`
+			VictoryThresholds       struct {
+				Text             string `xml:",chardata"`
+				VictoryThreshold []struct {
+					Text        string `xml:",chardata"`
+					VictoryType string `xml:"VictoryType"`
+					IThreshold  string `xml:"iThreshold"`
+				} `xml:"VictoryThreshold"`
+			} `xml:"VictoryThresholds"`
+			VictoryMinThresholds struct {
+				Text                string `xml:",chardata"`
+				VictoryMinThreshold []struct {
+					Text        string `xml:",chardata"`
+					VictoryType string `xml:"VictoryType"`
+					IThreshold  string `xml:"iThreshold"`
+				} `xml:"VictoryMinThreshold"`
+			} `xml:"VictoryMinThresholds"`
+			PrereqProjects struct {
+				Text          string `xml:",chardata"`
+				PrereqProject []struct {
+					Text        string `xml:",chardata"`
+					ProjectType string `xml:"ProjectType"`
+					INeeded     string `xml:"iNeeded"`
+				} `xml:"PrereqProject"`
+			} `xml:"PrereqProjects"`
+			Flavors struct {
+				Text   string `xml:",chardata"`
+				Flavor []struct {
+					Text       string `xml:",chardata"`
+					FlavorType string `xml:"FlavorType"`
+					IFlavor    string `xml:"iFlavor"`
+				} `xml:"Flavor"`
+			} `xml:"Flavors"`
+			IAIWeight                string `xml:"iAIWeight"`
+			BonusProductionModifiers struct {
+				Text                    string `xml:",chardata"`
+				BonusProductionModifier []struct {
+					Text               string `xml:",chardata"`
+					BonusType          string `xml:"BonusType"`
+					IProductonModifier string `xml:"iProductonModifier"`
+				} `xml:"BonusProductionModifier"`
+			} `xml:"BonusProductionModifiers"`
+			Button string `xml:"Button"`
+		} `xml:"ProjectInfo"`
+	} `xml:"ProjectInfos"`
+}
+
+type Civ4CivicOptionInfos struct {
+	XMLName          xml.Name `xml:"Civ4CivicOptionInfos"`
+	Text             string   `xml:",chardata"`
+	Xmlns            string   `xml:"xmlns,attr"`
+	CivicOptionInfos struct {
+		Text            string `xml:",chardata"`
+		CivicOptionInfo []struct {
+			Text           string `xml:",chardata"`
+			Type           string `xml:"Type"`
+			Description    string `xml:"Description"`
+			TraitNoUpkeeps struct {
+				Text          string `xml:",chardata"`
+				TraitNoUpkeep []struct {
+					Text      string `xml:",chardata"`
+					TraitType string `xml:"TraitType"`
+					BNoUpkeep string `xml:"bNoUpkeep"`
+				} `xml:"TraitNoUpkeep"`
+			} `xml:"TraitNoUpkeeps"`
+		} `xml:"CivicOptionInfo"`
+	} `xml:"CivicOptionInfos"`
+}
+
+type Civ4HandicapInfo struct {
+	XMLName       xml.Name `xml:"Civ4HandicapInfo"`
+	Text          string   `xml:",chardata"`
+	Xmlns         string   `xml:"xmlns,attr"`
+	HandicapInfos struct {
+		Text         string `xml:",chardata"`
+		HandicapInfo []struct {
+			Text                               string `xml:",chardata"`
+			Type                               string `xml:"Type"`
+			Description                        string `xml:"Description"`
+			Help                               string `xml:"Help"`
+			IFreeWinsVsBarbs                   string `xml:"iFreeWinsVsBarbs"`
+			IAnimalAttackProb                  string `xml:"iAnimalAttackProb"`
+			IStartingLocPercent                string `xml:"iStartingLocPercent"`
+			IAdvancedStartPointsMod            string `xml:"iAdvancedStartPointsMod"`
+			IStartingGold                      string `xml:"iStartingGold"`
+			IFreeUnits                         string `xml:"iFreeUnits"`
+			IUnitCostPercent                   string `xml:"iUnitCostPercent"`
+			IResearchPercent                   string `xml:"iResearchPercent"`
+			IDistanceMaintenancePercent        string `xml:"iDistanceMaintenancePercent"`
+			INumCitiesMaintenancePercent       string `xml:"iNumCitiesMaintenancePercent"`
+			IMaxNumCitiesMaintenance           string `xml:"iMaxNumCitiesMaintenance"`
+			IColonyMaintenancePercent          string `xml:"iColonyMaintenancePercent"`
+			IMaxColonyMaintenance              string `xml:"iMaxColonyMaintenance"`
+			ICorporationMaintenancePercent     string `xml:"iCorporationMaintenancePercent"`
+			ICivicUpkeepPercent                string `xml:"iCivicUpkeepPercent"`
+			IInflationPercent                  string `xml:"iInflationPercent"`
+			IHealthBonus                       string `xml:"iHealthBonus"`
+			IHappyBonus                        string `xml:"iHappyBonus"`
+			IAttitudeChange                    string `xml:"iAttitudeChange"`
+			INoTechTradeModifier               string `xml:"iNoTechTradeModifier"`
+			ITechTradeKnownModifier            string `xml:"iTechTradeKnownModifier"`
+			IUnownedTilesPerGameAnimal         string `xml:"iUnownedTilesPerGameAnimal"`
+			IUnownedTilesPerBarbarianUnit      string `xml:"iUnownedTilesPerBarbarianUnit"`
+			IUnownedWaterTilesPerBarbarianUnit string `xml:"iUnownedWaterTilesPerBarbarianUnit"`
+			IUnownedTilesPerBarbarianCity      string `xml:"iUnownedTilesPerBarbarianCity"`
+			IBarbarianCityCreationTurnsElapsed string `xml:"iBarbarianCityCreationTurnsElapsed"`
+			IBarbarianCityCreationProb         string `xml:"iBarbarianCityCreationProb"`
+			IAIDeclareWarProb                  string `xml:"iAIDeclareWarProb"`
+			IAIWorkRateModifier                string `xml:"iAIWorkRateModifier"`
+			IAIGrowthPercent                   string `xml:"iAIGrowthPercent"`
+			IAITrainPercent                    string `xml:"iAITrainPercent"`
+			IAIWorldTrainPercent               string `xml:"iAIWorldTrainPercent"`
+			IAIConstructPercent                string `xml:"iAIConstructPercent"`
+			IAIWorldConstructPercent           string `xml:"iAIWorldConstructPercent"`
+			IAICreatePercent                   string `xml:"iAICreatePercent"`
+			IAIWorldCreatePercent              string `xml:"iAIWorldCreatePercent"`
+			IAICivicUpkeepPercent              string `xml:"iAICivicUpkeepPercent"`
+			IAIUnitCostPercent                 string `xml:"iAIUnitCostPercent"`
+			IAIUnitSupplyPercent               string `xml:"iAIUnitSupplyPercent"`
+			IAIUnitUpgradePercent              string `xml:"iAIUnitUpgradePercent"`
+			IAIInflationPercent                string `xml:"iAIInflationPercent"`
+			IAIWarWearinessPercent             string `xml:"iAIWarWearinessPercent"`
+			IAIPerEraModifier                  string `xml:"iAIPerEraModifier"`
+			IAIAdvancedStartPercent            string `xml:"iAIAdvancedStartPercent"`
+		} `xml:"HandicapInfo"`
+	} `xml:"HandicapInfos"`
+}
+
+type Civ4Types struct {
+	XMLName       xml.Name `xml:"Civ4Types"`
+	Text          string   `xml:",chardata"`
+	Xmlns         string   `xml:"xmlns,attr"`
+	ArtStyleTypes struct {
+		Text         string   `xml:",chardata"`
+		ArtStyleType []string `xml:"ArtStyleType"`
+	} `xml:"ArtStyleTypes"`
+	CitySizeTypes struct {
+		Text         string   `xml:",chardata"`
+		CitySizeType []string `xml:"CitySizeType"`
+	} `xml:"CitySizeTypes"`
+}
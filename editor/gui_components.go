@@ -6,15 +6,23 @@ import (
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/widget"
 	"image/color"
+	"os"
+	"slices"
+	"strings"
 	"sync"
 )
 
-// ProgressBar is a custom progress bar with text label
+// ProgressBar is a custom progress bar with text label. Operations started by StartProgress are shown
+// with percent and cancel button (see Update), other ones are shown with infinite bar (see Start)
 type ProgressBar struct {
 	sync.Mutex
 	block    *fyne.Container
 	progress *widget.ProgressBarInfinite
+	bar      *widget.ProgressBar
+	cancel   *widget.Button
 	label    *widget.Label
+	// operation is an ID of the shown operation, -1 if none
+	operation int
 }
 
 func (p *ProgressBar) GetBlock() *fyne.Container {
@@ -25,6 +33,9 @@ func (p *ProgressBar) Start(loadingText string) {
 	p.Lock()
 	defer p.Unlock()
 	p.label.SetText(loadingText)
+	p.bar.Hide()
+	p.cancel.Hide()
+	p.progress.Show()
 	p.block.Show()
 	p.progress.Start()
 }
@@ -32,16 +43,75 @@ func (p *ProgressBar) Start(loadingText string) {
 func (p *ProgressBar) Stop() {
 	p.Lock()
 	defer p.Unlock()
+	p.operation = -1
 	p.progress.Stop()
 	p.block.Hide()
 }
 
+// Update shows the progress of the operation, the bar is hidden when it's finished. It's used as a listener of SubscribeProgress
+func (p *ProgressBar) Update(state Progress) {
+	p.Lock()
+	defer p.Unlock()
+	if state.Finished {
+		if state.ID == p.operation {
+			p.operation = -1
+			p.progress.Stop()
+			p.block.Hide()
+		}
+		return
+	}
+
+	text := state.Operation
+	if state.Phase != "" {
+		text += ": " + state.Phase
+	}
+	if state.Detail != "" {
+		text += " (" + shortenGamePath(state.Detail) + ")"
+	}
+
+	p.operation = state.ID
+	p.label.SetText(text)
+	p.cancel.Show()
+	p.block.Show()
+	if state.Percent < 0 {
+		p.bar.Hide()
+		p.progress.Show()
+		p.progress.Start()
+	} else {
+		p.progress.Stop()
+		p.progress.Hide()
+		p.bar.Show()
+		p.bar.SetValue(float64(state.Percent) / 100)
+	}
+}
+
 func GuiProgressBar() *ProgressBar {
 	progress := widget.NewProgressBarInfinite()
+	bar := widget.NewProgressBar()
 	progressText := widget.NewLabel("Loading...")
-	progressBlock := container.NewVBox(container.NewCenter(progressText), progress)
-	progressBlock.Hide()
-	return &ProgressBar{progress: progress, label: progressText, block: progressBlock}
+	p := &ProgressBar{progress: progress, bar: bar, label: progressText, operation: -1}
+	p.cancel = widget.NewButton("Cancel", func() {
+		p.Lock()
+		operation := p.operation
+		p.Unlock()
+		CancelProgress(operation)
+	})
+	p.block = container.NewVBox(container.NewCenter(container.NewHBox(progressText, p.cancel)), progress, bar)
+	p.block.Hide()
+	return p
+}
+
+// shortenGamePath removes game directory from the path and limits its length to make it shorter (better UX)
+func shortenGamePath(s string) string {
+	if GlobalConfig != nil && GlobalConfig.GameDir != "" {
+		s = strings.ReplaceAll(s, GlobalConfig.GameDir+"\\", "")
+		s = strings.ReplaceAll(s, GlobalConfig.GameDir+string(os.PathSeparator), "")
+		s = strings.ReplaceAll(s, GlobalConfig.GameDir, "")
+	}
+	if len(s) > 170 {
+		s = s[:170] + "..."
+	}
+	return s
 }
 
 // FormField is a common interface for all form fields
@@ -50,11 +120,12 @@ type FormField interface {
 	GetValue() string
 }
 
-// SelectEntry is a form field with predefined values.
+// SelectEntry is a form field with predefined values (see GetChoices).
 type SelectEntry struct {
 	Key        string
-	entry      *widget.SelectEntry
+	entry      *widget.Select
 	valueLabel *widget.Label
+	value      string
 }
 
 func (s *SelectEntry) GetKey() string {
@@ -62,19 +133,43 @@ func (s *SelectEntry) GetKey() string {
 }
 
 func (s *SelectEntry) GetValue() string {
-	return s.entry.Text
+	return s.value
 }
 
-func GuiSelectEntry(c *fyne.Container, key string, label string, value string, variants []string, onChange func(string)) *SelectEntry {
-	valueLabel := widget.NewLabel(GetLangString(value))
-	e := widget.NewSelectEntry(variants)
-	e.OnChanged = func(s string) {
-		onChange(s)
-		valueLabel.SetText(GetLangString(s))
+// GuiSelectEntry adds a select with choices, showing their localized descriptions. onChange receives the selected type.
+// Unknown value (e.g. from another mod) is kept as a separate option, so it's not lost
+func GuiSelectEntry(c *fyne.Container, key string, label string, value string, choices []Choice, onChange func(string)) *SelectEntry {
+	options := make([]string, 0, len(choices)+1)
+	optionTypes := make(map[string]string)
+	selected := ""
+	for _, choice := range choices {
+		option := choice.Description
+		if _, exists := optionTypes[option]; exists {
+			option += " (" + choice.Type + ")"
+		}
+
+		options = append(options, option)
+		optionTypes[option] = choice.Type
+		if choice.Type == value {
+			selected = option
+		}
 	}
-	e.Text = value
-	c.Add(container.NewGridWithColumns(3, widget.NewLabel(label), e, valueLabel))
-	return &SelectEntry{key, e, valueLabel}
+
+	if selected == "" && value != "" {
+		selected = value
+		options = append(options, value)
+		optionTypes[value] = value
+	}
+
+	s := &SelectEntry{Key: key, valueLabel: widget.NewLabel(value), value: value}
+	s.entry = widget.NewSelect(options, func(option string) {
+		s.value = optionTypes[option]
+		s.valueLabel.SetText(s.value)
+		onChange(s.value)
+	})
+	s.entry.Selected = selected
+	c.Add(container.NewGridWithColumns(3, widget.NewLabel(label), s.entry, s.valueLabel))
+	return s
 }
 
 type Entry struct {
@@ -90,8 +185,42 @@ func (e *Entry) GetValue() string {
 	return e.entry.Text
 }
 
-func GuiTextField(c *fyne.Container, key string, label string, value string, onChange func(string)) *Entry {
-	additionalLabel := widget.NewLabel(GetLangString(key))
+// SetValue changes the value, onChange is called as well
+func (e *Entry) SetValue(value string) {
+	e.entry.SetText(value)
+}
+
+// MultilineEntry is a text field editing a list of values, one per line
+type MultilineEntry struct {
+	entry *widget.Entry
+}
+
+// SetLines changes the value, onChange is called as well
+func (m *MultilineEntry) SetLines(lines []string) {
+	m.entry.SetText(strings.Join(lines, "\n"))
+}
+
+// GuiMultilineField adds a multiline text field, onChange receives non-empty trimmed lines
+func GuiMultilineField(c *fyne.Container, label string, lines []string, onChange func([]string)) *MultilineEntry {
+	e := widget.NewMultiLineEntry()
+	e.SetMinRowsVisible(6)
+	e.Text = strings.Join(lines, "\n")
+	e.OnChanged = func(s string) {
+		var result []string
+		for _, line := range strings.Split(s, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				result = append(result, line)
+			}
+		}
+		onChange(result)
+	}
+	c.Add(widget.NewLabel(label))
+	c.Add(e)
+	return &MultilineEntry{e}
+}
+
+func GuiTextField(c *fyne.Container, data *GameData, key string, label string, value string, onChange func(string)) *Entry {
+	additionalLabel := widget.NewLabel(data.GetLangString(key))
 	e := widget.NewEntry()
 	e.OnChanged = func(s string) {
 		onChange(s)
@@ -101,12 +230,100 @@ func GuiTextField(c *fyne.Container, key string, label string, value string, onC
 	return &Entry{key, e}
 }
 
-func GuiCheckbox(c *fyne.Container, label string, value bool, onChange func(bool)) {
-	cb := widget.NewCheck(GetLangString(label), onChange)
+func GuiCheckbox(c *fyne.Container, data *GameData, label string, value bool, onChange func(bool)) {
+	cb := widget.NewCheck(data.GetLangString(label), onChange)
 	cb.Checked = value
 	c.Add(cb)
 }
 
+// GuiChoiceCheckboxes adds a checkbox for every choice, onChange is called with types of checked choices
+func GuiChoiceCheckboxes(c *fyne.Container, prefix string, choices []Choice, values []string, onChange func([]string)) {
+	for _, choice := range choices {
+		choiceType := choice.Type
+		cb := widget.NewCheck(prefix+choice.Description, func(b bool) {
+			values = SwitchInSlice(b, slices.Clone(values), choiceType)
+			onChange(values)
+		})
+		cb.Checked = IsInSlice(values, choiceType)
+		c.Add(cb)
+	}
+}
+
+// GuiTeamRelations adds a matrix of relations (see TeamRelations) of the team with all other teams of the map
+func GuiTeamRelations(c *fyne.Container, m *WbMap, team *Team, onChange func(relation TeamRelation, other uint, value bool)) {
+	grid := container.NewGridWithColumns(len(TeamRelations) + 1)
+	grid.Add(widget.NewLabel(""))
+	for _, relation := range TeamRelations {
+		grid.Add(widget.NewLabel(TeamRelationNames[relation]))
+	}
+
+	for _, other := range m.Teams {
+		if other.TeamID == team.TeamID {
+			continue
+		}
+
+		otherID := other.TeamID
+		grid.Add(widget.NewLabel(m.GetTeamName(otherID)))
+		for _, relation := range TeamRelations {
+			relation := relation
+			cb := widget.NewCheck("", func(b bool) { onChange(relation, otherID, b) })
+			cb.Checked = team.HasRelation(relation, otherID)
+			grid.Add(cb)
+		}
+	}
+
+	c.Add(grid)
+}
+
 func GuiNoMapLoaded(c *fyne.Container) {
 	c.Add(container.NewCenter(canvas.NewText("To start editing, open a map or create a new one", color.White)))
 }
+
+// GuiLoadReport shows problems found while loading XML files (see XmlLoadReport)
+func GuiLoadReport(c *fyne.Container, report *XmlLoadReport) {
+	addList := func(title string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+
+		c.Add(widget.NewSeparator())
+		c.Add(widget.NewLabelWithStyle(title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		for _, line := range lines {
+			label := widget.NewLabel(line)
+			label.Wrapping = fyne.TextWrapBreak
+			c.Add(label)
+		}
+	}
+
+	c.Add(widget.NewLabel(report.Summary()))
+	addList("Listing errors", report.Errors)
+
+	var lines []string
+	for _, file := range report.GetFiles(XmlFileFailed) {
+		lines = append(lines, file.Path+": "+file.Reason)
+	}
+	addList("Failed files", lines)
+
+	lines = nil
+	for _, file := range report.GetFilesWithWarnings() {
+		lines = append(lines, file.Path+": "+strings.Join(file.Warnings, "; "))
+	}
+	addList("Warnings", lines)
+
+	addList("Unknown root tags", report.GetUnknownRootTags())
+
+	lines = nil
+	for _, duplicate := range report.Duplicates {
+		lines = append(lines, duplicate.Type+": "+strings.Join(duplicate.Files, ", "))
+	}
+	addList("Types defined more than once (the last one is used)", lines)
+}
+
+// GuiLintReport shows issues found by linter (see LintGameData)
+func GuiLintReport(c *fyne.Container, report *LintReport) {
+	for _, line := range strings.Split(strings.TrimSpace(report.String()), "\n") {
+		label := widget.NewLabel(line)
+		label.Wrapping = fyne.TextWrapBreak
+		c.Add(label)
+	}
+}
@@ -1,16 +1,22 @@
 package editor
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 	"github.com/bssth/civ4-studio/resources"
 	"image/color"
 	"os"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -19,6 +25,14 @@ import (
 type Editor struct {
 	FilePath string
 	WbMap    *WbMap
+	// Data is game data for the configured game directory and mod
+	Data *GameData
+	// History contains undoable changes of the map, every open map has its own history
+	History *History
+	// Documents are all open maps, FilePath, WbMap and History belong to the active one
+	Documents []*MapDocument
+	// Workspace is the open project or nil
+	Workspace *Workspace
 }
 
 const (
@@ -26,10 +40,21 @@ const (
 	SectionMapSettings
 	SectionTeams
 	SectionPlayers
+	SectionWorldBuilder
+	SectionCities
+	SectionUnits
+	SectionWorkspace
+	SectionLoadReport
 )
 
 func (e *Editor) ShowEditor() {
 	currentSection := 0
+	currentTeam := uint(0)
+	currentPlayer := 0
+	var mapCanvas *MapCanvas
+	currentCity := [2]int{-1, -1}
+	currentUnitPlot := [2]int{-1, -1}
+	copySource := ""
 
 	// Create new empty map if it's not set
 	// @todo more real default values
@@ -40,6 +65,11 @@ func (e *Editor) ShowEditor() {
 		}
 	}
 
+	// Game data is empty until loaded in background, reloading is safe while the interface reads it
+	if e.Data == nil {
+		e.Data = NewGameData(GlobalConfig.GameDir, GlobalConfig.Mod)
+	}
+
 	// Firstly, we need to create whole interface and then update it with data
 	editor := Application.NewWindow("Civ 4 Studio")
 	editor.SetIcon(resources.IconResource)
@@ -52,13 +82,66 @@ func (e *Editor) ShowEditor() {
 		}
 	}()
 
+	// Every open map is a document shown in a tab, e.FilePath, e.WbMap and e.History belong to the active one.
+	// Changed maps are autosaved to recover them after a crash, recovery is removed when the map is saved
+	var activeDoc *MapDocument
+	autosavers := make(map[*MapDocument]*Autosaver)
+	tabs := container.NewDocTabs()
+	if e.History == nil {
+		e.History = NewHistory()
+	}
+
+	// Window title shows the project and file name, asterisk means unsaved changes
+	updateTitle := func() {
+		title := "Civ 4 Studio"
+		if e.Workspace != nil {
+			title += " - " + e.Workspace.Name
+		}
+		if e.FilePath != "" {
+			title += " - " + filepath.Base(e.FilePath)
+		}
+		if e.History.IsDirty() {
+			title += " *"
+		}
+		editor.SetTitle(title)
+
+		for i, doc := range e.Documents {
+			if i < len(tabs.Items) {
+				tabs.Items[i].Text = doc.GetTitle()
+			}
+		}
+		tabs.Refresh()
+	}
+	saveConfig := func() {
+		if err := SaveConfig(); err != nil {
+			ConsoleWrite(err.Error())
+		}
+	}
+	showError := func(err error) {
+		errorText := err.Error()
+		ConsoleWrite(errorText)
+
+		// Truncate error text if it's too long for dialog
+		if len(errorText) > 100 {
+			errorText = errorText[:100] + "..."
+		}
+		dialog.ShowError(errors.New(errorText), editor)
+	}
+
+	// Maps and projects are opened by path from sections (recent files, project maps), assigned after sections
+	var openPath func(path string)
+	var openWorkspace func(path string)
+
 	progress := GuiProgressBar()
+	editor.SetOnClosed(SubscribeProgress(progress.Update))
 	body := container.NewVBox()
 
 	// Open another editor section (usually by user), fill content, apply data and callbacks
-	openSection := func(section int) {
+	var openSection func(section int)
+	openSection = func(section int) {
 		body.RemoveAll()
 		currentSection = section
+		history := e.History
 
 		switch section {
 		case SectionMapSettings:
@@ -67,70 +150,768 @@ func (e *Editor) ShowEditor() {
 				break
 			}
 
-			GuiSelectEntry(body, "Era", "Starting era", e.WbMap.Game.Era, GetEraNames(), func(s string) { e.WbMap.Game.Era = s })
-			GuiSelectEntry(body, "Speed", "Game speed", e.WbMap.Game.Speed, GetSpeedNames(), func(s string) { e.WbMap.Game.Speed = s })
-			GuiSelectEntry(body, "Calendar", "Calendar type", e.WbMap.Game.Calendar, GetCalendarNames(), func(s string) { e.WbMap.Game.Calendar = s })
+			data := e.Data.Registries()
+			game := e.WbMap.Game
+			GuiSelectEntry(body, "Era", "Starting era", game.Era, GetChoices(data, data.EraInfos, OrderXML), Set(history, "Era", game, &game.Era))
+			GuiSelectEntry(body, "Speed", "Game speed", game.Speed, GetChoices(data, data.SpeedInfos, OrderXML), Set(history, "Speed", game, &game.Speed))
+			GuiSelectEntry(body, "Calendar", "Calendar type", game.Calendar, GetChoices(data, data.CalendarInfos, OrderXML), Set(history, "Calendar", game, &game.Calendar))
 
 			body.Add(widget.NewSeparator())
-			GuiTextField(body, "GameTurn", "Starting turn", strconv.Itoa(int(e.WbMap.Game.GameTurn)), func(s string) { e.WbMap.Game.GameTurn = ToUint(s) })
-			GuiTextField(body, "MaxCityElimination", "Maximum cities lost to lose game", strconv.Itoa(int(e.WbMap.Game.MaxCityElimination)), func(s string) { e.WbMap.Game.MaxCityElimination = ToUint(s) })
-			GuiTextField(body, "NumAdvancedStartPoints", "Starting points", strconv.Itoa(int(e.WbMap.Game.NumAdvancedStartPoints)), func(s string) { e.WbMap.Game.NumAdvancedStartPoints = ToUint(s) })
-			GuiTextField(body, "TargetScore", "Score to win", strconv.Itoa(int(e.WbMap.Game.TargetScore)), func(s string) { e.WbMap.Game.TargetScore = ToUint(s) })
-			GuiTextField(body, "StartYear", "Starting year", strconv.Itoa(e.WbMap.Game.StartYear), func(s string) { e.WbMap.Game.StartYear = ToInt(s) })
-			GuiTextField(body, "Description", "Description", e.WbMap.Game.Description, func(s string) { e.WbMap.Game.Description = s })
-			GuiTextField(body, "ModPath", "Mod path", e.WbMap.Game.ModPath, func(s string) { e.WbMap.Game.ModPath = s })
-			GuiTextField(body, "MaxTurns", "Turns to end game", strconv.Itoa(int(e.WbMap.Game.MaxTurns)), func(s string) { e.WbMap.Game.MaxTurns = ToUint(s) })
-			GuiCheckbox(body, "Tutorial", e.WbMap.Game.Tutorial, func(b bool) { e.WbMap.Game.Tutorial = b })
+			gameTurn := GuiTextField(body, e.Data, "GameTurn", "Starting turn", strconv.Itoa(int(game.GameTurn)), func(s string) { SetText(history, "GameTurn", game, &game.GameTurn)(ToUint(s)) })
+			GuiTextField(body, e.Data, "MaxCityElimination", "Maximum cities lost to lose game", strconv.Itoa(int(game.MaxCityElimination)), func(s string) { SetText(history, "MaxCityElimination", game, &game.MaxCityElimination)(ToUint(s)) })
+			GuiTextField(body, e.Data, "NumAdvancedStartPoints", "Starting points", strconv.Itoa(int(game.NumAdvancedStartPoints)), func(s string) {
+				SetText(history, "NumAdvancedStartPoints", game, &game.NumAdvancedStartPoints)(ToUint(s))
+			})
+			GuiTextField(body, e.Data, "TargetScore", "Score to win", strconv.Itoa(int(game.TargetScore)), func(s string) { SetText(history, "TargetScore", game, &game.TargetScore)(ToUint(s)) })
+			GuiTextField(body, e.Data, "StartYear", "Starting year", strconv.Itoa(game.StartYear), func(s string) { SetText(history, "StartYear", game, &game.StartYear)(ToInt(s)) })
+			GuiTextField(body, e.Data, "Description", "Description", game.Description, SetText(history, "Description", game, &game.Description))
+			GuiTextField(body, e.Data, "ModPath", "Mod path", game.ModPath, SetText(history, "ModPath", game, &game.ModPath))
+			maxTurns := GuiTextField(body, e.Data, "MaxTurns", "Turns to end game", strconv.Itoa(int(game.MaxTurns)), func(s string) { SetText(history, "MaxTurns", game, &game.MaxTurns)(ToUint(s)) })
+
+			// Scenario dates are converted to turns with the current speed, calendar and starting year
+			turnForDate := func(s string) (int, bool) {
+				date, err := ParseGameDate(s)
+				if err != nil {
+					return 0, false
+				}
+				calendar, err := data.NewGameCalendar(game)
+				if err != nil {
+					return 0, false
+				}
+				turn, _, err := calendar.GetTurnForDate(date)
+				return turn, err == nil
+			}
+			GuiTextField(body, e.Data, "StartDate", "Starting date (YEAR-MONTH)", "", func(s string) {
+				if turn, ok := turnForDate(s); ok {
+					gameTurn.SetValue(strconv.Itoa(turn))
+				}
+			})
+			GuiTextField(body, e.Data, "EndDate", "Ending date (YEAR-MONTH)", "", func(s string) {
+				if turn, ok := turnForDate(s); ok && turn > int(game.GameTurn) {
+					maxTurns.SetValue(strconv.Itoa(turn - int(game.GameTurn)))
+				}
+			})
+			GuiCheckbox(body, e.Data, "Tutorial", game.Tutorial, Set(history, "Tutorial", game, &game.Tutorial))
 
 			body.Add(widget.NewSeparator())
-			// @todo checkboxes not working, T[key] is always the last value
-			for _, key := range SortKeys(VictoryInfos) {
-				GuiCheckbox(body, "Victory: "+GetLangString(VictoryInfos[key].Description), IsInSlice(e.WbMap.Game.Victory, VictoryInfos[key].Type),
-					func(b bool) { e.WbMap.Game.Victory = SwitchInSlice(b, e.WbMap.Game.Victory, VictoryInfos[key].Type) })
+			GuiChoiceCheckboxes(body, "Victory: ", GetChoices(data, data.VictoryInfos, OrderXML), game.Victory, Set(history, "Victory", game, &game.Victory))
+			body.Add(widget.NewSeparator())
+			GuiChoiceCheckboxes(body, "Game option: ", GetChoices(data, data.GameOptionInfos, OrderXML), game.Option, Set(history, "Option", game, &game.Option))
+			body.Add(widget.NewSeparator())
+			GuiChoiceCheckboxes(body, "Multiplayer option: ", GetChoices(data, data.GameMPInfos, OrderXML), game.MPOption, Set(history, "MPOption", game, &game.MPOption))
+			body.Add(widget.NewSeparator())
+			GuiChoiceCheckboxes(body, "Make unchangeable: ", GetChoices(data, data.ForceControlInfos, OrderXML), game.ForceControl, Set(history, "ForceControl", game, &game.ForceControl))
+
+		case SectionTeams:
+			if e.FilePath == "" {
+				GuiNoMapLoaded(body)
+				break
 			}
+			if len(e.WbMap.Teams) == 0 {
+				body.Add(widget.NewLabel("Map has no teams"))
+				break
+			}
+
+			team := e.WbMap.GetTeam(currentTeam)
+			if team == nil {
+				team = e.WbMap.Teams[0]
+				currentTeam = team.TeamID
+			}
+
+			// Changes affecting other checkboxes (prerequisites, mutual relations) reopen the section to show them
+			reopen := func() { openSection(SectionTeams) }
+			GuiSelectEntry(body, "TeamID", "Team", strconv.Itoa(int(currentTeam)), e.WbMap.GetTeamChoices(), func(s string) {
+				currentTeam = ToUint(s)
+				reopen()
+			})
+
+			check := widget.NewCheck("Reveal map", Set(history, "RevealMap", team, &team.RevealMap))
+			check.Checked = team.RevealMap
+			body.Add(check)
+
+			body.Add(widget.NewSeparator())
+			body.Add(widget.NewLabelWithStyle("Diplomacy", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+			GuiTeamRelations(body, e.WbMap, team, func(relation TeamRelation, other uint, value bool) {
+				err := history.Try("Diplomacy", func() error {
+					return e.WbMap.SetTeamRelation(relation, team.TeamID, other, value)
+				}, &e.WbMap.Teams)
+				if err != nil {
+					ConsoleWrite(err.Error())
+				}
+				reopen()
+			})
+
+			data := e.Data.Registries()
+			body.Add(widget.NewSeparator())
+			body.Add(widget.NewLabelWithStyle("Projects", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+			GuiChoiceCheckboxes(body, "", GetChoices(data, data.ProjectInfos, OrderXML), team.ProjectType, Set(history, "ProjectType", team, &team.ProjectType))
+
 			body.Add(widget.NewSeparator())
-			for _, key := range SortKeys(GameOptionInfos) {
-				GuiCheckbox(body, "Game option: "+GetLangString(GameOptionInfos[key].Description), IsInSlice(e.WbMap.Game.Option, GameOptionInfos[key].Type),
-					func(b bool) { e.WbMap.Game.Option = SwitchInSlice(b, e.WbMap.Game.Option, GameOptionInfos[key].Type) })
+			body.Add(widget.NewLabelWithStyle("Technologies (prerequisites are added and removed automatically)", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+			techs := container.NewGridWithColumns(3)
+			for _, choice := range GetChoices(data, data.TechInfos, OrderXML) {
+				techType := choice.Type
+				cb := widget.NewCheck(choice.Description, func(b bool) {
+					history.Update("Tech", func() {
+						if b {
+							team.Tech = data.AddTechWithPrereqs(team.Tech, techType)
+						} else {
+							team.Tech = data.RemoveTechWithDependents(team.Tech, techType)
+						}
+					}, team)
+					reopen()
+				})
+				cb.Checked = IsInSlice(team.Tech, techType)
+				techs.Add(cb)
 			}
+			body.Add(techs)
+
+		case SectionPlayers:
+			if e.FilePath == "" {
+				GuiNoMapLoaded(body)
+				break
+			}
+
+			reopen := func() { openSection(SectionPlayers) }
+			body.Add(container.NewHBox(
+				// The player is added to a free slot with a free civilization, which may be changed then
+				widget.NewButton("Add player", func() {
+					err := history.Try("Add player", func() error {
+						index, err := e.WbMap.AddPlayer(e.Data.Registries())
+						if err == nil {
+							currentPlayer = index
+						}
+						return err
+					}, &e.WbMap.Players, &e.WbMap.Teams)
+					if err != nil {
+						dialog.ShowError(err, editor)
+						return
+					}
+					reopen()
+				}),
+				widget.NewButton("Remove player", func() {
+					if currentPlayer >= len(e.WbMap.Players) {
+						return
+					}
+					message := "Units and cities of " + e.WbMap.GetPlayerName(currentPlayer) + " will be removed too. Continue?"
+					dialog.ShowConfirm("Remove player", message, func(ok bool) {
+						if !ok {
+							return
+						}
+						// Units, cities, attitudes and teams are changed, so the whole map is tracked
+						if err := history.Try("Remove player", func() error { return e.WbMap.RemovePlayer(currentPlayer) }, e.WbMap); err != nil {
+							ConsoleWrite(err.Error())
+						}
+						reopen()
+					}, editor)
+				}),
+			))
+			if len(e.WbMap.Players) == 0 {
+				break
+			}
+
+			if currentPlayer >= len(e.WbMap.Players) {
+				currentPlayer = 0
+			}
+			playerChoices := make([]Choice, 0, len(e.WbMap.Players))
+			for i := range e.WbMap.Players {
+				playerChoices = append(playerChoices, Choice{Type: strconv.Itoa(i), Description: e.WbMap.GetPlayerName(i)})
+			}
+			GuiSelectEntry(body, "Player", "Player", strconv.Itoa(currentPlayer), playerChoices, func(s string) {
+				currentPlayer = ToInt(s)
+				reopen()
+			})
+
+			// Civilization and leader change names, colors and leaders list, so the section is reopened to show them
+			player := e.WbMap.Players[currentPlayer]
+			data := e.Data.Registries()
 			body.Add(widget.NewSeparator())
-			for _, key := range SortKeys(GameMPInfos) {
-				GuiCheckbox(body, "Multiplayer option: "+GetLangString(GameMPInfos[key].Description), IsInSlice(e.WbMap.Game.MPOption, GameMPInfos[key].Type),
-					func(b bool) { e.WbMap.Game.MPOption = SwitchInSlice(b, e.WbMap.Game.MPOption, GameMPInfos[key].Type) })
+			GuiSelectEntry(body, "CivType", "Civilization", player.CivType, GetChoices(data, data.CivilizationInfos, OrderName), func(s string) {
+				history.Update("Civilization", func() { data.ApplyCivilization(player, s) }, player)
+				reopen()
+			})
+			if player.IsEmpty() {
+				body.Add(widget.NewLabel("The slot is empty, select a civilization to use it"))
+				break
+			}
+
+			leaders := GetChoices(data, data.LeaderHeadInfos, OrderName)
+			if civLeaders := data.GetCivilizationLeaders(player.CivType); len(civLeaders) > 0 {
+				leaders = FilterChoices(leaders, civLeaders)
 			}
+			GuiSelectEntry(body, "LeaderType", "Leader", player.LeaderType, leaders, func(s string) {
+				history.Update("Leader", func() { data.ApplyLeader(player, s) }, player)
+				reopen()
+			})
+			GuiTextField(body, e.Data, "CivDesc", "Civilization name", player.CivDesc, SetText(history, "CivDesc", player, &player.CivDesc))
+			GuiTextField(body, e.Data, "CivShortDesc", "Civilization short name", player.CivShortDesc, SetText(history, "CivShortDesc", player, &player.CivShortDesc))
+			GuiTextField(body, e.Data, "CivAdjective", "Civilization adjective", player.CivAdjective, SetText(history, "CivAdjective", player, &player.CivAdjective))
+			GuiTextField(body, e.Data, "LeaderName", "Leader name", player.LeaderName, SetText(history, "LeaderName", player, &player.LeaderName))
+			// Teams of other players are rejected, so every player keeps its own relations
+			GuiSelectEntry(body, "Team", "Team", strconv.Itoa(int(player.Team)), e.WbMap.GetTeamChoices(), func(s string) {
+				err := history.Try("Team", func() error {
+					player.Team = ToUint(s)
+					return e.WbMap.CheckPlayerTeam(currentPlayer)
+				}, player)
+				if err != nil {
+					dialog.ShowError(err, editor)
+					reopen()
+				}
+			})
+
 			body.Add(widget.NewSeparator())
-			for _, key := range SortKeys(ForceControlInfos) {
-				GuiCheckbox(body, "Make unchangeable: "+GetLangString(ForceControlInfos[key].Description), IsInSlice(e.WbMap.Game.ForceControl, ForceControlInfos[key].Type),
-					func(b bool) {
-						e.WbMap.Game.ForceControl = SwitchInSlice(b, e.WbMap.Game.ForceControl, ForceControlInfos[key].Type)
-					})
+			GuiSelectEntry(body, "Color", "Color", player.Color, GetChoices(data, data.PlayerColorInfos, OrderName), Set(history, "Color", player, &player.Color))
+			GuiTextField(body, e.Data, "FlagDecal", "Flag decal", player.FlagDecal, SetText(history, "FlagDecal", player, &player.FlagDecal))
+			GuiCheckbox(body, e.Data, "White flag", player.WhiteFlag, Set(history, "WhiteFlag", player, &player.WhiteFlag))
+			GuiSelectEntry(body, "ArtStyle", "Art style", player.ArtStyle, GetChoices(data, data.ArtStyleInfos, OrderName), Set(history, "ArtStyle", player, &player.ArtStyle))
+
+			body.Add(widget.NewSeparator())
+			GuiSelectEntry(body, "Handicap", "Handicap", player.Handicap, GetChoices(data, data.HandicapInfos, OrderXML), Set(history, "Handicap", player, &player.Handicap))
+			GuiCheckbox(body, e.Data, "Playable by human", player.PlayableCiv, Set(history, "PlayableCiv", player, &player.PlayableCiv))
+			GuiCheckbox(body, e.Data, "Minor nation", player.MinorNationStatus, Set(history, "MinorNationStatus", player, &player.MinorNationStatus))
+			GuiTextField(body, e.Data, "StartingGold", "Starting gold", strconv.Itoa(player.StartingGold), func(s string) { SetText(history, "StartingGold", player, &player.StartingGold)(ToInt(s)) })
+			GuiSelectEntry(body, "StartingEra", "Starting era", player.StartingEra, GetChoices(data, data.EraInfos, OrderXML), Set(history, "StartingEra", player, &player.StartingEra))
+			GuiCheckbox(body, e.Data, "Random start location", player.RandomStartLocation, Set(history, "RandomStartLocation", player, &player.RandomStartLocation))
+			GuiTextField(body, e.Data, "StartingX", "Starting X", strconv.Itoa(player.StartingX), func(s string) { SetText(history, "StartingX", player, &player.StartingX)(ToInt(s)) })
+			GuiTextField(body, e.Data, "StartingY", "Starting Y", strconv.Itoa(player.StartingY), func(s string) { SetText(history, "StartingY", player, &player.StartingY)(ToInt(s)) })
+
+			// Empty type means the value is not set, the game uses its default then
+			body.Add(widget.NewSeparator())
+			religions := append([]Choice{{Description: "No state religion"}}, GetChoices(data, data.ReligionInfos, OrderXML)...)
+			GuiSelectEntry(body, "StateReligion", "State religion", player.StateReligion, religions, Set(history, "StateReligion", player, &player.StateReligion))
+			civics := GetChoices(data, data.CivicInfos, OrderXML)
+			for _, option := range GetChoices(data, data.CivicOptionInfos, OrderXML) {
+				civicOption := option.Type
+				choices := append([]Choice{{Description: "Default"}}, FilterChoices(civics, data.GetCivicsByOption(civicOption))...)
+				GuiSelectEntry(body, civicOption, option.Description, player.GetCivic(civicOption), choices, func(s string) {
+					history.Update(civicOption, func() { player.SetCivic(civicOption, s) }, player)
+				})
 			}
 
-		case SectionTeams:
+			body.Add(widget.NewSeparator())
+			cityList := GuiMultilineField(body, "City names (one per line)", player.CityList, SetText(history, "CityList", player, &player.CityList))
+			body.Add(widget.NewButton("Use default city names of the civilization", func() {
+				cityList.SetLines(data.GetCityNames(player.CivType))
+			}))
+
+			body.Add(widget.NewSeparator())
+			body.Add(widget.NewLabelWithStyle("Attitude towards other players", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+			for i, other := range e.WbMap.Players {
+				if i == currentPlayer || other.IsEmpty() {
+					continue
+				}
+				otherIndex := uint(i)
+				GuiTextField(body, e.Data, "AttitudeExtra", e.WbMap.GetPlayerName(i), strconv.Itoa(player.GetAttitude(otherIndex)), func(s string) {
+					history.UpdateText("Attitude"+strconv.Itoa(int(otherIndex)), func() { player.SetAttitude(otherIndex, ToInt(s)) }, player)
+				})
+			}
+
+		case SectionWorldBuilder:
 			if e.FilePath == "" {
 				GuiNoMapLoaded(body)
 				break
 			}
 
-			fallthrough
-		case SectionPlayers:
+			// Canvas keeps zoom, brush and selection while the same map is edited
+			if mapCanvas == nil || mapCanvas.Grid.Map != e.WbMap {
+				mapCanvas = NewMapCanvas(e.WbMap, e.Data)
+			} else {
+				// Plots may be changed in other sections
+				mapCanvas.Reload()
+			}
+			mapCanvas.History = history
+			GuiMapCanvas(body, mapCanvas)
+
+		case SectionCities:
 			if e.FilePath == "" {
 				GuiNoMapLoaded(body)
 				break
 			}
 
-			fallthrough
+			reopen := func() { openSection(SectionCities) }
+			grid := NewPlotGrid(e.WbMap)
+			data := e.Data.Registries()
+			status := widget.NewLabel("")
+			status.Wrapping = fyne.TextWrapBreak
+
+			// New city is placed on the plot by coordinates for the player selected in players section, and becomes selected
+			placeX, placeY := widget.NewEntry(), widget.NewEntry()
+			placeX.SetPlaceHolder("X")
+			placeY.SetPlaceHolder("Y")
+			body.Add(container.NewGridWithColumns(4, widget.NewLabel("Place city at"), placeX, placeY, widget.NewButton("Place city", func() {
+				x, y := ToInt(placeX.Text), ToInt(placeY.Text)
+				err := history.Try("Place city", func() error {
+					_, err := grid.PlaceCity(x, y, uint(currentPlayer))
+					return err
+				}, grid.GetPlot(x, y))
+				if err != nil {
+					status.SetText(err.Error())
+					return
+				}
+				currentCity = [2]int{x, y}
+				reopen()
+			})))
+
+			cityChoices := make([]Choice, 0)
+			for _, plot := range e.WbMap.GetCityPlots() {
+				position := fmt.Sprintf("%d,%d", plot.X, plot.Y)
+				cityChoices = append(cityChoices, Choice{Type: position, Description: plot.Cities[0].CityName + " (" + position + ")"})
+			}
+			selected := ""
+			if currentCity[0] >= 0 {
+				selected = fmt.Sprintf("%d,%d", currentCity[0], currentCity[1])
+			}
+			GuiSelectEntry(body, "City", "City", selected, cityChoices, func(s string) {
+				if parts := strings.Split(s, ","); len(parts) == 2 {
+					currentCity = [2]int{ToInt(parts[0]), ToInt(parts[1])}
+				}
+				reopen()
+			})
+			body.Add(status)
+
+			plot := grid.GetPlot(currentCity[0], currentCity[1])
+			if plot == nil || len(plot.Cities) == 0 {
+				break
+			}
+			city := plot.Cities[0]
+			if err := grid.ValidateCityPlot(currentCity[0], currentCity[1]); err != nil {
+				status.SetText("Warning: " + err.Error())
+			}
+			body.Add(widget.NewButton("Remove city", func() {
+				history.Update("Remove city", func() { grid.RemoveCity(currentCity[0], currentCity[1]) }, plot)
+				currentCity = [2]int{-1, -1}
+				reopen()
+			}))
+
+			body.Add(widget.NewSeparator())
+			owners := make([]Choice, 0, len(e.WbMap.Players))
+			for i, player := range e.WbMap.Players {
+				if !player.IsEmpty() {
+					owners = append(owners, Choice{Type: strconv.Itoa(i), Description: e.WbMap.GetPlayerName(i)})
+				}
+			}
+			GuiSelectEntry(body, "CityOwner", "Owner", strconv.Itoa(int(city.CityOwner)), owners, func(s string) { Set(history, "CityOwner", city, &city.CityOwner)(ToUint(s)) })
+			cityName := GuiTextField(body, e.Data, "CityName", "Name", city.CityName, SetText(history, "CityName", city, &city.CityName))
+			body.Add(widget.NewButton("Suggest name from the owner's city list", func() {
+				if name := e.WbMap.SuggestCityName(city.CityOwner); name != "" {
+					cityName.SetValue(name)
+				}
+			}))
+			GuiTextField(body, e.Data, "CityPopulation", "Population", strconv.Itoa(int(city.CityPopulation)), func(s string) { SetText(history, "CityPopulation", city, &city.CityPopulation)(ToUint(s)) })
+
+			// Production values depend on the kind, so the section is reopened when the kind changes
+			body.Add(widget.NewSeparator())
+			productionKind, production := city.GetProduction()
+			kinds := []Choice{{Description: "Nothing"}}
+			for _, kind := range ProductionKinds {
+				kinds = append(kinds, Choice{Type: string(kind), Description: string(kind)})
+			}
+			GuiSelectEntry(body, "ProductionKind", "Production", string(productionKind), kinds, func(s string) {
+				history.Update("ProductionKind", func() { city.SetProduction(ProductionKind(s), "") }, city)
+				reopen()
+			})
+			var productionChoices []Choice
+			switch productionKind {
+			case ProductionUnit:
+				productionChoices = GetChoices(data, data.UnitInfos, OrderName)
+			case ProductionBuilding:
+				productionChoices = GetChoices(data, data.BuildingInfos, OrderName)
+			case ProductionProject:
+				productionChoices = GetChoices(data, data.ProjectInfos, OrderName)
+			case ProductionProcess:
+				for _, process := range ProcessTypes {
+					productionChoices = append(productionChoices, Choice{Type: process, Description: data.GetLangString("TXT_KEY_" + process)})
+				}
+			}
+			if productionKind != "" || production != "" {
+				GuiSelectEntry(body, "Production", "Builds", production, productionChoices, func(s string) {
+					history.Update("Production", func() { city.SetProduction(productionKind, s) }, city)
+				})
+			}
+
+			body.Add(widget.NewSeparator())
+			body.Add(widget.NewLabelWithStyle("Religions", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+			religions := container.NewGridWithColumns(2)
+			for _, religion := range GetChoices(data, data.ReligionInfos, OrderXML) {
+				religionType := religion.Type
+				hasReligion := widget.NewCheck(religion.Description, func(b bool) {
+					history.Update("Religion", func() { city.SetReligion(religionType, b) }, city)
+					reopen()
+				})
+				hasReligion.Checked = IsInSlice(city.ReligionType, religionType)
+				holyCity := widget.NewCheck("Holy city", func(b bool) {
+					// Holy city is removed from other cities, so all of them are tracked
+					var cityPlots []any
+					for _, cityPlot := range e.WbMap.GetCityPlots() {
+						cityPlots = append(cityPlots, cityPlot)
+					}
+					history.Update("Holy city", func() { e.WbMap.SetHolyCity(city, religionType, b) }, cityPlots...)
+					reopen()
+				})
+				holyCity.Checked = IsInSlice(city.HolyCityReligionType, religionType)
+				religions.Add(hasReligion)
+				religions.Add(holyCity)
+			}
+			body.Add(religions)
+
+			body.Add(widget.NewSeparator())
+			body.Add(widget.NewLabelWithStyle("Culture", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+			for i, player := range e.WbMap.Players {
+				if player.IsEmpty() && city.PlayerCulture[uint(i)] == 0 {
+					continue
+				}
+				playerIndex := uint(i)
+				GuiTextField(body, e.Data, "PlayerCulture", e.WbMap.GetPlayerName(i), strconv.FormatUint(city.PlayerCulture[playerIndex], 10), func(s string) {
+					value, _ := strconv.ParseUint(s, 10, 64)
+					history.UpdateText("Culture"+strconv.Itoa(int(playerIndex)), func() { city.SetCulture(playerIndex, value) }, city)
+				})
+			}
+
+			body.Add(widget.NewSeparator())
+			body.Add(widget.NewLabelWithStyle("Buildings", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+			buildings := container.NewGridWithColumns(3)
+			GuiChoiceCheckboxes(buildings, "", GetChoices(data, data.BuildingInfos, OrderName), city.BuildingType, Set(history, "BuildingType", city, &city.BuildingType))
+			body.Add(buildings)
+
+		case SectionUnits:
+			if e.FilePath == "" {
+				GuiNoMapLoaded(body)
+				break
+			}
+
+			reopen := func() { openSection(SectionUnits) }
+			grid := NewPlotGrid(e.WbMap)
+			data := e.Data.Registries()
+			status := widget.NewLabel("")
+			status.Wrapping = fyne.TextWrapBreak
+			unitChoices := GetChoices(data, data.UnitInfos, OrderName)
+
+			// Units of every player are added to its starting plot
+			startingUnits := widget.NewEntry()
+			startingUnits.SetText("UNIT_SETTLER,UNIT_WARRIOR")
+			body.Add(container.NewGridWithColumns(3, widget.NewLabel("Units at every player's start"), startingUnits, widget.NewButton("Add starting units", func() {
+				var added int
+				var err error
+				history.Update("Starting units", func() {
+					added, err = grid.AddStartingUnits(data, strings.Split(strings.ReplaceAll(startingUnits.Text, " ", ""), ","))
+				}, e.WbMap)
+				text := strconv.Itoa(added) + " units added"
+				if err != nil {
+					text += "\n" + err.Error()
+				}
+				status.SetText(text)
+			})))
+
+			plotX, plotY := widget.NewEntry(), widget.NewEntry()
+			plotX.SetPlaceHolder("X")
+			plotY.SetPlaceHolder("Y")
+			if currentUnitPlot[0] >= 0 {
+				plotX.SetText(strconv.Itoa(currentUnitPlot[0]))
+				plotY.SetText(strconv.Itoa(currentUnitPlot[1]))
+			}
+			body.Add(container.NewGridWithColumns(4, widget.NewLabel("Plot"), plotX, plotY, widget.NewButton("Open plot", func() {
+				currentUnitPlot = [2]int{ToInt(plotX.Text), ToInt(plotY.Text)}
+				reopen()
+			})))
+			body.Add(status)
+
+			plot := grid.GetPlot(currentUnitPlot[0], currentUnitPlot[1])
+			if plot == nil {
+				break
+			}
+
+			// New unit is added for the player selected in players section
+			newUnitType := ""
+			GuiSelectEntry(body, "UnitType", "New unit", "", unitChoices, func(s string) { newUnitType = s })
+			body.Add(widget.NewButton("Add unit to the plot", func() {
+				err := history.Try("Add unit", func() error {
+					_, err := grid.AddUnit(data, currentUnitPlot[0], currentUnitPlot[1], newUnitType, currentPlayer)
+					return err
+				}, plot)
+				if err != nil {
+					status.SetText(err.Error())
+					return
+				}
+				reopen()
+			}))
+
+			owners := make([]Choice, 0, len(e.WbMap.Players))
+			for i, player := range e.WbMap.Players {
+				if !player.IsEmpty() {
+					owners = append(owners, Choice{Type: strconv.Itoa(i), Description: e.WbMap.GetPlayerName(i)})
+				}
+			}
+			facings := make([]Choice, 0, len(FacingDirections))
+			for i, direction := range FacingDirections {
+				facings = append(facings, Choice{Type: strconv.Itoa(i), Description: direction})
+			}
+			promotions := GetChoices(data, data.PromotionInfos, OrderName)
+
+			for i, unit := range plot.Units {
+				index, unit := i, unit
+				body.Add(widget.NewSeparator())
+				body.Add(container.NewHBox(
+					widget.NewLabelWithStyle(fmt.Sprintf("Unit %d", index+1), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+					widget.NewButton("Remove", func() {
+						err := history.Try("Remove unit", func() error { return grid.RemoveUnit(currentUnitPlot[0], currentUnitPlot[1], index) }, plot)
+						if err != nil {
+							ConsoleWrite(err.Error())
+						}
+						reopen()
+					}),
+				))
+				GuiSelectEntry(body, "UnitType", "Type", unit.UnitType, unitChoices, func(s string) {
+					Set(history, "UnitType", unit, &unit.UnitType)(s)
+					reopen()
+				})
+				GuiSelectEntry(body, "UnitOwner", "Owner", strconv.Itoa(unit.UnitOwner), owners, func(s string) { Set(history, "UnitOwner", unit, &unit.UnitOwner)(ToInt(s)) })
+				GuiTextField(body, e.Data, "Level", "Level", strconv.Itoa(unit.Level), func(s string) { SetText(history, "Level", unit, &unit.Level)(ToInt(s)) })
+				GuiTextField(body, e.Data, "Experience", "Experience", strconv.Itoa(unit.Experience), func(s string) { SetText(history, "Experience", unit, &unit.Experience)(ToInt(s)) })
+				GuiTextField(body, e.Data, "Damage", "Damage (0-100)", strconv.Itoa(int(unit.Damage)), func(s string) { SetText(history, "Damage", unit, &unit.Damage)(ToUint(s)) })
+				GuiSelectEntry(body, "FacingDirection", "Facing", strconv.Itoa(unit.FacingDirection), facings, func(s string) { Set(history, "FacingDirection", unit, &unit.FacingDirection)(ToInt(s)) })
+
+				var unitAIs []Choice
+				if info, ok := data.UnitInfos[unit.UnitType]; ok {
+					for _, unitAI := range info.UnitAIs {
+						unitAIs = append(unitAIs, Choice{Type: unitAI, Description: unitAI})
+					}
+				}
+				GuiSelectEntry(body, "UnitAIType", "AI", unit.UnitAIType, unitAIs, Set(history, "UnitAIType", unit, &unit.UnitAIType))
+
+				// Only promotions for the unit combat type are shown, adding a promotion without prerequisites fails,
+				// removing a promotion removes dependent ones
+				unitPromotions := container.NewGridWithColumns(3)
+				for _, promotion := range promotions {
+					promotionType := promotion.Type
+					if !data.IsPromotionForUnit(unit.UnitType, promotionType) && !IsInSlice(unit.PromotionType, promotionType) {
+						continue
+					}
+					cb := widget.NewCheck(promotion.Description, func(b bool) {
+						if b {
+							err := history.Try("Promotion", func() error { return data.AddPromotion(unit, promotionType) }, unit)
+							if err != nil {
+								status.SetText(err.Error())
+							}
+						} else {
+							history.Update("Promotion", func() { data.RemovePromotion(unit, promotionType) }, unit)
+						}
+						reopen()
+					})
+					cb.Checked = IsInSlice(unit.PromotionType, promotionType)
+					unitPromotions.Add(cb)
+				}
+				body.Add(unitPromotions)
+			}
+
+		case SectionWorkspace:
+			reopen := func() { openSection(SectionWorkspace) }
+
+			// New project contains all open maps
+			body.Add(container.NewHBox(
+				widget.NewButton("New project", func() {
+					d := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+						if err != nil {
+							showError(err)
+							return
+						}
+						if writer == nil {
+							return
+						}
+
+						path := writer.URI().Path()
+						writer.Close()
+						w := NewWorkspace(path)
+						w.Mod = GlobalConfig.Mod
+						for _, doc := range e.Documents {
+							w.AddMap(doc.FilePath)
+						}
+						if err = w.Save(); err != nil {
+							showError(err)
+							return
+						}
+						e.Workspace = w
+						GlobalConfig.AddRecentFile(path)
+						saveConfig()
+						updateTitle()
+						reopen()
+					}, editor)
+					d.SetFileName("Scenario" + WorkspaceExt)
+					d.Show()
+				}),
+				widget.NewButton("Open project", func() {
+					d := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+						if err != nil {
+							showError(err)
+							return
+						}
+						if reader == nil {
+							return
+						}
+
+						path := reader.URI().Path()
+						reader.Close()
+						openWorkspace(path)
+					}, editor)
+					d.SetFilter(storage.NewExtensionFileFilter([]string{WorkspaceExt}))
+					d.Show()
+				}),
+			))
+
+			if e.Workspace == nil {
+				body.Add(widget.NewLabel("Project keeps maps, target mod and notes of a scenario together"))
+			} else {
+				w := e.Workspace
+				body.Add(widget.NewSeparator())
+				GuiTextField(body, e.Data, "ProjectName", "Project name", w.Name, func(s string) {
+					w.Name = s
+					updateTitle()
+				})
+				mods := []Choice{{Description: "Base game"}}
+				for _, mod := range GetModsList(GlobalConfig.GameDir) {
+					mods = append(mods, Choice{Type: mod, Description: mod})
+				}
+				GuiSelectEntry(body, "ProjectMod", "Target mod (applied when the project is opened)", w.Mod, mods, func(s string) { w.Mod = s })
+				GuiMultilineField(body, "Notes", strings.Split(w.Notes, "\n"), func(lines []string) { w.Notes = strings.Join(lines, "\n") })
+
+				body.Add(widget.NewLabelWithStyle("Maps", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+				for _, path := range w.GetMapPaths() {
+					path := path
+					buttons := container.NewHBox(
+						widget.NewButton("Open", func() { openPath(path) }),
+						widget.NewButton("Remove from project", func() {
+							w.RemoveMap(path)
+							reopen()
+						}),
+					)
+					body.Add(container.NewBorder(nil, nil, nil, buttons, widget.NewLabel(path)))
+				}
+				if e.FilePath != "" {
+					body.Add(widget.NewButton("Add current map to the project", func() {
+						w.AddMap(e.FilePath)
+						reopen()
+					}))
+				}
+				body.Add(widget.NewButton("Save project", func() {
+					if err := w.Save(); err != nil {
+						showError(err)
+					}
+				}))
+			}
+
+			// Data of another open map is copied to the current one (player and team selected in their sections)
+			if e.FilePath == "" || len(e.Documents) < 2 {
+				break
+			}
+			var source *MapDocument
+			sources := make([]Choice, 0, len(e.Documents))
+			for _, doc := range e.Documents {
+				if doc == activeDoc {
+					continue
+				}
+				sources = append(sources, Choice{Type: doc.FilePath, Description: filepath.Base(doc.FilePath)})
+				if source == nil || doc.FilePath == copySource {
+					source = doc
+				}
+			}
+			copySource = source.FilePath
+			status := widget.NewLabel("")
+
+			body.Add(widget.NewSeparator())
+			body.Add(widget.NewLabelWithStyle("Copy from another open map", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+			GuiSelectEntry(body, "CopySource", "Source map", copySource, sources, func(s string) {
+				copySource = s
+				reopen()
+			})
+			body.Add(widget.NewButton("Copy game settings", func() {
+				if err := history.Try("Copy game settings", func() error { return e.WbMap.CopyGameFrom(source.WbMap) }, e.WbMap.Game); err != nil {
+					status.SetText(err.Error())
+					return
+				}
+				status.SetText("Game settings copied")
+			}))
+
+			sourcePlayers := make([]Choice, 0, len(source.WbMap.Players))
+			for i, player := range source.WbMap.Players {
+				if !player.IsEmpty() {
+					sourcePlayers = append(sourcePlayers, Choice{Type: strconv.Itoa(i), Description: source.WbMap.GetPlayerName(i)})
+				}
+			}
+			if len(sourcePlayers) > 0 && currentPlayer < len(e.WbMap.Players) {
+				sourcePlayer, target := ToInt(sourcePlayers[0].Type), e.WbMap.Players[currentPlayer]
+				GuiSelectEntry(body, "CopyPlayer", "Source player", sourcePlayers[0].Type, sourcePlayers, func(s string) { sourcePlayer = ToInt(s) })
+				body.Add(widget.NewButton("Copy player settings to "+e.WbMap.GetPlayerName(currentPlayer), func() {
+					history.Update("Copy player", func() { target.CopySettingsFrom(source.WbMap.Players[sourcePlayer]) }, target)
+					status.SetText("Player settings copied")
+				}))
+			}
+
+			if target := e.WbMap.GetTeam(currentTeam); target != nil && len(source.WbMap.Teams) > 0 {
+				sourceTeam := source.WbMap.Teams[0].TeamID
+				GuiSelectEntry(body, "CopyTeam", "Source team", strconv.Itoa(int(sourceTeam)), source.WbMap.GetTeamChoices(), func(s string) { sourceTeam = ToUint(s) })
+				body.Add(widget.NewButton("Copy technologies and projects to "+e.WbMap.GetTeamName(currentTeam), func() {
+					if team := source.WbMap.GetTeam(sourceTeam); team != nil {
+						history.Update("Copy team", func() { target.CopyTechsFrom(team) }, target)
+						status.SetText("Technologies and projects copied")
+					}
+				}))
+			}
+
+			// Plots are copied to the rectangle starting at the target plot
+			fromX1, fromY1, fromX2, fromY2, toX, toY := widget.NewEntry(), widget.NewEntry(), widget.NewEntry(), widget.NewEntry(), widget.NewEntry(), widget.NewEntry()
+			for entry, placeholder := range map[*widget.Entry]string{fromX1: "X1", fromY1: "Y1", fromX2: "X2", fromY2: "Y2", toX: "X", toY: "Y"} {
+				entry.SetPlaceHolder(placeholder)
+			}
+			body.Add(container.NewGridWithColumns(5, widget.NewLabel("Source plots"), fromX1, fromY1, fromX2, fromY2))
+			body.Add(container.NewGridWithColumns(4, widget.NewLabel("To plot (bottom left)"), toX, toY, widget.NewButton("Copy plots", func() {
+				grid := NewPlotGrid(e.WbMap)
+				grid.OnBeforeChange = history.Track
+				selection := Selection{ToInt(fromX1.Text), ToInt(fromY1.Text), ToInt(fromX2.Text), ToInt(fromY2.Text)}
+				var changed []*Plot
+				history.Update("Copy plots", func() {
+					changed = grid.CopyPlotsFrom(NewPlotGrid(source.WbMap), selection, ToInt(toX.Text), ToInt(toY.Text))
+				})
+				// Copied plots may be new, so the canvas is created again
+				mapCanvas = nil
+				status.SetText(strconv.Itoa(len(changed)) + " plots copied")
+			})))
+			body.Add(status)
+
+		case SectionLoadReport:
+			GuiLoadReport(body, e.Data.Registries().XmlLoadReport)
+
+			lintResult := container.NewVBox()
+			body.Add(widget.NewSeparator())
+			body.Add(widget.NewButton("Check mod for missing texts and broken references", func() {
+				progress.Start("Checking XML files...")
+				defer progress.Stop()
+				lintResult.RemoveAll()
+				GuiLintReport(lintResult, LintGameData(e.Data))
+			}))
+			body.Add(lintResult)
+
 		default:
 			currentSection = 0
 			body.Add(container.NewCenter(canvas.NewText("Start with selecting what to edit", color.White)))
+			if len(GlobalConfig.RecentFiles) > 0 {
+				body.Add(widget.NewLabelWithStyle("Recent files", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+			}
+			for _, path := range GlobalConfig.RecentFiles {
+				path := path
+				body.Add(widget.NewButton(path, func() { openPath(path) }))
+			}
 		}
 	}
 
 	// Menu with sections to switch between
 	menu := container.NewVBox(
+		widget.NewButton("Project", func() { openSection(SectionWorkspace) }),
 		widget.NewButton("Map settings", func() { openSection(SectionMapSettings) }),
 		widget.NewButton("Teams", func() { openSection(SectionTeams) }),
 		widget.NewButton("Players", func() { openSection(SectionPlayers) }),
+		widget.NewButton("WorldBuilder", func() { openSection(SectionWorldBuilder) }),
+		widget.NewButton("Cities", func() { openSection(SectionCities) }),
+		widget.NewButton("Units", func() { openSection(SectionUnits) }),
+		widget.NewButton("Load report", func() { openSection(SectionLoadReport) }),
 	)
 
 	// Some kind of reactivity to update all content when something changes externally (like file loaded etc.)
@@ -141,38 +922,206 @@ func (e *Editor) ShowEditor() {
 		updateContent()
 	}
 
-	// Open map file, parse it and load into editor
-	openFile := func() {
-		dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
-			progress.Start("Loading and parsing map...")
-			defer progress.Stop()
-			if err != nil {
-				ConsoleWrite(err.Error())
-				dialog.ShowError(err, editor)
-				return
+	// Undone changes may affect any section, so the current one is reopened
+	undo := func() {
+		// The stroke being painted is recorded first, so it's undone
+		if mapCanvas != nil {
+			mapCanvas.DragEnd()
+		}
+		if e.History.Undo() {
+			if mapCanvas != nil {
+				mapCanvas.Reload()
 			}
-			if reader == nil {
+			updateContent()
+		}
+	}
+	redo := func() {
+		if mapCanvas != nil {
+			mapCanvas.DragEnd()
+		}
+		if e.History.Redo() {
+			if mapCanvas != nil {
+				mapCanvas.Reload()
+			}
+			updateContent()
+		}
+	}
+	editor.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyZ, Modifier: fyne.KeyModifierShortcutDefault}, func(fyne.Shortcut) { undo() })
+	editor.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyY, Modifier: fyne.KeyModifierShortcutDefault}, func(fyne.Shortcut) { redo() })
+
+	// Switch to another open map, nil means no maps are open
+	activate := func(doc *MapDocument) {
+		activeDoc = doc
+		if doc == nil {
+			e.FilePath, e.WbMap, e.History = "", &WbMap{Version: 1, Game: &Game{}}, NewHistory()
+		} else {
+			e.FilePath, e.WbMap, e.History = doc.FilePath, doc.WbMap, doc.History
+			if i := slices.Index(e.Documents, doc); tabs.SelectedIndex() != i {
+				tabs.SelectIndex(i)
+			}
+		}
+		currentCity, currentUnitPlot = [2]int{-1, -1}, [2]int{-1, -1}
+		updateTitle()
+		updateAll()
+	}
+	// Add the map to tabs and make it active, already open map is only activated
+	addDocument := func(doc *MapDocument) {
+		for _, other := range e.Documents {
+			if doc.FilePath != "" && other.FilePath == doc.FilePath {
+				activate(other)
 				return
 			}
+		}
 
-			defer reader.Close()
-			wbMap, err := ParseWbMap(reader)
-			if err != nil {
-				errorText := err.Error()
-				ConsoleWrite(errorText)
+		doc.History.OnChange = updateTitle
+		autosaver := &Autosaver{OnError: func(err error) { Logger.Warn("Autosave failed", "error", err) }}
+		autosaver.SetMap(doc.FilePath, doc.WbMap, doc.History)
+		if GlobalConfig.AutoSave {
+			autosaver.Start(AutosaveInterval)
+		}
+		autosavers[doc] = autosaver
+		e.Documents = append(e.Documents, doc)
+		tabs.Append(container.NewTabItem(doc.GetTitle(), widget.NewLabel("")))
+		activate(doc)
+	}
+	// Close the map without saving, recovery is not needed then
+	closeDocument := func(doc *MapDocument) {
+		index := slices.Index(e.Documents, doc)
+		if index < 0 {
+			return
+		}
+
+		autosavers[doc].Stop()
+		autosavers[doc].RemoveRecovery()
+		delete(autosavers, doc)
+		e.Documents = slices.Delete(e.Documents, index, index+1)
+		tabs.RemoveIndex(index)
+		if doc != activeDoc {
+			return
+		}
+		if len(e.Documents) > 0 {
+			activate(e.Documents[max(index-1, 0)])
+		} else {
+			activate(nil)
+		}
+	}
+	tabs.OnSelected = func(item *container.TabItem) {
+		if i := slices.Index(tabs.Items, item); i >= 0 && i < len(e.Documents) && e.Documents[i] != activeDoc {
+			activate(e.Documents[i])
+		}
+	}
+	tabs.CloseIntercept = func(item *container.TabItem) {
+		i := slices.Index(tabs.Items, item)
+		if i < 0 || i >= len(e.Documents) {
+			return
+		}
+		doc := e.Documents[i]
+		if !doc.History.IsDirty() {
+			closeDocument(doc)
+			return
+		}
+		dialog.ShowConfirm("Unsaved changes", doc.GetTitle()+" has unsaved changes. Close anyway?", func(ok bool) {
+			if ok {
+				closeDocument(doc)
+			}
+		}, editor)
+	}
 
-				// Truncate error text if it's too long for dialog
-				if len(errorText) > 100 {
-					errorText = errorText[:100] + "..."
+	// Load game data for the configured game directory and mod, showing what file is parsed at the moment
+	loadData := func() error {
+		ctx, reporter := StartProgress(context.Background(), "Loading game data")
+		err := e.Data.Reload(ctx)
+		reporter.Finish(err)
+		if errors.Is(err, context.Canceled) {
+			ConsoleWrite("Loading game data is cancelled, previous data is kept")
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if currentSection == SectionLoadReport {
+			updateContent()
+		}
+		return nil
+	}
+
+	// Open map file or project by path, missing files are removed from recent files
+	openPath = func(path string) {
+		if IsWorkspaceFile(path) {
+			openWorkspace(path)
+			return
+		}
+
+		ctx, reporter := StartProgress(context.Background(), "Opening "+filepath.Base(path))
+		doc, err := OpenMapDocument(ctx, path)
+		reporter.Finish(err)
+		if errors.Is(err, context.Canceled) {
+			return
+		} else if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				GlobalConfig.RemoveRecentFile(path)
+				saveConfig()
+			}
+			showError(err)
+			return
+		}
+
+		GlobalConfig.AddRecentFile(path)
+		saveConfig()
+		addDocument(doc)
+	}
+
+	// Open project with all its maps, game data is reloaded if the project is made for another mod
+	openWorkspace = func(path string) {
+		w, err := LoadWorkspace(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				GlobalConfig.RemoveRecentFile(path)
+				saveConfig()
+			}
+			showError(err)
+			return
+		}
+
+		e.Workspace = w
+		GlobalConfig.AddRecentFile(path)
+		if w.Mod != GlobalConfig.Mod {
+			GlobalConfig.Mod = w.Mod
+			e.Data = NewGameData(GlobalConfig.GameDir, GlobalConfig.Mod)
+			go func() {
+				if err := loadData(); err != nil {
+					showError(err)
 				}
-				dialog.ShowError(errors.New(errorText), editor)
+			}()
+		}
+		saveConfig()
+
+		for _, mapPath := range w.GetMapPaths() {
+			openPath(mapPath)
+		}
+		openSection(SectionWorkspace)
+	}
+
+	// Open map file or project, the dialog starts in the directory of the latest recent file
+	openFile := func() {
+		d := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				showError(err)
+				return
+			}
+			if reader == nil {
 				return
 			}
 
-			e.WbMap, e.FilePath = wbMap, reader.URI().Path()
-			currentSection = SectionWelcome // not using openSection() to prevent reload, we'll do it manually in the next line
-			updateAll()
-		}, editor).Show()
+			path := reader.URI().Path()
+			reader.Close()
+			openPath(path)
+		}, editor)
+		if len(GlobalConfig.RecentFiles) > 0 {
+			if dir, err := storage.ListerForURI(storage.NewFileURI(filepath.Dir(GlobalConfig.RecentFiles[0]))); err == nil {
+				d.SetLocation(dir)
+			}
+		}
+		d.Show()
 	}
 
 	saveFile := func() {
@@ -180,9 +1129,6 @@ func (e *Editor) ShowEditor() {
 			return
 		}
 
-		progress.Start("Saving...")
-		defer progress.Stop()
-
 		if e.FilePath == "" {
 			dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
 				if err != nil {
@@ -194,28 +1140,45 @@ func (e *Editor) ShowEditor() {
 					return
 				}
 
-				defer writer.Close()
-				_, err = writer.Write(e.WbMap.ToWbFormat())
+				// The file is written atomically by path, the writer is only used to choose it
+				path := writer.URI().Path()
+				writer.Close()
+				ctx, reporter := StartProgress(context.Background(), "Saving "+filepath.Base(path))
+				err = SaveWbMap(ctx, path, e.WbMap)
+				reporter.Finish(err)
 				if err != nil {
 					ConsoleWrite(err.Error())
 					dialog.ShowError(err, editor)
+					return
 				}
+				GlobalConfig.AddRecentFile(path)
+				saveConfig()
+				e.History.MarkSaved()
+				addDocument(&MapDocument{FilePath: path, WbMap: e.WbMap, History: e.History})
 			}, editor).Show()
 		} else {
-			err := os.WriteFile(e.FilePath, e.WbMap.ToWbFormat(), 0644)
+			ctx, reporter := StartProgress(context.Background(), "Saving "+filepath.Base(e.FilePath))
+			err := SaveWbMap(ctx, e.FilePath, e.WbMap)
+			reporter.Finish(err)
 			if err != nil {
 				ConsoleWrite(err.Error())
 				dialog.ShowError(err, editor)
 				return
 			}
+			e.History.MarkSaved()
 		}
 	}
 
 	toolbar := widget.NewToolbar(
 		widget.NewToolbarAction(theme.FolderOpenIcon(), openFile),
 		widget.NewToolbarAction(theme.DocumentSaveIcon(), saveFile),
+		widget.NewToolbarAction(theme.ContentUndoIcon(), undo),
+		widget.NewToolbarAction(theme.ContentRedoIcon(), redo),
 		widget.NewToolbarAction(theme.MediaPlayIcon(), func() {
-			err := LaunchGame(e.FilePath)
+			profile, err := GlobalConfig.GetLaunchProfile("")
+			if err == nil {
+				err = LaunchGame(profile, e.FilePath, LaunchNewGame)
+			}
 			if err != nil {
 				ConsoleWrite(err.Error())
 				dialog.ShowError(err, editor)
@@ -236,49 +1199,87 @@ func (e *Editor) ShowEditor() {
 		}),
 	)
 
-	window := container.NewBorder(toolbar, progress.GetBlock(), menu, nil, container.NewScroll(body))
+	window := container.NewBorder(container.NewVBox(toolbar, tabs), progress.GetBlock(), menu, nil, container.NewScroll(body))
 	editor.SetContent(window)
+	// Recovery is not needed after the editor is closed by user
+	closeEditor := func() {
+		for _, autosaver := range autosavers {
+			autosaver.Stop()
+			autosaver.RemoveRecovery()
+		}
+		editor.Close()
+	}
+	editor.SetCloseIntercept(func() {
+		dirty := 0
+		for _, doc := range e.Documents {
+			if doc.History.IsDirty() {
+				dirty++
+			}
+		}
+		if dirty == 0 {
+			closeEditor()
+			return
+		}
+		dialog.ShowConfirm("Unsaved changes", fmt.Sprintf("%d open maps have unsaved changes. Close anyway?", dirty), func(ok bool) {
+			if ok {
+				closeEditor()
+			}
+		}, editor)
+	})
 	editor.Show()
 	editor.SetMaster()
 
 	// After creating interfaces and callbacks, we need to load game data and invite user to open map file
 	go func() {
-		progress.Start("Parsing game XML files")
-		// Show what file is parsed at the moment
-		parsingProgressHandler := func(s string) {
-			// Remove game dir from path to make it shorter (better UX)
-			s = strings.ReplaceAll(s, GlobalConfig.GameDir+"\\", "")
-			s = strings.ReplaceAll(s, GlobalConfig.GameDir+string(os.PathSeparator), "")
-			s = strings.ReplaceAll(s, GlobalConfig.GameDir, "")
-			if len(s) > 170 {
-				s = s[:170] + "..."
-			}
-
-			progress.Stop()
-			progress.Start(s)
-		}
-
-		err := LoadAllXML(parsingProgressHandler)
-		if err != nil {
+		if err := loadData(); err != nil {
 			ConsoleWrite(err.Error())
 			dialog.ShowError(err, editor)
 			return
 		}
 
-		progress.Stop()
+		// Offer to recover the map autosaved before a crash, it's removed if user refuses
+		if recoveries := ListRecoveries(); e.FilePath == "" && len(recoveries) > 0 {
+			recovery := recoveries[0]
+			name := recovery.FilePath
+			if name == "" {
+				name = "a new map"
+			}
+			message := fmt.Sprintf("Unsaved changes of %s from %s were found. Recover them?", name, recovery.SavedAt.Format("2006-01-02 15:04"))
+			dialog.ShowConfirm("Recover unsaved changes", message, func(ok bool) {
+				if !ok {
+					recovery.Remove()
+					openFile()
+					return
+				}
+
+				wbMap, err := recovery.Load()
+				if err != nil {
+					ConsoleWrite(err.Error())
+					dialog.ShowError(err, editor)
+					return
+				}
+				doc := &MapDocument{FilePath: recovery.FilePath, WbMap: wbMap, History: NewHistory()}
+				addDocument(doc)
+				autosavers[doc].UseRecovery(recovery)
+				doc.History.MarkUnsaved()
+			}, editor)
+			return
+		}
 
 		// Select/open test file if not already set
 		if e.FilePath == "" {
 			// Open test file if it exists. It's developed for testing and debugging purposes
 			if *DevMode && e.testFileExists() {
-				e.FilePath, e.WbMap = e.openTestFile()
-				if e.WbMap != nil {
-					updateAll()
+				if path, wbMap := e.openTestFile(); wbMap != nil {
+					addDocument(&MapDocument{FilePath: path, WbMap: wbMap, History: NewHistory()})
 					return
 				}
 			}
 
-			openFile()
+			// The start page shows recent files, so the dialog is opened only if there are none
+			if len(GlobalConfig.RecentFiles) == 0 {
+				openFile()
+			}
 		}
 	}()
 }
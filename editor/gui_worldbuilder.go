@@ -0,0 +1,500 @@
+package editor
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CanvasMode is an action of the map canvas on tap and drag
+type CanvasMode string
+
+const (
+	CanvasPaint  CanvasMode = "Paint"
+	CanvasFill   CanvasMode = "Fill"
+	CanvasSelect CanvasMode = "Select"
+	CanvasPan    CanvasMode = "Pan"
+)
+
+// CanvasModes contains all modes in the order they are shown in the editor
+var CanvasModes = []CanvasMode{CanvasPaint, CanvasFill, CanvasSelect, CanvasPan}
+
+const (
+	minCanvasZoom     = 2
+	maxCanvasZoom     = 64
+	defaultCanvasZoom = 8
+)
+
+// terrainColors are colors of known terrains, other terrains get a color generated from their type
+var terrainColors = map[string]color.RGBA{
+	"TERRAIN_GRASS":  {R: 76, G: 140, B: 48, A: 255},
+	"TERRAIN_PLAINS": {R: 158, G: 148, B: 70, A: 255},
+	"TERRAIN_DESERT": {R: 222, G: 202, B: 132, A: 255},
+	"TERRAIN_TUNDRA": {R: 138, G: 128, B: 108, A: 255},
+	"TERRAIN_SNOW":   {R: 238, G: 240, B: 245, A: 255},
+	"TERRAIN_COAST":  {R: 70, G: 130, B: 200, A: 255},
+	"TERRAIN_OCEAN":  {R: 30, G: 68, B: 150, A: 255},
+	"TERRAIN_PEAK":   {R: 110, G: 100, B: 95, A: 255},
+	"TERRAIN_HILL":   {R: 120, G: 110, B: 70, A: 255},
+}
+
+var (
+	peakColor      = color.RGBA{R: 90, G: 85, B: 80, A: 255}
+	featureColor   = color.RGBA{R: 20, G: 70, B: 20, A: 255}
+	riverColor     = color.RGBA{R: 40, G: 110, B: 255, A: 255}
+	bonusColor     = color.RGBA{R: 255, G: 220, B: 40, A: 255}
+	routeColor     = color.RGBA{R: 90, G: 60, B: 30, A: 255}
+	selectionColor = color.RGBA{R: 255, G: 255, B: 0, A: 255}
+	emptyColor     = color.RGBA{R: 20, G: 20, B: 20, A: 255}
+)
+
+// MapCanvas is a zoomable and pannable widget drawing plots of the map, plots are changed by brush (see PlotGrid)
+type MapCanvas struct {
+	widget.BaseWidget
+	Grid *PlotGrid
+	Data *GameData
+	Mode CanvasMode
+	// Brush is used in paint and fill modes
+	Brush Brush
+	// Selection is a selected rectangle or nil if nothing is selected
+	Selection *Selection
+	// OnChange is called with changed plots after every paint or fill
+	OnChange func(plots []*Plot)
+	// OnError is called if brush cannot be applied
+	OnError func(err error)
+	// History records every stroke, fill or selection fill as one step, changes are not recorded if it's nil
+	History *History
+
+	zoom             float32
+	offsetX, offsetY float32
+	dragStart        *[2]int
+	// stroke is a brush stroke being dragged, it's recorded as one step when the drag ends
+	stroke *canvasStroke
+	raster *canvas.Raster
+
+	// tiles are cached looks of plots by grid index, so drawing doesn't read plots changed by the brush
+	mu    sync.RWMutex
+	tiles []canvasTile
+}
+
+// canvasTile is a look of the plot drawn by the canvas
+type canvasTile struct {
+	exists bool
+	color  color.RGBA
+	river  [2]bool
+	bonus  bool
+	route  bool
+}
+
+// canvasStroke keeps states of plots before the stroke. History lock is not held between drag events,
+// so the stroke is recorded as one step when it ends (see MapCanvas.DragEnd)
+type canvasStroke struct {
+	targets []any
+	before  []func()
+	tracked map[any]bool
+}
+
+func (s *canvasStroke) track(target any) {
+	if s.tracked[target] {
+		return
+	}
+	if before := takeSnapshot(target); before != nil {
+		s.tracked[target] = true
+		s.targets = append(s.targets, target)
+		s.before = append(s.before, before)
+	}
+}
+
+// NewMapCanvas returns a canvas for the map with paint mode and default zoom
+func NewMapCanvas(m *WbMap, data *GameData) *MapCanvas {
+	c := &MapCanvas{
+		Grid:  NewPlotGrid(m),
+		Data:  data,
+		Mode:  CanvasPaint,
+		Brush: Brush{Tool: BrushTerrain, Size: 1},
+		zoom:  defaultCanvasZoom,
+	}
+	c.Grid.OnBeforeChange = func(target any) {
+		if c.stroke != nil {
+			c.stroke.track(target)
+		} else if c.History != nil {
+			c.History.Track(target)
+		}
+	}
+	c.reloadTiles()
+	c.raster = canvas.NewRaster(c.draw)
+	c.raster.SetMinSize(fyne.NewSize(800, 600))
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+func (c *MapCanvas) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(c.raster)
+}
+
+// SetZoom changes size of a plot in pixels, keeping the point of the widget at the same place of the map
+func (c *MapCanvas) SetZoom(zoom float32, around fyne.Position) {
+	zoom = float32(math.Max(minCanvasZoom, math.Min(maxCanvasZoom, float64(zoom))))
+	c.offsetX = around.X - (around.X-c.offsetX)*zoom/c.zoom
+	c.offsetY = around.Y - (around.Y-c.offsetY)*zoom/c.zoom
+	c.zoom = zoom
+	c.Refresh()
+}
+
+// ZoomBy multiplies zoom by the factor around the center of the widget
+func (c *MapCanvas) ZoomBy(factor float32) {
+	size := c.Size()
+	c.SetZoom(c.zoom*factor, fyne.NewPos(size.Width/2, size.Height/2))
+}
+
+// Reload updates the canvas after plots are changed outside of it, e.g. by undo
+func (c *MapCanvas) Reload() {
+	c.Grid.Reload()
+	c.reloadTiles()
+	c.Refresh()
+}
+
+// ResetView restores default zoom and moves the map to the top left corner
+func (c *MapCanvas) ResetView() {
+	c.zoom, c.offsetX, c.offsetY = defaultCanvasZoom, 0, 0
+	c.Refresh()
+}
+
+// FillSelection applies the brush to the selected plots
+func (c *MapCanvas) FillSelection() {
+	if c.Selection == nil {
+		return
+	}
+	c.beginStep("Fill selection")
+	defer c.endStep()
+	c.handleResult(c.Grid.FillSelection(*c.Selection, c.Brush))
+}
+
+// beginStep starts a history step, plots changed by the brush are tracked until endStep. Steps must not last
+// longer than one event, use the stroke for dragging
+func (c *MapCanvas) beginStep(name string) {
+	if c.History != nil {
+		c.History.Begin(name)
+	}
+}
+
+func (c *MapCanvas) endStep() {
+	if c.History != nil {
+		c.History.End()
+	}
+}
+
+// getPlotPosition returns plot coordinates at the widget position. Rows start from the bottom, as in the map file
+func (c *MapCanvas) getPlotPosition(pos fyne.Position) (int, int) {
+	x := int(math.Floor(float64((pos.X - c.offsetX) / c.zoom)))
+	row := int(math.Floor(float64((pos.Y - c.offsetY) / c.zoom)))
+	return x, c.Grid.Height - 1 - row
+}
+
+func (c *MapCanvas) Tapped(e *fyne.PointEvent) {
+	x, y := c.getPlotPosition(e.Position)
+	switch c.Mode {
+	case CanvasPaint:
+		c.beginStep("Paint")
+		c.handleResult(c.Grid.Paint(x, y, c.Brush))
+		c.endStep()
+	case CanvasFill:
+		c.beginStep("Flood fill")
+		c.handleResult(c.Grid.FloodFill(x, y, c.Brush))
+		c.endStep()
+	case CanvasSelect:
+		c.Selection = &Selection{x, y, x, y}
+		c.Refresh()
+	}
+}
+
+func (c *MapCanvas) Dragged(e *fyne.DragEvent) {
+	x, y := c.getPlotPosition(e.Position)
+	switch c.Mode {
+	case CanvasPaint:
+		// The whole stroke is one history step
+		if c.stroke == nil {
+			c.stroke = &canvasStroke{tracked: make(map[any]bool)}
+		}
+		var plots []*Plot
+		var err error
+		c.change(func() { plots, err = c.Grid.Paint(x, y, c.Brush) })
+		c.handleResult(plots, err)
+	case CanvasSelect:
+		if c.dragStart == nil {
+			c.dragStart = &[2]int{x, y}
+		}
+		c.Selection = &Selection{c.dragStart[0], c.dragStart[1], x, y}
+		c.Refresh()
+	case CanvasPan:
+		c.offsetX += e.Dragged.DX
+		c.offsetY += e.Dragged.DY
+		c.Refresh()
+	}
+}
+
+func (c *MapCanvas) DragEnd() {
+	c.dragStart = nil
+	if c.stroke == nil {
+		return
+	}
+
+	stroke := c.stroke
+	c.stroke = nil
+	if c.History == nil || len(stroke.targets) == 0 {
+		return
+	}
+
+	// Plots are painted already, so they are restored to record the stroke as a usual step
+	after := make([]func(), len(stroke.targets))
+	for i, target := range stroke.targets {
+		after[i] = takeSnapshot(target)
+	}
+	c.History.Change(func() {
+		for i := len(stroke.before) - 1; i >= 0; i-- {
+			stroke.before[i]()
+		}
+	})
+	c.History.Update("Paint", func() {
+		for _, fn := range after {
+			fn()
+		}
+	}, stroke.targets...)
+}
+
+// change calls fn changing plots, the map is locked for other goroutines while fn runs
+func (c *MapCanvas) change(fn func()) {
+	if c.History != nil {
+		c.History.Change(fn)
+	} else {
+		fn()
+	}
+}
+
+func (c *MapCanvas) Scrolled(e *fyne.ScrollEvent) {
+	factor := float32(1.25)
+	if e.Scrolled.DY < 0 {
+		factor = 1 / factor
+	}
+	c.SetZoom(c.zoom*factor, e.Position)
+}
+
+// handleResult redraws the canvas and reports changed plots or error
+func (c *MapCanvas) handleResult(plots []*Plot, err error) {
+	if err != nil {
+		if c.OnError != nil {
+			c.OnError(err)
+		}
+		return
+	}
+	if len(plots) == 0 {
+		return
+	}
+
+	c.updateTiles(plots)
+	c.Refresh()
+	if c.OnChange != nil {
+		c.OnChange(plots)
+	}
+}
+
+// draw renders visible plots. Details (rivers, bonuses, routes) are drawn only if plots are large enough
+func (c *MapCanvas) draw(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	size := c.Size()
+	if size.Width <= 0 || c.Grid.Width == 0 {
+		return img
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	scale := float32(w) / size.Width
+	cell := c.zoom * scale
+
+	for py := 0; py < h; py++ {
+		for px := 0; px < w; px++ {
+			x, y := c.getPlotPosition(fyne.NewPos(float32(px)/scale, float32(py)/scale))
+			tx, ty, ok := c.Grid.normalize(x, y)
+			if !ok || ty*c.Grid.Width+tx >= len(c.tiles) {
+				continue
+			}
+			tile := c.tiles[ty*c.Grid.Width+tx]
+			if !tile.exists {
+				img.SetRGBA(px, py, emptyColor)
+				continue
+			}
+			col := tile.color
+
+			// Position inside the plot from 0 to 1, top left corner is 0,0
+			fx := (float32(px) - (c.offsetX*scale + float32(x)*cell)) / cell
+			fy := (float32(py) - (c.offsetY*scale + float32(c.Grid.Height-1-y)*cell)) / cell
+			if cell >= 6 {
+				switch {
+				case tile.river[0] && fx > 0.85, tile.river[1] && fy > 0.85:
+					col = riverColor
+				case tile.bonus && math.Abs(float64(fx-0.5)) < 0.15 && math.Abs(float64(fy-0.5)) < 0.15:
+					col = bonusColor
+				case tile.route && (math.Abs(float64(fx-0.5)) < 0.06 || math.Abs(float64(fy-0.5)) < 0.06):
+					col = routeColor
+				}
+			}
+			if c.Selection != nil && c.Selection.Contains(x, y) {
+				col = mixColors(col, selectionColor, 0.35)
+			}
+
+			img.SetRGBA(px, py, col)
+		}
+	}
+
+	return img
+}
+
+// reloadTiles updates looks of all plots of the grid
+func (c *MapCanvas) reloadTiles() {
+	tiles := make([]canvasTile, c.Grid.Width*c.Grid.Height)
+	registries := c.Data.Registries()
+	for i := range tiles {
+		if plot := c.Grid.GetPlot(i%c.Grid.Width, i/c.Grid.Width); plot != nil {
+			tiles[i] = getPlotTile(registries, plot)
+		}
+	}
+
+	c.mu.Lock()
+	c.tiles = tiles
+	c.mu.Unlock()
+}
+
+// updateTiles updates looks of changed plots
+func (c *MapCanvas) updateTiles(plots []*Plot) {
+	registries := c.Data.Registries()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, plot := range plots {
+		if i := int(plot.Y)*c.Grid.Width + int(plot.X); i < len(c.tiles) {
+			c.tiles[i] = getPlotTile(registries, plot)
+		}
+	}
+}
+
+// getPlotTile returns the look of the plot: base color and details drawn on large plots
+func getPlotTile(r *GameRegistries, p *Plot) canvasTile {
+	return canvasTile{
+		exists: true,
+		color:  getPlotColor(r, p),
+		river:  [2]bool{p.IsWOfRiver, p.IsNOfRiver},
+		bonus:  p.BonusType != "",
+		route:  p.RouteType != "",
+	}
+}
+
+// getPlotColor returns the base color of the plot: terrain color changed by plot type and feature
+func getPlotColor(r *GameRegistries, p *Plot) color.RGBA {
+	col, ok := terrainColors[p.TerrainType]
+	if !ok {
+		col = getTypeColor(p.TerrainType)
+		if terrain, known := r.TerrainInfos[p.TerrainType]; known && terrain.Water {
+			col = mixColors(col, terrainColors["TERRAIN_OCEAN"], 0.7)
+		}
+	}
+
+	switch p.PlotType {
+	case PlotTypePeak:
+		col = peakColor
+	case PlotTypeHills:
+		col = mixColors(col, color.RGBA{A: 255}, 0.25)
+	}
+	if len(p.FeatureType) > 0 {
+		col = mixColors(col, featureColor, 0.4)
+	}
+	return col
+}
+
+// getTypeColor returns a color generated from the type, so the same type always has the same color
+func getTypeColor(t string) color.RGBA {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(strings.ToUpper(t)))
+	sum := h.Sum32()
+	return color.RGBA{R: uint8(sum), G: uint8(sum >> 8), B: uint8(sum >> 16), A: 255}
+}
+
+// mixColors returns a color between a and b, weight is a share of b
+func mixColors(a, b color.RGBA, weight float64) color.RGBA {
+	mix := func(x, y uint8) uint8 {
+		return uint8(float64(x)*(1-weight) + float64(y)*weight)
+	}
+	return color.RGBA{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B), A: 255}
+}
+
+// GuiMapCanvas adds the canvas with its tools: mode, brush tool, value, variety and size
+func GuiMapCanvas(c *fyne.Container, mapCanvas *MapCanvas) {
+	status := widget.NewLabel("Scroll to zoom, drag in pan mode to move the map")
+	mapCanvas.OnChange = func(plots []*Plot) {
+		status.SetText(strconv.Itoa(len(plots)) + " plots changed")
+	}
+	mapCanvas.OnError = func(err error) {
+		status.SetText(err.Error())
+	}
+
+	modes := make([]string, 0, len(CanvasModes))
+	for _, mode := range CanvasModes {
+		modes = append(modes, string(mode))
+	}
+	modeGroup := widget.NewRadioGroup(modes, func(s string) { mapCanvas.Mode = CanvasMode(s) })
+	modeGroup.Horizontal = true
+	modeGroup.Required = true
+	modeGroup.Selected = string(mapCanvas.Mode)
+	c.Add(modeGroup)
+
+	// Values depend on the tool, so they are replaced when the tool changes
+	values := container.NewVBox()
+	showValues := func() {
+		values.RemoveAll()
+		registries := mapCanvas.Data.Registries()
+		GuiSelectEntry(values, "BrushValue", "Value", mapCanvas.Brush.Value, registries.GetBrushChoices(mapCanvas.Brush.Tool), func(s string) {
+			mapCanvas.Brush.Value = s
+		})
+		if mapCanvas.Brush.Tool == BrushFeature {
+			variety := widget.NewEntry()
+			variety.SetText(mapCanvas.Brush.Variety)
+			variety.OnChanged = func(s string) { mapCanvas.Brush.Variety = s }
+			values.Add(container.NewGridWithColumns(3, widget.NewLabel("Feature variety"), variety, widget.NewLabel("")))
+		}
+	}
+
+	tools := make([]Choice, 0, len(BrushTools))
+	for _, tool := range BrushTools {
+		tools = append(tools, Choice{Type: string(tool), Description: string(tool)})
+	}
+	GuiSelectEntry(c, "BrushTool", "Brush", string(mapCanvas.Brush.Tool), tools, func(s string) {
+		mapCanvas.Brush = Brush{Tool: BrushTool(s), Size: mapCanvas.Brush.Size}
+		showValues()
+	})
+	showValues()
+	c.Add(values)
+
+	sizes := []Choice{{Type: "1", Description: "1x1"}, {Type: "2", Description: "3x3"}, {Type: "3", Description: "5x5"}, {Type: "5", Description: "9x9"}}
+	GuiSelectEntry(c, "BrushSize", "Brush size", strconv.Itoa(mapCanvas.Brush.Size), sizes, func(s string) {
+		mapCanvas.Brush.Size = ToInt(s)
+	})
+
+	c.Add(container.NewHBox(
+		widget.NewButton("Fill selection", mapCanvas.FillSelection),
+		widget.NewButton("Clear selection", func() {
+			mapCanvas.Selection = nil
+			mapCanvas.Refresh()
+		}),
+		widget.NewButton("Zoom in", func() { mapCanvas.ZoomBy(1.5) }),
+		widget.NewButton("Zoom out", func() { mapCanvas.ZoomBy(1 / 1.5) }),
+		widget.NewButton("Reset view", mapCanvas.ResetView),
+	))
+	c.Add(status)
+	c.Add(mapCanvas)
+}
@@ -0,0 +1,40 @@
+package editor
+
+import (
+	"fyne.io/fyne/v2"
+	"testing"
+)
+
+func TestMapCanvasStroke(t *testing.T) {
+	m := newTestPaintMap(5, 3, false)
+	m.Plots = m.Plots[1:]
+	c := NewMapCanvas(m, NewGameData("", ""))
+	c.History = NewHistory()
+	c.Brush = Brush{Tool: BrushTerrain, Value: "TERRAIN_PLAINS", Size: 1}
+
+	// Plot 0,0 is missing and added by the stroke, rows start from the bottom
+	for _, x := range []float32{0, 1, 9, 17} {
+		c.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{Position: fyne.NewPos(x, 20)}})
+	}
+
+	// History is not locked between drag events
+	read := false
+	c.History.Read(func(int, bool) { read = true })
+	if !read || c.History.CanUndo() {
+		t.Fatal("Stroke must not be recorded before it ends")
+	}
+	if tile := c.tiles[0]; !tile.exists || tile.color != terrainColors["TERRAIN_PLAINS"] {
+		t.Errorf("Tile is not updated: %+v", tile)
+	}
+
+	c.DragEnd()
+	if c.History.GetUndoName() != "Paint" || len(m.Plots) != 15 || c.Grid.GetPlot(2, 0).TerrainType != "TERRAIN_PLAINS" {
+		t.Fatalf("Unexpected plots after stroke: %d", len(m.Plots))
+	}
+
+	c.History.Undo()
+	c.Reload()
+	if c.History.CanUndo() || len(m.Plots) != 14 || c.Grid.GetPlot(1, 0).TerrainType != "TERRAIN_GRASS" || c.tiles[0].exists {
+		t.Errorf("Stroke must be undone in one step: %d plots", len(m.Plots))
+	}
+}
@@ -2,8 +2,32 @@
 
 package editor
 
-import "errors"
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+)
 
-func RunElevated(exe string, argv []string) error {
+func RunElevated(exe string, argv []string, cwd string) error {
 	return errors.New("elevated execution is not supported on this platform")
 }
+
+// setCommandLine does nothing, command line is set on Windows only
+func setCommandLine(cmd *exec.Cmd, line string) {}
+
+// setProcessGroup starts the command in a new process group, so it can be killed with processes it starts
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// killProcessGroup kills the process group of the process started with setProcessGroup
+func killProcessGroup(process *os.Process) error {
+	if err := syscall.Kill(-process.Pid, syscall.SIGKILL); err != nil {
+		return process.Kill()
+	}
+	return nil
+}
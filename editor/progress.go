@@ -0,0 +1,199 @@
+package editor
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// Progress is a state of a long operation (loading game data, parsing or saving a map), reported to both interfaces
+type Progress struct {
+	// ID identifies the operation among running ones, it's used to cancel it (see CancelProgress)
+	ID int `json:"id"`
+	// Operation is a name of the whole operation, e.g. "Loading game data"
+	Operation string `json:"operation"`
+	// Phase is a name of the current step, e.g. "Parsing XML files"
+	Phase string `json:"phase"`
+	// Detail describes the current item of the phase (e.g. file path), may be empty
+	Detail string `json:"detail"`
+	Done   int    `json:"done"`
+	// Total is a number of items in the phase, 0 means the progress is indeterminate
+	Total int `json:"total"`
+	// Percent is a progress of the phase from 0 to 100, or -1 if it's indeterminate
+	Percent int `json:"percent"`
+	// Finished is true when the operation is over, Error is set if it failed or was cancelled
+	Finished bool   `json:"finished"`
+	Error    string `json:"error"`
+}
+
+// ProgressReporter sends progress of one operation to listeners. All methods of nil reporter do nothing,
+// so operations may report progress without checking if anybody listens (see ProgressFrom)
+type ProgressReporter struct {
+	mu        sync.Mutex
+	state     Progress
+	listeners []func(Progress)
+	cancel    context.CancelFunc
+}
+
+// progressListener is a listener of all operations (see SubscribeProgress)
+type progressListener struct {
+	id int
+	fn func(Progress)
+}
+
+// progressHub keeps running operations and listeners of all operations, IDs are shared by operations and listeners
+var progressHub = struct {
+	sync.Mutex
+	nextID    int
+	running   map[int]*ProgressReporter
+	listeners []progressListener
+}{running: make(map[int]*ProgressReporter)}
+
+type progressKey struct{}
+
+// SubscribeProgress adds a listener of all operations started by StartProgress, e.g. a progress bar.
+// The listener is called from the goroutine of the operation and must not block. Returns a function to unsubscribe
+func SubscribeProgress(listener func(Progress)) func() {
+	progressHub.Lock()
+	defer progressHub.Unlock()
+	id := progressHub.nextID
+	progressHub.nextID++
+	progressHub.listeners = append(progressHub.listeners, progressListener{id: id, fn: listener})
+
+	return func() {
+		progressHub.Lock()
+		defer progressHub.Unlock()
+		progressHub.listeners = slices.DeleteFunc(progressHub.listeners, func(l progressListener) bool { return l.id == id })
+	}
+}
+
+// StartProgress starts reporting of the operation to subscribed listeners. The returned context carries the reporter
+// (see ProgressFrom) and is cancelled by CancelProgress. Finish must be called when the operation is over
+func StartProgress(parent context.Context, operation string) (context.Context, *ProgressReporter) {
+	ctx, cancel := context.WithCancel(parent)
+
+	progressHub.Lock()
+	id := progressHub.nextID
+	progressHub.nextID++
+	r := &ProgressReporter{state: Progress{ID: id, Operation: operation, Percent: -1}, cancel: cancel}
+	for _, listener := range progressHub.listeners {
+		r.listeners = append(r.listeners, listener.fn)
+	}
+	progressHub.running[id] = r
+	progressHub.Unlock()
+
+	r.notify()
+	return context.WithValue(ctx, progressKey{}, r), r
+}
+
+// CancelProgress cancels the running operation, returns false if it's already finished
+func CancelProgress(id int) bool {
+	progressHub.Lock()
+	r, ok := progressHub.running[id]
+	progressHub.Unlock()
+	if ok {
+		r.cancel()
+	}
+	return ok
+}
+
+// GetRunningProgress returns states of running operations in the order they are started
+func GetRunningProgress() []Progress {
+	progressHub.Lock()
+	running := make([]*ProgressReporter, 0, len(progressHub.running))
+	for _, r := range progressHub.running {
+		running = append(running, r)
+	}
+	progressHub.Unlock()
+
+	states := make([]Progress, 0, len(running))
+	for _, r := range running {
+		r.mu.Lock()
+		states = append(states, r.state)
+		r.mu.Unlock()
+	}
+	slices.SortFunc(states, func(a, b Progress) int { return a.ID - b.ID })
+	return states
+}
+
+// ProgressFrom returns the reporter of the context, or nil if the operation is not reported
+func ProgressFrom(ctx context.Context) *ProgressReporter {
+	r, _ := ctx.Value(progressKey{}).(*ProgressReporter)
+	return r
+}
+
+// Phase starts the next step of the operation, total is a number of its items (0 if unknown)
+func (r *ProgressReporter) Phase(name string, total int) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	r.state.Phase, r.state.Detail, r.state.Done, r.state.Total = name, "", 0, total
+	r.mu.Unlock()
+	r.notify()
+}
+
+// Step marks one more item of the phase as done
+func (r *ProgressReporter) Step(detail string) {
+	r.SetDone(-1, detail)
+}
+
+// SetDone sets a number of done items of the phase (e.g. bytes read), -1 means one more item.
+// Listeners are notified only when percent changes, so reporting every item is cheap
+func (r *ProgressReporter) SetDone(done int, detail string) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	if done < 0 {
+		done = r.state.Done + 1
+	}
+	percent := r.state.Percent
+	r.state.Done, r.state.Detail = done, detail
+	changed := r.state.Total == 0 || getPercent(done, r.state.Total) != percent
+	r.mu.Unlock()
+
+	if changed {
+		r.notify()
+	}
+}
+
+// Finish reports the end of the operation, err is nil on success
+func (r *ProgressReporter) Finish(err error) {
+	if r == nil {
+		return
+	}
+
+	progressHub.Lock()
+	delete(progressHub.running, r.state.ID)
+	progressHub.Unlock()
+
+	r.mu.Lock()
+	r.state.Finished = true
+	if err != nil {
+		r.state.Error = err.Error()
+	}
+	r.mu.Unlock()
+	r.notify()
+	r.cancel()
+}
+
+// notify sends the current state to listeners
+func (r *ProgressReporter) notify() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.state.Percent = getPercent(r.state.Done, r.state.Total)
+	for _, listener := range r.listeners {
+		listener(r.state)
+	}
+}
+
+// getPercent returns done items in percent of total, or -1 if total is unknown
+func getPercent(done, total int) int {
+	if total <= 0 {
+		return -1
+	}
+	return min(done*100/total, 100)
+}
@@ -0,0 +1,84 @@
+package editor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestProgressReporter(t *testing.T) {
+	var states []Progress
+	unsubscribe := SubscribeProgress(func(p Progress) { states = append(states, p) })
+	ctx, reporter := StartProgress(context.Background(), "Test")
+	unsubscribe()
+
+	if ProgressFrom(ctx) != reporter || ProgressFrom(context.Background()) != nil {
+		t.Fatal("Reporter must be taken from the context")
+	}
+	if running := GetRunningProgress(); len(running) != 1 || running[0].ID != states[0].ID {
+		t.Errorf("Unexpected running operations: %+v", running)
+	}
+
+	// Only changes of percent are reported
+	reporter.Phase("Items", 300)
+	for i := 0; i < 300; i++ {
+		reporter.Step("item")
+	}
+	last := states[len(states)-1]
+	if len(states) != 102 || last.Phase != "Items" || last.Done != 300 || last.Percent != 100 {
+		t.Errorf("Unexpected progress: %d states, last %+v", len(states), last)
+	}
+
+	if !CancelProgress(last.ID) || ctx.Err() == nil {
+		t.Error("Operation must be cancelled")
+	}
+	reporter.Finish(ctx.Err())
+	last = states[len(states)-1]
+	if !last.Finished || last.Error == "" || CancelProgress(last.ID) || len(GetRunningProgress()) != 0 {
+		t.Errorf("Unexpected finished state: %+v", last)
+	}
+
+	// Nil reporter ignores all calls
+	var r *ProgressReporter
+	r.Phase("Nothing", 1)
+	r.Step("")
+	r.Finish(nil)
+}
+
+func TestProgressCancellation(t *testing.T) {
+	m := newTestSaveMap("Cancelled")
+	for i := 0; i < 2*progressInterval; i++ {
+		m.Plots = append(m.Plots, &Plot{TerrainType: "TERRAIN_GRASS"})
+	}
+	data := m.ToWbFormat()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := m.ToWbFormatContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Generation must be cancelled: %v", err)
+	}
+	if _, err := ParseWbMapContext(ctx, strings.NewReader(string(data)), int64(len(data))); !errors.Is(err, context.Canceled) {
+		t.Errorf("Parsing must be cancelled: %v", err)
+	}
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{"Assets/XML/Technologies/CIV4TechInfos.xml": testTechXml})
+	useTestXmlCache(t, "")
+	if _, err := LoadGameRegistries(ctx, root, ""); !errors.Is(err, context.Canceled) {
+		t.Errorf("Loading must be cancelled: %v", err)
+	}
+
+	var parsed Progress
+	unsubscribe := SubscribeProgress(func(p Progress) {
+		if p.Phase == "Parsing map" {
+			parsed = p
+		}
+	})
+	defer unsubscribe()
+	ctx, reporter := StartProgress(context.Background(), "Parsing")
+	_, err := ParseWbMapContext(ctx, strings.NewReader(string(data)), int64(len(data)))
+	reporter.Finish(err)
+	if err != nil || parsed.Percent <= 0 || parsed.Total != len(data) {
+		t.Errorf("Unexpected parsing progress: %+v, %v", parsed, err)
+	}
+}
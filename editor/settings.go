@@ -7,12 +7,26 @@ import (
 
 const mainConfigFile = "config.json"
 
+// MaxRecentFiles is a number of files kept in Config.RecentFiles
+const MaxRecentFiles = 10
+
 type Config struct {
 	GameDir  string `json:"game_dir"`
 	Mod      string `json:"mod"`
 	AutoSave bool   `json:"auto_save"`
+	// Language is a game text language (see GameLanguages). Empty means DefaultLanguage
+	Language string `json:"language"`
+	// RecentFiles are recently opened maps and workspaces, the latest first
+	RecentFiles []string `json:"recent_files"`
+	// LaunchProfiles are ways to start the game (see GetLaunchProfiles)
+	LaunchProfiles []LaunchProfile `json:"launch_profiles"`
+	// LaunchProfile is a name of the selected launch profile, empty means the first one
+	LaunchProfile string `json:"launch_profile"`
 }
 
+// findGameInstalls looks for installations to select the game directory of the default configuration
+var findGameInstalls = FindGameInstalls
+
 // GetConfig returns the current configuration. If the configuration file does not exist, it will return the default configuration.
 func GetConfig() (Config, bool) {
 	data, err := os.ReadFile(mainConfigFile)
@@ -50,11 +64,26 @@ func GetDefaultConfig() Config {
 		AutoSave: true,
 	}
 
-	if config.GameDir == "" {
-		if _, err := os.Stat(steamDefaultGameDir); err == nil {
-			config.GameDir = steamDefaultGameDir
+	// The first found installation with Beyond the Sword is used, on Linux its launch profile is detected by game directory
+	for _, install := range findGameInstalls() {
+		if install.Supported {
+			config.GameDir = install.Dir
+			break
 		}
 	}
 
 	return config
 }
+
+// AddRecentFile moves the file to the top of recent files, only MaxRecentFiles latest files are kept
+func (c *Config) AddRecentFile(path string) {
+	c.RecentFiles = append([]string{path}, RemoveFromSlice(c.RecentFiles, path)...)
+	if len(c.RecentFiles) > MaxRecentFiles {
+		c.RecentFiles = c.RecentFiles[:MaxRecentFiles]
+	}
+}
+
+// RemoveRecentFile removes the file from recent files, e.g. if it doesn't exist anymore
+func (c *Config) RemoveRecentFile(path string) {
+	c.RecentFiles = RemoveFromSlice(c.RecentFiles, path)
+}
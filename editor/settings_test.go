@@ -1,11 +1,41 @@
 package editor
 
-import "testing"
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
 
 func TestGetDefaultConfig(t *testing.T) {
+	var installs []GameInstall
+	t.Cleanup(func() { findGameInstalls = FindGameInstalls })
+	findGameInstalls = func() []GameInstall { return installs }
+
 	testConf := &Config{AutoSave: true}
 	config := GetDefaultConfig()
-	if config != *testConf {
+	if !reflect.DeepEqual(config, *testConf) {
 		t.Error("GetDefaultConfig() did not return the expected default configuration")
 	}
+
+	// The first supported installation is selected
+	installs = []GameInstall{{Dir: "vanilla"}, {Dir: "bts", Supported: true}, {Dir: "other", Supported: true}}
+	if config = GetDefaultConfig(); config.GameDir != "bts" {
+		t.Errorf("Unexpected game directory: %q", config.GameDir)
+	}
+}
+
+func TestRecentFiles(t *testing.T) {
+	config := &Config{}
+	for i := 0; i < MaxRecentFiles+2; i++ {
+		config.AddRecentFile("map" + strconv.Itoa(i))
+	}
+	config.AddRecentFile("map5")
+	if len(config.RecentFiles) != MaxRecentFiles || config.RecentFiles[0] != "map5" || config.RecentFiles[1] != "map11" {
+		t.Errorf("Unexpected recent files: %v", config.RecentFiles)
+	}
+
+	config.RemoveRecentFile("map11")
+	if IsInSlice(config.RecentFiles, "map11") || len(config.RecentFiles) != MaxRecentFiles-1 {
+		t.Errorf("File is not removed: %v", config.RecentFiles)
+	}
 }
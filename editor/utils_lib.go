@@ -1,7 +1,7 @@
 package editor
 
 import (
-	"bufio"
+	"bytes"
 	"encoding/xml"
 	"golang.org/x/net/html/charset"
 	"os"
@@ -13,38 +13,31 @@ import (
 // WaitCloseChan is a channel that will be closed when the UI window is closed
 type WaitCloseChan <-chan bool
 
-// xmlTypeRegex is used to determine the XML file type by reading the first line
+// xmlTypeRegex is used to determine the XML file type by finding the first tag
 var xmlTypeRegex = regexp.MustCompile("<([a-zA-Z0-9]+)[ |>]")
 
-// ParseXMLFromFile opens a file and returns an XML decoder and the Civilization XML file type
+// ParseXMLFromFile reads a file and returns an XML decoder and the Civilization XML file type
 func ParseXMLFromFile(path string) (decoder *xml.Decoder, tag CivXmlType, err error) {
-	var xmlFile *os.File
-	xmlFile, err = os.Open(path)
+	var data []byte
+	data, err = os.ReadFile(path)
 	if err != nil {
-		ConsoleWrite("Error opening file: %s", err)
+		Logger.Error("Cannot open file", "path", path, "error", err)
 		return
 	}
 
-	// Read line-by-line to find the XML file type. It's needed to determine which struct to use
-	// No need to parse the whole file, first line is enough
-	sc := bufio.NewScanner(xmlFile)
-	for sc.Scan() {
-		matches := xmlTypeRegex.FindStringSubmatch(sc.Text())
-		if len(matches) > 1 {
-			tag = CivXmlType(matches[1])
-			break
-		}
-	}
+	decoder, tag = ParseXMLFromBytes(data)
+	return
+}
 
-	xmlFile.Close()
-	// Reopen file to parse it from the beginning
-	xmlFile, err = os.Open(path)
-	if err != nil {
-		ConsoleWrite("Error reopening file: %s", err)
-		return
+// ParseXMLFromBytes returns an XML decoder and the Civilization XML file type for the file contents.
+// File type is the first tag found (it's needed to determine which struct to use), no need to parse the whole file.
+func ParseXMLFromBytes(data []byte) (decoder *xml.Decoder, tag CivXmlType) {
+	matches := xmlTypeRegex.FindSubmatch(data)
+	if len(matches) > 1 {
+		tag = CivXmlType(matches[1])
 	}
 
-	decoder = xml.NewDecoder(xmlFile)
+	decoder = xml.NewDecoder(bytes.NewReader(data))
 	decoder.CharsetReader = charset.NewReaderLabel // needed for non-UTF-8 files, sometimes it's something like "iso-8859-1"
 	decoder.Strict = false                         // a bit faster and safer because some mod files are not strictly valid XML
 	return
@@ -61,8 +54,29 @@ func ToUint(s string) uint {
 	return uint(ToInt(s))
 }
 
-// IsInSlice checks if a string is in a slice
-func IsInSlice(slice []string, value string) bool {
+// ToFloat converts a string to a float
+func ToFloat(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+// ToBool converts an XML boolean ("0"/"1") to a boolean
+func ToBool(s string) bool {
+	return s == "1"
+}
+
+// ToIntSlice converts a slice of strings to a slice of integers (e.g. list of yields)
+func ToIntSlice(slice []string) []int {
+	result := make([]int, 0, len(slice))
+	for _, s := range slice {
+		result = append(result, ToInt(s))
+	}
+
+	return result
+}
+
+// IsInSlice checks if a value is in a slice
+func IsInSlice[T comparable](slice []T, value T) bool {
 	for _, v := range slice {
 		if v == value {
 			return true
@@ -72,8 +86,8 @@ func IsInSlice(slice []string, value string) bool {
 	return false
 }
 
-// SwitchInSlice adds or removes a string from a slice depending on the "add" parameter
-func SwitchInSlice(add bool, slice []string, value string) []string {
+// SwitchInSlice adds or removes a value from a slice depending on the "add" parameter
+func SwitchInSlice[T comparable](add bool, slice []T, value T) []T {
 	if add {
 		return AddToSlice(slice, value)
 	} else {
@@ -81,8 +95,8 @@ func SwitchInSlice(add bool, slice []string, value string) []string {
 	}
 }
 
-// AddToSlice adds a string to a slice if it's not already there
-func AddToSlice(slice []string, value string) []string {
+// AddToSlice adds a value to a slice if it's not already there
+func AddToSlice[T comparable](slice []T, value T) []T {
 	for _, v := range slice {
 		if v == value {
 			return slice
@@ -92,8 +106,8 @@ func AddToSlice(slice []string, value string) []string {
 	return append(slice, value)
 }
 
-// RemoveFromSlice removes a string from a slice
-func RemoveFromSlice(slice []string, value string) []string {
+// RemoveFromSlice removes a value from a slice
+func RemoveFromSlice[T comparable](slice []T, value T) []T {
 	for i, v := range slice {
 		if v == value {
 			return append(slice[:i], slice[i+1:]...)
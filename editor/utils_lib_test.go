@@ -22,6 +22,34 @@ func TestToUint(t *testing.T) {
 	}
 }
 
+func TestToFloat(t *testing.T) {
+	if ToFloat("0.5") != 0.5 {
+		t.Error("ToFloat failed")
+	}
+
+	if ToFloat("bad") != 0 {
+		t.Error("ToFloat failed")
+	}
+}
+
+func TestToBool(t *testing.T) {
+	if !ToBool("1") {
+		t.Error("ToBool failed")
+	}
+
+	if ToBool("0") || ToBool("") {
+		t.Error("ToBool failed")
+	}
+}
+
+func TestToIntSlice(t *testing.T) {
+	ints := ToIntSlice([]string{"1", "2", ""})
+
+	if len(ints) != 3 || ints[0] != 1 || ints[1] != 2 || ints[2] != 0 {
+		t.Error("ToIntSlice failed")
+	}
+}
+
 func TestIsInSlice(t *testing.T) {
 	slice := []string{"a", "b", "c"}
 
@@ -0,0 +1,378 @@
+package editor
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AutosaveInterval is how often changed map is written to the recovery directory (see Config.AutoSave)
+const AutosaveInterval = time.Minute
+
+// MaxBackups is a number of backups kept for every map file, the oldest ones are removed
+const MaxBackups = 10
+
+// RecoveryDir is a directory for autosaved maps, they are offered for recovery on next launch. Empty value disables autosave
+var RecoveryDir = defaultStudioDir("recovery")
+
+// BackupDir is a directory for map backups made before every save. Empty value disables backups
+var BackupDir = defaultStudioDir("backups")
+
+// recoveryExt is an extension of autosaved maps which were never saved to a file
+const recoveryExt = ".CivBeyondSwordWBSave"
+
+// recoveryMeta is written next to the autosaved map
+type recoveryMeta struct {
+	FilePath string    `json:"file_path"`
+	Key      string    `json:"key"`
+	SavedAt  time.Time `json:"saved_at"`
+}
+
+// Recovery is an autosaved map which was not saved by user (e.g. after a crash)
+type Recovery struct {
+	// FilePath is the path of the original map file, empty if the map was never saved
+	FilePath string
+	// Key names the recovery files, it's derived from FilePath or generated for maps never saved (see Autosaver)
+	Key     string
+	SavedAt time.Time
+	// Path is the path of the autosaved map
+	Path string
+}
+
+// defaultStudioDir returns a directory inside user config directory
+func defaultStudioDir(name string) string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "civ4-studio", name)
+}
+
+// getFileKey returns a short unique key of the map file path, used to name its backups and recovery files
+func getFileKey(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	hash := sha1.Sum([]byte(path))
+	return hex.EncodeToString(hash[:])[:12]
+}
+
+// newRecoveryKey returns a random key of recovery files for a map which was never saved
+func newRecoveryKey() string {
+	random := make([]byte, 6)
+	_, _ = rand.Read(random)
+	return "unsaved-" + hex.EncodeToString(random)
+}
+
+// WriteFileAtomic writes data to a temporary file in the same directory and renames it to path,
+// so the file is never left half-written
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), perm)
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// BackupFile copies the file to the backup directory with a timestamp, only MaxBackups newest backups of the file are kept.
+// Returns the backup path, or empty string if the file doesn't exist or backups are disabled
+func BackupFile(path string) (string, error) {
+	if BackupDir == "" {
+		return "", nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	if err = os.MkdirAll(BackupDir, 0755); err != nil {
+		return "", err
+	}
+
+	ext := filepath.Ext(path)
+	prefix := strings.TrimSuffix(filepath.Base(path), ext) + "-" + getFileKey(path) + "-"
+	backup := filepath.Join(BackupDir, prefix+time.Now().Format("20060102-150405.000000000")+ext)
+	if err = WriteFileAtomic(backup, data, 0644); err != nil {
+		return "", err
+	}
+
+	// Timestamps are sorted as strings, the oldest backups are first. Names are not used as glob patterns,
+	// as map names often contain brackets (e.g. "[BTS] Earth")
+	entries, err := os.ReadDir(BackupDir)
+	if err != nil {
+		return backup, err
+	}
+	var backups []string
+	for _, entry := range entries {
+		if name := entry.Name(); !entry.IsDir() && strings.HasPrefix(name, prefix) && strings.HasSuffix(name, ext) {
+			backups = append(backups, filepath.Join(BackupDir, name))
+		}
+	}
+	sort.Strings(backups)
+	for len(backups) > MaxBackups {
+		_ = os.Remove(backups[0])
+		backups = backups[1:]
+	}
+	return backup, nil
+}
+
+// SaveWbMap makes a backup of the existing file and writes the map atomically. Recovery of the file is removed.
+// Progress is reported to the reporter of the context, the file is not changed if the context is cancelled
+func SaveWbMap(ctx context.Context, path string, m *WbMap) error {
+	progress := ProgressFrom(ctx)
+	progress.Phase("Making backup", 0)
+	if _, err := BackupFile(path); err != nil {
+		Logger.Warn("Backup failed", "path", path, "error", err)
+	}
+
+	data, err := m.ToWbFormatContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	progress.Phase("Writing file", 0)
+	if err = WriteFileAtomic(path, data, 0644); err != nil {
+		return err
+	}
+
+	RemoveRecovery(path)
+	return nil
+}
+
+// getRecoveryPaths returns paths of the autosaved map and its metadata by the recovery key
+func getRecoveryPaths(key, filePath string) (string, string) {
+	ext := filepath.Ext(filePath)
+	if ext == "" {
+		ext = recoveryExt
+	}
+	return filepath.Join(RecoveryDir, key+ext), filepath.Join(RecoveryDir, key+".json")
+}
+
+// WriteRecovery saves the map data to the recovery directory
+func WriteRecovery(filePath string, data []byte) error {
+	return writeRecovery(getFileKey(filePath), filePath, data)
+}
+
+func writeRecovery(key, filePath string, data []byte) error {
+	if RecoveryDir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(RecoveryDir, 0755); err != nil {
+		return err
+	}
+
+	mapPath, metaPath := getRecoveryPaths(key, filePath)
+	if err := WriteFileAtomic(mapPath, data, 0644); err != nil {
+		return err
+	}
+
+	meta, err := json.Marshal(recoveryMeta{FilePath: filePath, Key: key, SavedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(metaPath, meta, 0644)
+}
+
+// RemoveRecovery removes autosaved map of the file, e.g. after it's saved or changes are discarded
+func RemoveRecovery(filePath string) {
+	removeRecovery(getFileKey(filePath), filePath)
+}
+
+func removeRecovery(key, filePath string) {
+	if RecoveryDir == "" {
+		return
+	}
+
+	mapPath, metaPath := getRecoveryPaths(key, filePath)
+	_ = os.Remove(metaPath)
+	_ = os.Remove(mapPath)
+}
+
+// ListRecoveries returns autosaved maps, the newest first
+func ListRecoveries() []Recovery {
+	if RecoveryDir == "" {
+		return nil
+	}
+
+	metas, _ := filepath.Glob(filepath.Join(RecoveryDir, "*.json"))
+	recoveries := make([]Recovery, 0, len(metas))
+	for _, metaPath := range metas {
+		data, err := os.ReadFile(metaPath)
+		if err != nil {
+			continue
+		}
+		meta := recoveryMeta{}
+		if err = json.Unmarshal(data, &meta); err != nil || (meta.FilePath == "" && meta.Key == "") {
+			continue
+		}
+		if meta.Key == "" {
+			meta.Key = getFileKey(meta.FilePath)
+		}
+
+		mapPath, _ := getRecoveryPaths(meta.Key, meta.FilePath)
+		if _, err = os.Stat(mapPath); err != nil {
+			continue
+		}
+		recoveries = append(recoveries, Recovery{FilePath: meta.FilePath, Key: meta.Key, SavedAt: meta.SavedAt, Path: mapPath})
+	}
+
+	sort.Slice(recoveries, func(i, j int) bool {
+		return recoveries[i].SavedAt.After(recoveries[j].SavedAt)
+	})
+	return recoveries
+}
+
+// Load parses the autosaved map
+func (r Recovery) Load() (*WbMap, error) {
+	file, err := os.Open(r.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseWbMap(file)
+}
+
+// Remove removes the autosaved map, e.g. if user refuses to recover it
+func (r Recovery) Remove() {
+	removeRecovery(r.Key, r.FilePath)
+}
+
+// Autosaver writes the map to the recovery directory when it's changed, the map is read under History lock
+type Autosaver struct {
+	// OnError is called from the autosave goroutine if the map cannot be written
+	OnError func(err error)
+
+	mu       sync.Mutex
+	filePath string
+	// key names recovery files, it's generated for maps never saved to a file (e.g. new maps)
+	key     string
+	wbMap   *WbMap
+	history *History
+	version int
+	stop    chan struct{}
+}
+
+// SetMap changes the autosaved map, e.g. when another file is opened. Empty path means a map never saved
+func (a *Autosaver) SetMap(filePath string, m *WbMap, h *History) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.filePath, a.wbMap, a.history, a.version = filePath, m, h, -1
+	if filePath != "" {
+		a.key = getFileKey(filePath)
+	} else {
+		a.key = newRecoveryKey()
+	}
+}
+
+// UseRecovery makes autosave overwrite the recovery the map is loaded from, so a recovered map never saved
+// doesn't leave its old recovery
+func (a *Autosaver) UseRecovery(r Recovery) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if r.Key != "" && a.filePath == r.FilePath {
+		a.key = r.Key
+	}
+}
+
+// RemoveRecovery removes the autosaved map, e.g. when it's closed without saving
+func (a *Autosaver) RemoveRecovery() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.key != "" {
+		removeRecovery(a.key, a.filePath)
+	}
+}
+
+// Start runs autosave every interval until Stop is called
+func (a *Autosaver) Start(interval time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.stop != nil {
+		return
+	}
+
+	a.stop = make(chan struct{})
+	go func(stop chan struct{}) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := a.Save(); err != nil && a.OnError != nil {
+					a.OnError(err)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}(a.stop)
+}
+
+// Stop stops autosave started by Start
+func (a *Autosaver) Stop() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.stop != nil {
+		close(a.stop)
+		a.stop = nil
+	}
+}
+
+// Save writes the map to the recovery directory if it's changed since the last autosave.
+// Recovery is removed if the map has no unsaved changes
+func (a *Autosaver) Save() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.wbMap == nil || a.history == nil {
+		return nil
+	}
+
+	var data []byte
+	changed, dirty := false, false
+	a.history.Read(func(version int, isDirty bool) {
+		changed, dirty = version != a.version, isDirty
+		if changed && dirty {
+			data = a.wbMap.ToWbFormat()
+		}
+		a.version = version
+	})
+
+	if !changed {
+		return nil
+	}
+	if !dirty {
+		removeRecovery(a.key, a.filePath)
+		return nil
+	}
+	return writeRecovery(a.key, a.filePath, data)
+}
@@ -0,0 +1,141 @@
+package editor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// useTestStudioDirs makes backup and recovery directories temporary for the test
+func useTestStudioDirs(t *testing.T) {
+	backupDir, recoveryDir := BackupDir, RecoveryDir
+	BackupDir, RecoveryDir = filepath.Join(t.TempDir(), "backups"), filepath.Join(t.TempDir(), "recovery")
+	t.Cleanup(func() {
+		BackupDir, RecoveryDir = backupDir, recoveryDir
+	})
+}
+
+func newTestSaveMap(description string) *WbMap {
+	m := newTestPaintMap(3, 2, false)
+	m.Version, m.Game = 1, &Game{Description: description}
+	return m
+}
+
+func TestSaveWbMapBackups(t *testing.T) {
+	useTestStudioDirs(t)
+	path := filepath.Join(t.TempDir(), "test.CivBeyondSwordWBSave")
+	m := newTestSaveMap("First")
+
+	// The first save has nothing to back up
+	if err := SaveWbMap(context.Background(), path, m); err != nil {
+		t.Fatal(err)
+	}
+	if backups, _ := filepath.Glob(filepath.Join(BackupDir, "*")); len(backups) != 0 {
+		t.Errorf("Unexpected backups: %v", backups)
+	}
+
+	for i := 0; i < MaxBackups+2; i++ {
+		if err := SaveWbMap(context.Background(), path, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	backups, _ := filepath.Glob(filepath.Join(BackupDir, "test-*.CivBeyondSwordWBSave"))
+	if len(backups) != MaxBackups {
+		t.Errorf("Expected %d backups, got %d", MaxBackups, len(backups))
+	}
+	if files, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*")); len(files) != 1 {
+		t.Errorf("Temporary files are left: %v", files)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != string(m.ToWbFormat()) {
+		t.Errorf("Unexpected saved map: %v", err)
+	}
+}
+
+func TestAutosaveRecovery(t *testing.T) {
+	useTestStudioDirs(t)
+	path := filepath.Join(t.TempDir(), "test.CivBeyondSwordWBSave")
+	m := newTestSaveMap("Saved")
+	h := NewHistory()
+	a := &Autosaver{}
+	a.SetMap(path, m, h)
+
+	if err := a.Save(); err != nil || len(ListRecoveries()) != 0 {
+		t.Fatalf("Unchanged map must not be autosaved: %v", err)
+	}
+
+	Set(h, "Description", m.Game, &m.Game.Description)("Unsaved")
+	if err := a.Save(); err != nil {
+		t.Fatal(err)
+	}
+	recoveries := ListRecoveries()
+	if len(recoveries) != 1 || recoveries[0].FilePath != path {
+		t.Fatalf("Unexpected recoveries: %+v", recoveries)
+	}
+	recovered, err := recoveries[0].Load()
+	if err != nil || recovered.Game.Description != "Unsaved" {
+		t.Errorf("Unexpected recovered map: %v", err)
+	}
+
+	// Saving removes recovery, autosave doesn't write it again until the next change
+	if err = SaveWbMap(context.Background(), path, m); err != nil {
+		t.Fatal(err)
+	}
+	h.MarkSaved()
+	if err = a.Save(); err != nil || len(ListRecoveries()) != 0 {
+		t.Errorf("Recovery must be removed after save: %v", err)
+	}
+}
+
+func TestBackupBracketName(t *testing.T) {
+	useTestStudioDirs(t)
+	path := filepath.Join(t.TempDir(), "[BTS] Earth.CivBeyondSwordWBSave")
+	m := newTestSaveMap("Earth")
+
+	// Brackets are not a glob pattern, so old backups are removed
+	for i := 0; i < MaxBackups+3; i++ {
+		if err := SaveWbMap(context.Background(), path, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if backups, _ := os.ReadDir(BackupDir); len(backups) != MaxBackups {
+		t.Errorf("Expected %d backups, got %d", MaxBackups, len(backups))
+	}
+}
+
+func TestAutosaveNewMap(t *testing.T) {
+	useTestStudioDirs(t)
+	m := newTestSaveMap("New")
+	h := NewHistory()
+	h.MarkUnsaved()
+	a := &Autosaver{}
+	a.SetMap("", m, h)
+
+	// Map never saved to a file gets its own recovery
+	if err := a.Save(); err != nil {
+		t.Fatal(err)
+	}
+	recoveries := ListRecoveries()
+	if len(recoveries) != 1 || recoveries[0].FilePath != "" || recoveries[0].Key == "" {
+		t.Fatalf("Unexpected recoveries: %+v", recoveries)
+	}
+	if recovered, err := recoveries[0].Load(); err != nil || recovered.Game.Description != "New" {
+		t.Errorf("Unexpected recovered map: %v", err)
+	}
+
+	// Recovered map keeps writing the same recovery
+	other := &Autosaver{}
+	other.SetMap("", m, h)
+	other.UseRecovery(recoveries[0])
+	Set(h, "Description", m.Game, &m.Game.Description)("Recovered")
+	if err := other.Save(); err != nil || len(ListRecoveries()) != 1 {
+		t.Errorf("Unexpected recoveries after save: %v, %+v", err, ListRecoveries())
+	}
+
+	other.RemoveRecovery()
+	if len(ListRecoveries()) != 0 {
+		t.Error("Recovery must be removed")
+	}
+}
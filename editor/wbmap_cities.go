@@ -0,0 +1,175 @@
+package editor
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ProductionKind is a kind of city production, the city builds only one thing at game start
+type ProductionKind string
+
+const (
+	ProductionUnit     ProductionKind = "Unit"
+	ProductionBuilding ProductionKind = "Building"
+	ProductionProject  ProductionKind = "Project"
+	ProductionProcess  ProductionKind = "Process"
+)
+
+// ProductionKinds contains all production kinds in the order they are shown in the editor
+var ProductionKinds = []ProductionKind{ProductionUnit, ProductionBuilding, ProductionProject, ProductionProcess}
+
+// ProcessTypes are processes of the base game (see CIV4ProcessInfo.xml), process infos are not loaded
+var ProcessTypes = []string{"PROCESS_RESEARCH", "PROCESS_WEALTH", "PROCESS_CULTURE"}
+
+// GetProduction returns what the city builds at game start, the first set production is used (as in the game)
+func (c *City) GetProduction() (ProductionKind, string) {
+	switch {
+	case c.ProductionUnit != "":
+		return ProductionUnit, c.ProductionUnit
+	case c.ProductionBuilding != "":
+		return ProductionBuilding, c.ProductionBuilding
+	case c.ProductionProject != "":
+		return ProductionProject, c.ProductionProject
+	case c.ProductionProcess != "":
+		return ProductionProcess, c.ProductionProcess
+	}
+	return "", ""
+}
+
+// SetProduction sets what the city builds at game start, other production fields are cleared
+func (c *City) SetProduction(kind ProductionKind, value string) {
+	c.ProductionUnit, c.ProductionBuilding, c.ProductionProject, c.ProductionProcess = "", "", "", ""
+	switch kind {
+	case ProductionUnit:
+		c.ProductionUnit = value
+	case ProductionBuilding:
+		c.ProductionBuilding = value
+	case ProductionProject:
+		c.ProductionProject = value
+	case ProductionProcess:
+		c.ProductionProcess = value
+	}
+}
+
+// SetReligion adds or removes the religion, holy city of the removed religion is removed too
+func (c *City) SetReligion(religion string, value bool) {
+	c.ReligionType = SwitchInSlice(value, c.ReligionType, religion)
+	if !value {
+		c.HolyCityReligionType = RemoveFromSlice(c.HolyCityReligionType, religion)
+	}
+}
+
+// SetCulture sets culture of the player in the city, zero culture removes the value
+func (c *City) SetCulture(player uint, culture uint64) {
+	if culture == 0 {
+		delete(c.PlayerCulture, player)
+		return
+	}
+
+	if c.PlayerCulture == nil {
+		c.PlayerCulture = make(map[uint]uint64)
+	}
+	c.PlayerCulture[player] = culture
+}
+
+// GetCityPlots returns plots with cities
+func (m *WbMap) GetCityPlots() []*Plot {
+	var plots []*Plot
+	for _, plot := range m.Plots {
+		if len(plot.Cities) > 0 {
+			plots = append(plots, plot)
+		}
+	}
+	return plots
+}
+
+// SetHolyCity makes the city a holy city of the religion, the religion is added to the city.
+// There is only one holy city of every religion, so it's removed from other cities
+func (m *WbMap) SetHolyCity(city *City, religion string, value bool) {
+	if value {
+		for _, plot := range m.GetCityPlots() {
+			for _, other := range plot.Cities {
+				other.HolyCityReligionType = RemoveFromSlice(other.HolyCityReligionType, religion)
+			}
+		}
+		city.ReligionType = AddToSlice(city.ReligionType, religion)
+	}
+	city.HolyCityReligionType = SwitchInSlice(value, city.HolyCityReligionType, religion)
+}
+
+// SuggestCityName returns the first name from the city list of the player not used by other cities,
+// or empty string if all names are used
+func (m *WbMap) SuggestCityName(owner uint) string {
+	if int(owner) >= len(m.Players) {
+		return ""
+	}
+
+	used := make(map[string]bool)
+	for _, plot := range m.GetCityPlots() {
+		for _, city := range plot.Cities {
+			used[city.CityName] = true
+		}
+	}
+	for _, name := range m.Players[owner].CityList {
+		if !used[name] {
+			return name
+		}
+	}
+	return ""
+}
+
+// ValidateCityPlot returns an error if a city cannot be on the plot: the plot is missing, water or peak,
+// or there is another city on adjacent plots (the game doesn't allow cities closer than 2 plots)
+func (g *PlotGrid) ValidateCityPlot(x, y int) error {
+	plot := g.GetPlot(x, y)
+	if plot == nil {
+		return fmt.Errorf("plot %d,%d not found", x, y)
+	}
+	if plot.PlotType == PlotTypeOcean {
+		return fmt.Errorf("plot %d,%d is water", x, y)
+	}
+	if plot.PlotType == PlotTypePeak {
+		return fmt.Errorf("plot %d,%d is peak", x, y)
+	}
+
+	// Neighbours are compared after wrapping, so cities across the map edge are adjacent too
+	x, y, _ = g.normalize(x, y)
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			otherX, otherY, ok := g.normalize(x+dx, y+dy)
+			if !ok || (otherX == x && otherY == y) {
+				continue
+			}
+			if other := g.GetPlot(otherX, otherY); other != nil && len(other.Cities) > 0 {
+				return fmt.Errorf("plot %d,%d is adjacent to city %s", x, y, other.Cities[0].CityName)
+			}
+		}
+	}
+	return nil
+}
+
+// PlaceCity adds a new city of the owner on the plot, name is suggested from the owner's city list
+func (g *PlotGrid) PlaceCity(x, y int, owner uint) (*City, error) {
+	plot := g.GetPlot(x, y)
+	if plot != nil && len(plot.Cities) > 0 {
+		return nil, errors.New("plot already has a city")
+	}
+	if err := g.ValidateCityPlot(x, y); err != nil {
+		return nil, err
+	}
+
+	city := &City{CityOwner: owner, CityName: g.Map.SuggestCityName(owner), CityPopulation: 1}
+	plot.Cities = append(plot.Cities, city)
+	return city, nil
+}
+
+// RemoveCity removes cities from the plot, returns false if there were no cities
+func (g *PlotGrid) RemoveCity(x, y int) bool {
+	plot := g.GetPlot(x, y)
+	if plot == nil || len(plot.Cities) == 0 {
+		return false
+	}
+
+	plot.Cities = nil
+	return true
+}
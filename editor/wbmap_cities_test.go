@@ -0,0 +1,84 @@
+package editor
+
+import (
+	"testing"
+)
+
+func TestPlaceCity(t *testing.T) {
+	m := newTestPaintMap(7, 5, false)
+	m.Players = []*Player{{CivType: "CIVILIZATION_ROME", CityList: []string{"Rome", "Antium"}}}
+	g := NewPlotGrid(m)
+
+	city, err := g.PlaceCity(1, 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if city.CityName != "Rome" || city.CityPopulation != 1 {
+		t.Errorf("Unexpected city %+v", city)
+	}
+	if m.SuggestCityName(0) != "Antium" {
+		t.Errorf("Unexpected suggested name %s", m.SuggestCityName(0))
+	}
+
+	// Water (the middle column), adjacent and occupied plots
+	for _, position := range [][2]int{{3, 1}, {2, 2}, {1, 1}} {
+		if _, err = g.PlaceCity(position[0], position[1], 0); err == nil {
+			t.Errorf("City placed at %v", position)
+		}
+	}
+
+	if _, err = g.PlaceCity(1, 1, 0); err == nil || err.Error() != "plot already has a city" {
+		t.Errorf("Unexpected error for occupied plot: %v", err)
+	}
+
+	if !g.RemoveCity(1, 1) || g.RemoveCity(1, 1) {
+		t.Error("RemoveCity failed")
+	}
+}
+
+func TestPlaceCityWrap(t *testing.T) {
+	g := NewPlotGrid(newTestPaintMap(7, 5, true))
+	if _, err := g.PlaceCity(0, 1, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// The last column is adjacent to the first one on the wrapping map, also by coordinates outside the map
+	for _, position := range [][2]int{{6, 2}, {-1, 1}, {7, 0}} {
+		if err := g.ValidateCityPlot(position[0], position[1]); err == nil {
+			t.Errorf("City allowed at %v next to the map edge", position)
+		}
+	}
+	if err := g.ValidateCityPlot(5, 1); err != nil {
+		t.Errorf("City not allowed 2 plots away: %v", err)
+	}
+}
+
+func TestCityFields(t *testing.T) {
+	m := newTestPaintMap(7, 5, false)
+	g := NewPlotGrid(m)
+	rome, _ := g.PlaceCity(0, 0, 0)
+	antium, _ := g.PlaceCity(5, 0, 0)
+
+	m.SetHolyCity(rome, "RELIGION_JUDAISM", true)
+	m.SetHolyCity(antium, "RELIGION_JUDAISM", true)
+	if len(rome.HolyCityReligionType) != 0 || !IsInSlice(antium.ReligionType, "RELIGION_JUDAISM") || !IsInSlice(antium.HolyCityReligionType, "RELIGION_JUDAISM") {
+		t.Errorf("Holy city must be unique: %+v, %+v", rome, antium)
+	}
+
+	antium.SetReligion("RELIGION_JUDAISM", false)
+	if len(antium.HolyCityReligionType) != 0 {
+		t.Errorf("Holy city must be removed with religion: %+v", antium)
+	}
+
+	rome.SetProduction(ProductionUnit, "UNIT_WARRIOR")
+	rome.SetProduction(ProductionProcess, "PROCESS_WEALTH")
+	if kind, value := rome.GetProduction(); kind != ProductionProcess || value != "PROCESS_WEALTH" || rome.ProductionUnit != "" {
+		t.Errorf("Unexpected production %s %s", kind, value)
+	}
+
+	rome.SetCulture(2, 100)
+	rome.SetCulture(2, 0)
+	if len(rome.PlayerCulture) != 0 {
+		t.Errorf("Zero culture must be removed: %v", rome.PlayerCulture)
+	}
+}
@@ -0,0 +1,66 @@
+package editor
+
+import (
+	"fmt"
+	"slices"
+)
+
+// CopyGameFrom copies game settings (era, speed, options, victories etc.) from another map
+func (m *WbMap) CopyGameFrom(src *WbMap) error {
+	if src.Game == nil {
+		return fmt.Errorf("source map has no game settings")
+	}
+	if m.Game == nil {
+		m.Game = &Game{}
+	}
+
+	*m.Game = cloneGame(*src.Game)
+	return nil
+}
+
+// CopySettingsFrom copies civilization, leader, names and other settings of the player from another map.
+// Team, starting location and attitudes are kept, as indexes of teams and players differ between maps
+func (p *Player) CopySettingsFrom(src *Player) {
+	team, x, y, randomStart := p.Team, p.StartingX, p.StartingY, p.RandomStartLocation
+	attitudePlayer, attitudeExtra := p.AttitudePlayer, p.AttitudeExtra
+
+	*p = clonePlayer(*src)
+	p.Team, p.StartingX, p.StartingY, p.RandomStartLocation = team, x, y, randomStart
+	p.AttitudePlayer, p.AttitudeExtra = attitudePlayer, attitudeExtra
+}
+
+// CopyTechsFrom copies technologies, projects and revealed map of the team from another map, relations are kept
+func (t *Team) CopyTechsFrom(src *Team) {
+	t.Tech = slices.Clone(src.Tech)
+	t.ProjectType = slices.Clone(src.ProjectType)
+	t.RevealMap = src.RevealMap
+}
+
+// CopyPlotsFrom copies terrain, features, resources, improvements, routes and rivers of plots in the selection
+// of another map, so the bottom left plot of the selection becomes the plot x, y. Units, cities and revealed plots
+// are not copied. Returns changed plots
+func (g *PlotGrid) CopyPlotsFrom(src *PlotGrid, s Selection, x, y int) []*Plot {
+	s = s.Normalize()
+	var changed []*Plot
+	visited := make(map[*Plot]bool)
+	for sy := s.Y1; sy <= s.Y2; sy++ {
+		for sx := s.X1; sx <= s.X2; sx++ {
+			from := src.GetPlot(sx, sy)
+			if from == nil {
+				continue
+			}
+			to := g.getOrCreatePlot(x+sx-s.X1, y+sy-s.Y1)
+			if to == nil || visited[to] {
+				continue
+			}
+
+			visited[to] = true
+			g.beforeChange(to)
+			value := clonePlot(*from)
+			value.X, value.Y, value.Units, value.Cities, value.TeamReveal = to.X, to.Y, to.Units, to.Cities, to.TeamReveal
+			*to = value
+			changed = append(changed, to)
+		}
+	}
+	return changed
+}
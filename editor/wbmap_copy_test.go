@@ -0,0 +1,70 @@
+package editor
+
+import "testing"
+
+func TestCopyPlayerAndTeam(t *testing.T) {
+	src := &Player{CivType: "CIVILIZATION_ROME", LeaderType: "LEADER_CAESAR", CivicOption: []string{"CIVICOPTION_LEGAL"}, Civic: []string{"CIVIC_BUREAUCRACY"}, Team: 3, StartingX: 10}
+	dst := &Player{CivType: NonePlayer, Team: 1, StartingX: 5, AttitudePlayer: []uint{0}, AttitudeExtra: []int{2}}
+	dst.CopySettingsFrom(src)
+	if dst.CivType != "CIVILIZATION_ROME" || dst.LeaderType != "LEADER_CAESAR" || dst.GetCivic("CIVICOPTION_LEGAL") != "CIVIC_BUREAUCRACY" {
+		t.Errorf("Settings are not copied: %+v", dst)
+	}
+	if dst.Team != 1 || dst.StartingX != 5 || dst.GetAttitude(0) != 2 {
+		t.Errorf("Team, start and attitudes must be kept: %+v", dst)
+	}
+
+	// Copied slices must not be shared with the source
+	dst.SetCivic("CIVICOPTION_LEGAL", "CIVIC_VASSALAGE")
+	if src.Civic[0] != "CIVIC_BUREAUCRACY" {
+		t.Error("Source player is changed")
+	}
+
+	team := &Team{TeamID: 1, AtWar: []uint{2}}
+	srcTeam := &Team{Tech: []string{"TECH_MINING"}, ProjectType: []string{"PROJECT_APOLLO_PROGRAM"}, RevealMap: true}
+	team.CopyTechsFrom(srcTeam)
+	if len(team.Tech) != 1 || len(team.ProjectType) != 1 || !team.RevealMap || len(team.AtWar) != 1 {
+		t.Errorf("Unexpected team %+v", team)
+	}
+	srcTeam.Tech[0] = "TECH_BRONZE_WORKING"
+	if team.Tech[0] != "TECH_MINING" {
+		t.Error("Copied techs must not be shared with the source team")
+	}
+}
+
+func TestCopyPlots(t *testing.T) {
+	src := NewPlotGrid(newTestPaintMap(5, 3, false))
+	src.GetPlot(1, 1).Units = []*Unit{{UnitType: "UNIT_WARRIOR"}}
+	dst := NewPlotGrid(newTestPaintMap(4, 3, false))
+	dst.GetPlot(0, 0).Cities = []*City{{CityName: "Rome"}}
+
+	// Columns 1-3 of the source (with coast in the middle) become columns 0-2, the rest is outside the map
+	changed := dst.CopyPlotsFrom(src, Selection{3, 2, 1, 0}, 0, 0)
+	if len(changed) != 9 {
+		t.Fatalf("Unexpected number of copied plots: %d", len(changed))
+	}
+	if dst.GetPlot(1, 1).PlotType != PlotTypeOcean || dst.GetPlot(2, 1).PlotType != PlotTypeFlat || dst.GetPlot(2, 1).X != 2 {
+		t.Error("Plots are copied to wrong place")
+	}
+	if len(dst.GetPlot(0, 1).Units) != 0 || len(dst.GetPlot(0, 0).Cities) != 1 {
+		t.Error("Units and cities must not be copied")
+	}
+}
+
+func TestCopyPlotsUndo(t *testing.T) {
+	h := NewHistory()
+	src := NewPlotGrid(newTestPaintMap(3, 3, false))
+	m := newTestPaintMap(3, 3, false)
+	m.Plots = m.Plots[1:]
+	dst := NewPlotGrid(m)
+	dst.OnBeforeChange = h.Track
+
+	// Missing plot is added by copying and removed on undo
+	h.Update("Copy plots", func() { dst.CopyPlotsFrom(src, Selection{0, 0, 2, 2}, 0, 0) })
+	if len(m.Plots) != 9 {
+		t.Fatalf("Missing plot is not copied: %d plots", len(m.Plots))
+	}
+	h.Undo()
+	if len(m.Plots) != 8 {
+		t.Errorf("Undo left the copied plot: %d plots", len(m.Plots))
+	}
+}
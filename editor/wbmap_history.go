@@ -0,0 +1,457 @@
+package editor
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// DefaultHistoryLimit is a number of steps that can be undone
+const DefaultHistoryLimit = 100
+
+// History contains undo and redo stacks of map changes. Changes are recorded as snapshots of changed structs
+// (see Update), so any mutation of the map can be undone if its targets are tracked.
+// Supported targets: *WbMap, *Game, *Team, *Player, *Plot, *City, *Unit, *[]*Team, *[]*Player and *[]*Plot.
+// Changes are made by one goroutine (UI), other goroutines read the map with Read
+type History struct {
+	// Limit is a maximum number of undo steps, the oldest steps are removed
+	Limit int
+	// OnChange is called after every recorded, undone or redone step
+	OnChange func()
+
+	// mu is locked while the map is changed (during the whole group) and while it's read by Read.
+	// version and dirty are copies of the state for other goroutines
+	mu      sync.Mutex
+	version int
+	dirty   bool
+
+	undo  []*historyStep
+	redo  []*historyStep
+	group *historyStep
+	depth int
+	// nextID and savedID are used to check if the map is changed since last save, savedID is -1 if no state is saved
+	nextID  int
+	savedID int
+}
+
+// historyStep is a group of changes undone and redone together
+type historyStep struct {
+	id      int
+	name    string
+	targets []any
+	changes []*historyChange
+	tracked map[any]bool
+	// merge is true for text edits, sequential ones are merged into one step (see UpdateText)
+	merge bool
+}
+
+// historyChange restores the target state before and after the step
+type historyChange struct {
+	target any
+	before func()
+	after  func()
+}
+
+// NewHistory returns empty history with the default limit
+func NewHistory() *History {
+	return &History{Limit: DefaultHistoryLimit}
+}
+
+// Update applies the change of targets as one step, targets may be tracked by apply too (see Track).
+// Every update is a separate step, use UpdateText for text entries
+func (h *History) Update(name string, apply func(), targets ...any) {
+	h.update(name, false, apply, targets)
+}
+
+// UpdateText applies the text change like Update, but sequential text changes with the same name and targets are merged,
+// so typing into a field is undone at once. Discrete edits (checkboxes, selects) must use Update to be undone one by one
+func (h *History) UpdateText(name string, apply func(), targets ...any) {
+	h.update(name, true, apply, targets)
+}
+
+func (h *History) update(name string, merge bool, apply func(), targets []any) {
+	if h.group != nil {
+		for _, target := range targets {
+			h.Track(target)
+		}
+		apply()
+		return
+	}
+
+	if merge && len(h.undo) > 0 && len(h.redo) == 0 && len(targets) > 0 {
+		if last := h.undo[len(h.undo)-1]; last.merge && last.name == name && last.id != h.savedID && slices.Equal(last.targets, targets) {
+			h.mu.Lock()
+			apply()
+			for _, change := range last.changes {
+				change.after = takeSnapshot(change.target)
+			}
+			h.mu.Unlock()
+			h.changed()
+			return
+		}
+	}
+
+	h.Begin(name, targets...)
+	h.group.merge = merge
+	apply()
+	h.End()
+}
+
+// Try applies the change like Update, but targets are restored and nothing is recorded if apply returns an error
+func (h *History) Try(name string, apply func() error, targets ...any) error {
+	if h.group != nil {
+		for _, target := range targets {
+			h.Track(target)
+		}
+		return apply()
+	}
+
+	h.Begin(name, targets...)
+	if err := apply(); err != nil {
+		step := h.group
+		h.group, h.depth = nil, 0
+		for i := len(step.changes) - 1; i >= 0; i-- {
+			step.changes[i].before()
+		}
+		h.mu.Unlock()
+		return err
+	}
+	h.End()
+	return nil
+}
+
+// Begin starts a group of changes undone in one step (e.g. brush stroke), targets may be tracked later (see Track).
+// Groups may be nested, the step is recorded when the outer group ends
+func (h *History) Begin(name string, targets ...any) {
+	h.depth++
+	if h.group == nil {
+		h.mu.Lock()
+		h.group = &historyStep{name: name, targets: targets, tracked: make(map[any]bool)}
+	}
+	for _, target := range targets {
+		h.Track(target)
+	}
+}
+
+// Track saves the target state before it's changed. It's ignored outside a group or if the target is already tracked
+func (h *History) Track(target any) {
+	if h.group == nil || h.group.tracked[target] {
+		return
+	}
+	if v := reflect.ValueOf(target); !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil()) {
+		return
+	}
+
+	if before := takeSnapshot(target); before != nil {
+		h.group.tracked[target] = true
+		h.group.changes = append(h.group.changes, &historyChange{target: target, before: before})
+	}
+}
+
+// End finishes the group and records it as a step if anything is tracked
+func (h *History) End() {
+	if h.depth == 0 {
+		return
+	}
+	h.depth--
+	if h.depth > 0 {
+		return
+	}
+
+	step := h.group
+	h.group = nil
+	if len(step.changes) == 0 {
+		h.mu.Unlock()
+		return
+	}
+
+	for _, change := range step.changes {
+		change.after = takeSnapshot(change.target)
+	}
+	h.mu.Unlock()
+	h.nextID++
+	step.id = h.nextID
+	h.undo = append(h.undo, step)
+	h.redo = nil
+	if h.Limit > 0 && len(h.undo) > h.Limit {
+		h.undo = h.undo[len(h.undo)-h.Limit:]
+	}
+	h.changed()
+}
+
+// Undo restores the state before the last step, returns false if there is nothing to undo
+func (h *History) Undo() bool {
+	if len(h.undo) == 0 || h.group != nil {
+		return false
+	}
+
+	step := h.undo[len(h.undo)-1]
+	h.undo = h.undo[:len(h.undo)-1]
+	h.mu.Lock()
+	for i := len(step.changes) - 1; i >= 0; i-- {
+		step.changes[i].before()
+	}
+	h.mu.Unlock()
+	h.redo = append(h.redo, step)
+	h.changed()
+	return true
+}
+
+// Redo restores the state after the last undone step, returns false if there is nothing to redo
+func (h *History) Redo() bool {
+	if len(h.redo) == 0 || h.group != nil {
+		return false
+	}
+
+	step := h.redo[len(h.redo)-1]
+	h.redo = h.redo[:len(h.redo)-1]
+	h.mu.Lock()
+	for _, change := range step.changes {
+		change.after()
+	}
+	h.mu.Unlock()
+	h.undo = append(h.undo, step)
+	h.changed()
+	return true
+}
+
+// CanUndo returns true if there are steps to undo
+func (h *History) CanUndo() bool {
+	return len(h.undo) > 0
+}
+
+// CanRedo returns true if there are undone steps to redo
+func (h *History) CanRedo() bool {
+	return len(h.redo) > 0
+}
+
+// GetUndoName returns the name of the step to undo, or empty string if there is nothing to undo
+func (h *History) GetUndoName() string {
+	if len(h.undo) == 0 {
+		return ""
+	}
+	return h.undo[len(h.undo)-1].name
+}
+
+// IsDirty returns true if the map is changed since it was saved (see MarkSaved)
+func (h *History) IsDirty() bool {
+	current := 0
+	if len(h.undo) > 0 {
+		current = h.undo[len(h.undo)-1].id
+	}
+	return current != h.savedID
+}
+
+// MarkSaved marks the current state as saved, so the map is not dirty until next change
+func (h *History) MarkSaved() {
+	h.savedID = 0
+	if len(h.undo) > 0 {
+		h.savedID = h.undo[len(h.undo)-1].id
+	}
+	h.changed()
+}
+
+// MarkUnsaved makes the map dirty until it's saved, e.g. if it's recovered from autosave
+func (h *History) MarkUnsaved() {
+	h.savedID = -1
+	h.changed()
+}
+
+// Change calls fn changing the map outside of steps, the map is locked only while fn runs. It's used for changes
+// recorded as a step later, e.g. a brush stroke painted while the mouse is dragged (see MapCanvas)
+func (h *History) Change(fn func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	fn()
+}
+
+// Read calls fn while the map is not changed. Version is increased after every change, dirty is true if the map is not saved.
+// It's safe to call from any goroutine
+func (h *History) Read(fn func(version int, dirty bool)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	fn(h.version, h.dirty)
+}
+
+func (h *History) changed() {
+	h.mu.Lock()
+	h.version++
+	h.dirty = h.IsDirty()
+	h.mu.Unlock()
+	if h.OnChange != nil {
+		h.OnChange()
+	}
+}
+
+// Set returns a callback assigning the value to the field as an undoable step, target is the struct containing the field.
+// E.g. Set(history, "Era", game, &game.Era) for selects and checkboxes
+func Set[T any](h *History, name string, target any, field *T) func(T) {
+	return func(value T) {
+		h.Update(name, func() { *field = value }, target)
+	}
+}
+
+// SetText is like Set for text entries, typing into the entry is merged into one step (see UpdateText).
+// E.g. SetText(history, "Description", game, &game.Description)
+func SetText[T any](h *History, name string, target any, field *T) func(T) {
+	return func(value T) {
+		h.UpdateText(name, func() { *field = value }, target)
+	}
+}
+
+// takeSnapshot saves the current state of the target and returns a function restoring it, or nil for unsupported targets
+func takeSnapshot(target any) func() {
+	switch t := target.(type) {
+	case *Game:
+		value := cloneGame(*t)
+		return func() { *t = cloneGame(value) }
+	case *Team:
+		value := cloneTeam(*t)
+		return func() { *t = cloneTeam(value) }
+	case *Player:
+		value := clonePlayer(*t)
+		return func() { *t = clonePlayer(value) }
+	case *Plot:
+		return snapshotPlot(t)
+	case *City:
+		value := cloneCity(*t)
+		return func() { *t = cloneCity(value) }
+	case *Unit:
+		value := cloneUnit(*t)
+		return func() { *t = cloneUnit(value) }
+	case *[]*Team:
+		return snapshotSlice(t, snapshotWith(cloneTeam))
+	case *[]*Player:
+		return snapshotSlice(t, snapshotWith(clonePlayer))
+	case *[]*Plot:
+		// Only the list is restored (e.g. plots added by a brush are removed), changed plots are tracked one by one
+		pointers := slices.Clone(*t)
+		return func() { *t = slices.Clone(pointers) }
+	case *WbMap:
+		// Pointers of plots, units, cities, players and teams are kept, so other references to them
+		// (e.g. PlotGrid or steps tracking a city) stay valid
+		version, game, props := t.Version, t.Game, t.Map
+		var gameValue Game
+		var propsValue MapProps
+		if game != nil {
+			gameValue = cloneGame(*game)
+		}
+		if props != nil {
+			propsValue = *props
+		}
+		teams, players, plots := snapshotSlice(&t.Teams, snapshotWith(cloneTeam)), snapshotSlice(&t.Players, snapshotWith(clonePlayer)), snapshotSlice(&t.Plots, snapshotPlot)
+		return func() {
+			t.Version, t.Game, t.Map = version, game, props
+			if game != nil {
+				*game = cloneGame(gameValue)
+			}
+			if props != nil {
+				*props = propsValue
+			}
+			teams()
+			players()
+			plots()
+		}
+	}
+	return nil
+}
+
+// snapshotSlice saves the slice of pointers and values they point to, values are restored into the same pointers
+func snapshotSlice[T any](slice *[]*T, snapshot func(*T) func()) func() {
+	pointers := slices.Clone(*slice)
+	restore := make([]func(), len(pointers))
+	for i, p := range pointers {
+		restore[i] = snapshot(p)
+	}
+	return func() {
+		*slice = slices.Clone(pointers)
+		for _, fn := range restore {
+			fn()
+		}
+	}
+}
+
+// snapshotWith returns a snapshot function for snapshotSlice restoring the value copied by clone
+func snapshotWith[T any](clone func(T) T) func(*T) func() {
+	return func(p *T) func() {
+		value := clone(*p)
+		return func() { *p = clone(value) }
+	}
+}
+
+// snapshotPlot saves the plot with its units and cities. Units and cities are restored into the same structs,
+// so steps tracking them (e.g. renaming a city) are still applied to the plot after undo and redo
+func snapshotPlot(p *Plot) func() {
+	value := clonePlot(*p)
+	units, cities := snapshotSlice(&p.Units, snapshotWith(cloneUnit)), snapshotSlice(&p.Cities, snapshotWith(cloneCity))
+	return func() {
+		*p = clonePlot(value)
+		units()
+		cities()
+	}
+}
+
+func cloneGame(g Game) Game {
+	g.Option = slices.Clone(g.Option)
+	g.MPOption = slices.Clone(g.MPOption)
+	g.ForceControl = slices.Clone(g.ForceControl)
+	g.Victory = slices.Clone(g.Victory)
+	return g
+}
+
+func cloneTeam(t Team) Team {
+	t.Tech = slices.Clone(t.Tech)
+	t.ContactWithTeam = slices.Clone(t.ContactWithTeam)
+	t.AtWar = slices.Clone(t.AtWar)
+	t.PermanentWarPeace = slices.Clone(t.PermanentWarPeace)
+	t.OpenBordersWithTeam = slices.Clone(t.OpenBordersWithTeam)
+	t.DefensivePactWithTeam = slices.Clone(t.DefensivePactWithTeam)
+	t.ProjectType = slices.Clone(t.ProjectType)
+	return t
+}
+
+func clonePlayer(p Player) Player {
+	p.CityList = slices.Clone(p.CityList)
+	p.CivicOption = slices.Clone(p.CivicOption)
+	p.Civic = slices.Clone(p.Civic)
+	p.AttitudePlayer = slices.Clone(p.AttitudePlayer)
+	p.AttitudeExtra = slices.Clone(p.AttitudeExtra)
+	return p
+}
+
+// clonePlot copies the plot with its units and cities, units and cities are copied as new structs
+func clonePlot(p Plot) Plot {
+	p.FeatureType = slices.Clone(p.FeatureType)
+	p.FeatureVariety = slices.Clone(p.FeatureVariety)
+	p.TeamReveal = slices.Clone(p.TeamReveal)
+	p.Units = cloneSlice(p.Units, cloneUnit)
+	p.Cities = cloneSlice(p.Cities, cloneCity)
+	return p
+}
+
+func cloneCity(c City) City {
+	c.BuildingType = slices.Clone(c.BuildingType)
+	c.ReligionType = slices.Clone(c.ReligionType)
+	c.HolyCityReligionType = slices.Clone(c.HolyCityReligionType)
+	c.PlayerCulture = maps.Clone(c.PlayerCulture)
+	return c
+}
+
+func cloneUnit(u Unit) Unit {
+	u.PromotionType = slices.Clone(u.PromotionType)
+	return u
+}
+
+// cloneSlice returns a slice of new structs with cloned values
+func cloneSlice[T any](slice []*T, clone func(T) T) []*T {
+	if slice == nil {
+		return nil
+	}
+	result := make([]*T, len(slice))
+	for i, p := range slice {
+		value := clone(*p)
+		result[i] = &value
+	}
+	return result
+}
@@ -0,0 +1,195 @@
+package editor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestHistoryFieldEdits(t *testing.T) {
+	h := NewHistory()
+	game := &Game{Era: "ERA_ANCIENT"}
+
+	// Typing into the same field is merged into one step
+	SetText(h, "Description", game, &game.Description)("M")
+	SetText(h, "Description", game, &game.Description)("My map")
+	Set(h, "Era", game, &game.Era)("ERA_MEDIEVAL")
+	if !h.IsDirty() || h.GetUndoName() != "Era" {
+		t.Fatalf("Unexpected history state: dirty %v, undo %s", h.IsDirty(), h.GetUndoName())
+	}
+
+	if !h.Undo() || game.Era != "ERA_ANCIENT" || game.Description != "My map" {
+		t.Errorf("Undo of era failed: %+v", game)
+	}
+	if !h.Undo() || game.Description != "" || h.CanUndo() || h.IsDirty() {
+		t.Errorf("Undo of description failed: %+v", game)
+	}
+	if !h.Redo() || !h.Redo() || h.Redo() || game.Era != "ERA_MEDIEVAL" || game.Description != "My map" {
+		t.Errorf("Redo failed: %+v", game)
+	}
+
+	h.MarkSaved()
+	if h.IsDirty() {
+		t.Error("Saved history must not be dirty")
+	}
+	h.Undo()
+	if !h.IsDirty() {
+		t.Error("History must be dirty after undo of saved step")
+	}
+
+	// A new change after undo drops redo steps
+	Set(h, "Victory", game, &game.Victory)([]string{"VICTORY_SPACE_RACE"})
+	if h.CanRedo() {
+		t.Error("Redo must be cleared by a new change")
+	}
+
+	// Discrete edits are undone one by one
+	Set(h, "Tutorial", game, &game.Tutorial)(true)
+	Set(h, "Tutorial", game, &game.Tutorial)(false)
+	if !h.Undo() || !game.Tutorial || h.GetUndoName() != "Tutorial" {
+		t.Errorf("Toggles must not be merged: %+v", game)
+	}
+}
+
+func TestHistoryBrushStroke(t *testing.T) {
+	h := NewHistory()
+	g := NewPlotGrid(newTestPaintMap(5, 3, false))
+	g.OnBeforeChange = h.Track
+
+	// Painting outside a group is not recorded
+	if _, err := g.Paint(0, 0, Brush{Tool: BrushTerrain, Value: "TERRAIN_DESERT", Size: 1}); err != nil || h.CanUndo() {
+		t.Fatalf("Unexpected step: %v", err)
+	}
+
+	h.Begin("Paint")
+	for x := 0; x < 5; x++ {
+		g.Paint(x, 1, Brush{Tool: BrushTerrain, Value: "TERRAIN_PLAINS", Size: 1})
+	}
+	g.Paint(0, 1, Brush{Tool: BrushTerrain, Value: "TERRAIN_TUNDRA", Size: 1})
+	h.End()
+
+	if !h.Undo() || h.CanUndo() {
+		t.Fatal("Stroke must be undone in one step")
+	}
+	if g.GetPlot(0, 1).TerrainType != "TERRAIN_GRASS" || g.GetPlot(2, 1).TerrainType != "TERRAIN_COAST" || g.GetPlot(0, 0).TerrainType != "TERRAIN_DESERT" {
+		t.Errorf("Unexpected plots after undo: %s, %s", g.GetPlot(0, 1).TerrainType, g.GetPlot(2, 1).TerrainType)
+	}
+	h.Redo()
+	if g.GetPlot(0, 1).TerrainType != "TERRAIN_TUNDRA" || g.GetPlot(4, 1).TerrainType != "TERRAIN_PLAINS" {
+		t.Errorf("Unexpected plots after redo: %s, %s", g.GetPlot(0, 1).TerrainType, g.GetPlot(4, 1).TerrainType)
+	}
+}
+
+func TestHistoryPaintMissingPlot(t *testing.T) {
+	h := NewHistory()
+	m := newTestPaintMap(5, 3, false)
+	m.Game, m.Plots = &Game{}, m.Plots[1:]
+	g := NewPlotGrid(m)
+	g.OnBeforeChange = h.Track
+
+	h.Begin("Paint")
+	g.Paint(0, 0, Brush{Tool: BrushTerrain, Value: "TERRAIN_PLAINS", Size: 1})
+	h.End()
+	if len(m.Plots) != 15 || g.GetPlot(0, 0).TerrainType != "TERRAIN_PLAINS" {
+		t.Fatalf("Missing plot is not added: %d plots", len(m.Plots))
+	}
+
+	// The added plot is removed on undo, so no plot without terrain is saved
+	h.Undo()
+	g.Reload()
+	if len(m.Plots) != 14 || g.GetPlot(0, 0) != nil {
+		t.Fatalf("Undo left the added plot: %d plots", len(m.Plots))
+	}
+	if saved := string(m.ToWbFormat()); strings.Count(saved, "BeginPlot") != 14 || strings.Contains(saved, "TerrainType=\n") {
+		t.Errorf("Unexpected saved plots:\n%s", saved)
+	}
+
+	h.Redo()
+	g.Reload()
+	if len(m.Plots) != 15 || g.GetPlot(0, 0).TerrainType != "TERRAIN_PLAINS" {
+		t.Errorf("Redo did not restore the added plot: %d plots", len(m.Plots))
+	}
+}
+
+func TestHistoryBulkChanges(t *testing.T) {
+	h := NewHistory()
+	m := newTestPaintMap(5, 3, false)
+	m.Game = &Game{}
+	m.Players = []*Player{{CivType: "CIVILIZATION_ROME", StartingX: 1, StartingY: 1}}
+	m.Teams = []*Team{{TeamID: 0, ContactWithTeam: []uint{0}}}
+	g := NewPlotGrid(m)
+	r := newTestUnitRegistries()
+	plot := g.GetPlot(1, 1)
+
+	h.Update("Starting units", func() { g.AddStartingUnits(r, []string{"UNIT_WARRIOR"}) }, m)
+	unit := plot.Units[0]
+	Set(h, "Level", unit, &unit.Level)(3)
+	if err := h.Try("Add unit", func() error {
+		_, err := g.AddUnit(r, 2, 1, "UNIT_WARRIOR", 0)
+		return err
+	}, g.GetPlot(2, 1)); err == nil || h.GetUndoName() != "Level" {
+		t.Errorf("Failed change must not be recorded: %v", err)
+	}
+
+	h.Update("Remove player", func() { m.RemovePlayer(0) }, m)
+	if len(plot.Units) != 0 || !m.Players[0].IsEmpty() {
+		t.Fatal("Player is not removed")
+	}
+
+	// Plots keep their pointers, so grids created before undo stay valid
+	h.Undo()
+	if m.Players[0].CivType != "CIVILIZATION_ROME" || g.GetPlot(1, 1) != m.Plots[6] || len(plot.Units) != 1 || plot.Units[0].Level != 3 {
+		t.Errorf("Undo of player removal failed: %+v", m.Players[0])
+	}
+	h.Undo()
+	h.Undo()
+	if len(plot.Units) != 0 || h.CanUndo() {
+		t.Errorf("Undo of starting units failed: %d units", len(plot.Units))
+	}
+
+	if h.Try("Failed", func() error { return errors.New("failed") }, m) == nil || h.CanUndo() {
+		t.Error("Try must return the error without a step")
+	}
+}
+
+func TestHistoryPlacedCityEdits(t *testing.T) {
+	h := NewHistory()
+	m := newTestPaintMap(7, 5, false)
+	m.Players = []*Player{{CivType: "CIVILIZATION_ROME", CityList: []string{"Rome"}}}
+	g := NewPlotGrid(m)
+	plot := g.GetPlot(1, 1)
+
+	var city *City
+	if err := h.Try("Place city", func() (err error) {
+		city, err = g.PlaceCity(1, 1, 0)
+		return err
+	}, plot); err != nil {
+		t.Fatal(err)
+	}
+	Set(h, "CityName", city, &city.CityName)("Roma")
+
+	// Redo of the plot restores the same city, so the rename is applied to the map again
+	h.Undo()
+	h.Undo()
+	if len(plot.Cities) != 0 {
+		t.Fatalf("Undo of placed city failed: %d cities", len(plot.Cities))
+	}
+	h.Redo()
+	h.Redo()
+	if len(plot.Cities) != 1 || plot.Cities[0] != city || plot.Cities[0].CityName != "Roma" {
+		t.Errorf("Redo of renamed city failed: %+v", plot.Cities)
+	}
+
+	// Same for steps of the whole map
+	h.Update("Remove player", func() { m.RemovePlayer(0) }, m)
+	if len(plot.Cities) != 0 {
+		t.Fatalf("Cities of removed player are not removed: %d", len(plot.Cities))
+	}
+	h.Undo()
+	Set(h, "CityName", city, &city.CityName)("Antium")
+	h.Undo()
+	h.Redo()
+	if len(plot.Cities) != 1 || plot.Cities[0] != city || city.CityName != "Antium" {
+		t.Errorf("Undo of player removal failed: %+v", plot.Cities)
+	}
+}
@@ -0,0 +1,409 @@
+package editor
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Plot types (see Plot.PlotType)
+const (
+	PlotTypePeak uint = iota
+	PlotTypeHills
+	PlotTypeFlat
+	PlotTypeOcean
+)
+
+// River flow directions (see Plot.RiverNSDirection and Plot.RiverWEDirection)
+const (
+	RiverFlowNorth = iota
+	RiverFlowEast
+	RiverFlowSouth
+	RiverFlowWest
+)
+
+// BrushTool is a plot property changed by brush
+type BrushTool string
+
+const (
+	BrushPlotType    BrushTool = "PlotType"
+	BrushTerrain     BrushTool = "TerrainType"
+	BrushFeature     BrushTool = "FeatureType"
+	BrushBonus       BrushTool = "BonusType"
+	BrushImprovement BrushTool = "ImprovementType"
+	BrushRoute       BrushTool = "RouteType"
+	// BrushRiver places a river on the east edge of the plot (flowing north or south)
+	// or on the south edge (flowing east or west), value is the flow direction
+	BrushRiver BrushTool = "River"
+)
+
+// BrushTools contains all tools in the order they are shown in the editor
+var BrushTools = []BrushTool{BrushPlotType, BrushTerrain, BrushFeature, BrushBonus, BrushImprovement, BrushRoute, BrushRiver}
+
+// Brush describes how plots are painted. Empty value removes the property (except plot type and terrain, which are required)
+type Brush struct {
+	Tool  BrushTool
+	Value string
+	// Variety is a feature variety, used with BrushFeature only
+	Variety string
+	// Size is a brush radius in plots, 1 paints a single plot, 2 paints 3x3 plots etc.
+	Size int
+}
+
+// GetValue returns the property of the plot changed by the brush, used to compare plots for flood fill
+func (b *Brush) GetValue(p *Plot) string {
+	switch b.Tool {
+	case BrushPlotType:
+		return strconv.Itoa(int(p.PlotType))
+	case BrushTerrain:
+		return p.TerrainType
+	case BrushFeature:
+		if len(p.FeatureType) == 0 {
+			return ""
+		}
+		return p.FeatureType[0]
+	case BrushBonus:
+		return p.BonusType
+	case BrushImprovement:
+		return p.ImprovementType
+	case BrushRoute:
+		return p.RouteType
+	case BrushRiver:
+		if p.IsWOfRiver {
+			return strconv.Itoa(p.RiverNSDirection)
+		}
+		if p.IsNOfRiver {
+			return strconv.Itoa(p.RiverWEDirection)
+		}
+	}
+	return ""
+}
+
+// isApplied returns true if the plot already has the value of the brush
+func (b *Brush) isApplied(p *Plot) bool {
+	if b.GetValue(p) != b.Value {
+		return false
+	}
+	if b.Tool != BrushFeature || b.Value == "" || len(p.FeatureVariety) == 0 {
+		return true
+	}
+	return p.FeatureVariety[0] == b.Variety || (b.Variety == "" && p.FeatureVariety[0] == "0")
+}
+
+// Apply changes the plot property
+func (b *Brush) Apply(p *Plot) {
+	switch b.Tool {
+	case BrushPlotType:
+		p.PlotType = ToUint(b.Value)
+	case BrushTerrain:
+		p.TerrainType = b.Value
+	case BrushFeature:
+		p.FeatureType, p.FeatureVariety = nil, nil
+		if b.Value != "" {
+			variety := b.Variety
+			if variety == "" {
+				variety = "0"
+			}
+			p.FeatureType, p.FeatureVariety = []string{b.Value}, []string{variety}
+		}
+	case BrushBonus:
+		p.BonusType = b.Value
+	case BrushImprovement:
+		p.ImprovementType = b.Value
+	case BrushRoute:
+		p.RouteType = b.Value
+	case BrushRiver:
+		p.IsNOfRiver, p.IsWOfRiver = false, false
+		if b.Value == "" {
+			break
+		}
+
+		direction := ToInt(b.Value)
+		if direction == RiverFlowNorth || direction == RiverFlowSouth {
+			p.IsWOfRiver, p.RiverNSDirection = true, direction
+		} else {
+			p.IsNOfRiver, p.RiverWEDirection = true, direction
+		}
+	}
+}
+
+// validate returns an error if the brush cannot be applied
+func (b *Brush) validate() error {
+	switch b.Tool {
+	case BrushPlotType:
+		if v := ToInt(b.Value); b.Value == "" || v < int(PlotTypePeak) || v > int(PlotTypeOcean) {
+			return errors.New("invalid plot type " + b.Value)
+		}
+	case BrushTerrain:
+		if b.Value == "" {
+			return errors.New("terrain is required")
+		}
+	case BrushRiver:
+		if v := ToInt(b.Value); b.Value != "" && (v < RiverFlowNorth || v > RiverFlowWest) {
+			return errors.New("invalid river direction " + b.Value)
+		}
+	case BrushFeature, BrushBonus, BrushImprovement, BrushRoute:
+	default:
+		return errors.New("unknown brush tool " + string(b.Tool))
+	}
+	return nil
+}
+
+// Selection is a rectangle of plots, corners are included
+type Selection struct {
+	X1, Y1, X2, Y2 int
+}
+
+// Normalize returns the selection with the first corner at bottom left
+func (s Selection) Normalize() Selection {
+	if s.X1 > s.X2 {
+		s.X1, s.X2 = s.X2, s.X1
+	}
+	if s.Y1 > s.Y2 {
+		s.Y1, s.Y2 = s.Y2, s.Y1
+	}
+	return s
+}
+
+// Contains returns true if the plot is in the selection
+func (s Selection) Contains(x, y int) bool {
+	s = s.Normalize()
+	return x >= s.X1 && x <= s.X2 && y >= s.Y1 && y <= s.Y2
+}
+
+// PlotGrid gives access to plots of the map by coordinates, taking map wrapping into account.
+// Plots missing in the map are created when painted
+type PlotGrid struct {
+	Map    *WbMap
+	Width  int
+	Height int
+	WrapX  bool
+	WrapY  bool
+	// OnBeforeChange is called before the plot (*Plot) is changed by a brush, or before a missing plot is added
+	// to plots of the map (*[]*Plot), e.g. to track it in History
+	OnBeforeChange func(target any)
+	plots          []*Plot
+}
+
+// NewPlotGrid returns a grid for the map. Size is taken from map properties or from plots if properties are not set
+func NewPlotGrid(m *WbMap) *PlotGrid {
+	g := &PlotGrid{Map: m}
+	if m.Map != nil {
+		g.Width, g.Height = int(m.Map.GridWidth), int(m.Map.GridHeight)
+		g.WrapX, g.WrapY = m.Map.WrapX == 1, m.Map.WrapY == 1
+	}
+	for _, plot := range m.Plots {
+		g.Width = max(g.Width, int(plot.X)+1)
+		g.Height = max(g.Height, int(plot.Y)+1)
+	}
+
+	g.Reload()
+	return g
+}
+
+// Reload indexes plots of the map again, e.g. after undo removed plots added by a brush. Size of the grid is kept
+func (g *PlotGrid) Reload() {
+	g.plots = make([]*Plot, g.Width*g.Height)
+	for _, plot := range g.Map.Plots {
+		if x, y := int(plot.X), int(plot.Y); x < g.Width && y < g.Height {
+			g.plots[y*g.Width+x] = plot
+		}
+	}
+}
+
+// NewWbMap returns a map of ocean plots with default game settings, wrapping on the x-axis like maps created by the game
+func NewWbMap(width, height int) (*WbMap, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("invalid map size %dx%d", width, height)
+	}
+
+	m := &WbMap{
+		Version: defaultVersion,
+		Game:    &Game{Era: "ERA_ANCIENT", Speed: "GAMESPEED_NORMAL", Calendar: "CALENDAR_DEFAULT", StartYear: -4000},
+		Map: &MapProps{
+			GridWidth:       uint64(width),
+			GridHeight:      uint64(height),
+			TopLatitude:     90,
+			BottomLatitude:  -90,
+			WrapX:           1,
+			WorldSize:       "WORLDSIZE_STANDARD",
+			Climate:         "CLIMATE_TEMPERATE",
+			SeaLevel:        "SEALEVEL_MEDIUM",
+			NumPlotsWritten: uint64(width * height),
+		},
+	}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			m.Plots = append(m.Plots, &Plot{X: uint(x), Y: uint(y), TerrainType: "TERRAIN_OCEAN", PlotType: PlotTypeOcean})
+		}
+	}
+	return m, nil
+}
+
+// normalize returns coordinates inside the grid after wrapping, ok is false if the plot is outside the map
+func (g *PlotGrid) normalize(x, y int) (int, int, bool) {
+	if g.WrapX && g.Width > 0 {
+		x = (x%g.Width + g.Width) % g.Width
+	}
+	if g.WrapY && g.Height > 0 {
+		y = (y%g.Height + g.Height) % g.Height
+	}
+	return x, y, x >= 0 && y >= 0 && x < g.Width && y < g.Height
+}
+
+// GetPlot returns the plot by coordinates or nil if it's outside the map or missing
+func (g *PlotGrid) GetPlot(x, y int) *Plot {
+	x, y, ok := g.normalize(x, y)
+	if !ok {
+		return nil
+	}
+	return g.plots[y*g.Width+x]
+}
+
+// getOrCreatePlot returns the plot, adding it to the map if it's missing
+func (g *PlotGrid) getOrCreatePlot(x, y int) *Plot {
+	x, y, ok := g.normalize(x, y)
+	if !ok {
+		return nil
+	}
+
+	plot := g.plots[y*g.Width+x]
+	if plot == nil {
+		g.beforeChange(&g.Map.Plots)
+		plot = &Plot{X: uint(x), Y: uint(y), PlotType: PlotTypeOcean}
+		g.plots[y*g.Width+x] = plot
+		g.Map.Plots = append(g.Map.Plots, plot)
+	}
+	return plot
+}
+
+// Paint applies the brush to plots around the point and returns changed plots
+func (g *PlotGrid) Paint(x, y int, b Brush) ([]*Plot, error) {
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+
+	radius := max(b.Size, 1) - 1
+	return g.applyTo(Selection{x - radius, y - radius, x + radius, y + radius}, b), nil
+}
+
+// FillSelection applies the brush to all plots in the selection and returns changed plots
+func (g *PlotGrid) FillSelection(s Selection, b Brush) ([]*Plot, error) {
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+	return g.applyTo(s.Normalize(), b), nil
+}
+
+// applyTo applies the brush to plots in the normalized selection, every plot is changed once even if the selection wraps
+func (g *PlotGrid) applyTo(s Selection, b Brush) []*Plot {
+	var changed []*Plot
+	visited := make(map[*Plot]bool)
+	for y := s.Y1; y <= s.Y2; y++ {
+		for x := s.X1; x <= s.X2; x++ {
+			plot := g.getOrCreatePlot(x, y)
+			if plot == nil || visited[plot] {
+				continue
+			}
+
+			visited[plot] = true
+			if !b.isApplied(plot) {
+				g.beforeChange(plot)
+				b.Apply(plot)
+				changed = append(changed, plot)
+			}
+		}
+	}
+	return changed
+}
+
+// FloodFill applies the brush to the plot and all connected plots (by sides) having the same value, returns changed plots
+func (g *PlotGrid) FloodFill(x, y int, b Brush) ([]*Plot, error) {
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+
+	start := g.getOrCreatePlot(x, y)
+	if start == nil {
+		return nil, errors.New("plot is outside the map")
+	}
+
+	target := b.GetValue(start)
+	if target == b.Value {
+		return nil, nil
+	}
+
+	var changed []*Plot
+	queue := [][2]int{{int(start.X), int(start.Y)}}
+	for len(queue) > 0 {
+		point := queue[0]
+		queue = queue[1:]
+
+		plot := g.GetPlot(point[0], point[1])
+		if plot == nil || b.GetValue(plot) != target {
+			continue
+		}
+
+		g.beforeChange(plot)
+		b.Apply(plot)
+		changed = append(changed, plot)
+		for _, d := range [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+			queue = append(queue, [2]int{int(plot.X) + d[0], int(plot.Y) + d[1]})
+		}
+	}
+	return changed, nil
+}
+
+func (g *PlotGrid) beforeChange(target any) {
+	if g.OnBeforeChange != nil {
+		g.OnBeforeChange(target)
+	}
+}
+
+// GetPlotsIn returns existing plots in the selection
+func (g *PlotGrid) GetPlotsIn(s Selection) []*Plot {
+	s = s.Normalize()
+	var plots []*Plot
+	visited := make(map[*Plot]bool)
+	for y := s.Y1; y <= s.Y2; y++ {
+		for x := s.X1; x <= s.X2; x++ {
+			if plot := g.GetPlot(x, y); plot != nil && !visited[plot] {
+				visited[plot] = true
+				plots = append(plots, plot)
+			}
+		}
+	}
+	return plots
+}
+
+// GetBrushChoices returns values available for the brush tool. Empty type removes the property
+func (r *GameRegistries) GetBrushChoices(tool BrushTool) []Choice {
+	none := []Choice{{Description: "None"}}
+	switch tool {
+	case BrushPlotType:
+		return []Choice{
+			{Type: strconv.Itoa(int(PlotTypePeak)), Description: "Peak"},
+			{Type: strconv.Itoa(int(PlotTypeHills)), Description: "Hills"},
+			{Type: strconv.Itoa(int(PlotTypeFlat)), Description: "Flat"},
+			{Type: strconv.Itoa(int(PlotTypeOcean)), Description: "Water"},
+		}
+	case BrushTerrain:
+		return GetChoices(r, r.TerrainInfos, OrderXML)
+	case BrushFeature:
+		return append(none, GetChoices(r, r.FeatureInfos, OrderXML)...)
+	case BrushBonus:
+		return append(none, GetChoices(r, r.BonusInfos, OrderXML)...)
+	case BrushImprovement:
+		return append(none, GetChoices(r, r.ImprovementInfos, OrderXML)...)
+	case BrushRoute:
+		return append(none, GetChoices(r, r.RouteInfos, OrderXML)...)
+	case BrushRiver:
+		return append(none,
+			Choice{Type: strconv.Itoa(RiverFlowNorth), Description: "Flowing north (east edge)"},
+			Choice{Type: strconv.Itoa(RiverFlowSouth), Description: "Flowing south (east edge)"},
+			Choice{Type: strconv.Itoa(RiverFlowEast), Description: "Flowing east (south edge)"},
+			Choice{Type: strconv.Itoa(RiverFlowWest), Description: "Flowing west (south edge)"},
+		)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package editor
+
+import (
+	"testing"
+)
+
+// newTestPaintMap returns a map of grassland with a lake in the middle column
+func newTestPaintMap(width, height int, wrapX bool) *WbMap {
+	m := &WbMap{Map: &MapProps{GridWidth: uint64(width), GridHeight: uint64(height), WrapX: BoolToInt(wrapX)}}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			plot := &Plot{X: uint(x), Y: uint(y), TerrainType: "TERRAIN_GRASS", PlotType: PlotTypeFlat}
+			if x == width/2 {
+				plot.TerrainType, plot.PlotType = "TERRAIN_COAST", PlotTypeOcean
+			}
+			m.Plots = append(m.Plots, plot)
+		}
+	}
+	return m
+}
+
+func TestPlotGridPaint(t *testing.T) {
+	g := NewPlotGrid(newTestPaintMap(5, 5, true))
+
+	// Brush of size 2 wraps over the left edge: columns 4, 0 and 1
+	changed, err := g.Paint(0, 2, Brush{Tool: BrushFeature, Value: "FEATURE_FOREST", Variety: "1", Size: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 9 || len(g.GetPlot(4, 1).FeatureType) != 1 || g.GetPlot(4, 1).FeatureVariety[0] != "1" || len(g.GetPlot(2, 2).FeatureType) != 0 {
+		t.Errorf("Unexpected painted plots: %d", len(changed))
+	}
+
+	// Painting the same value again changes nothing
+	changed, _ = g.Paint(0, 2, Brush{Tool: BrushFeature, Value: "FEATURE_FOREST", Variety: "1", Size: 2})
+	if len(changed) != 0 {
+		t.Errorf("Repeated paint changed %d plots", len(changed))
+	}
+
+	// Rows are outside the map and don't wrap
+	changed, _ = g.Paint(2, 4, Brush{Tool: BrushRiver, Value: "2", Size: 2})
+	if len(changed) != 6 || !g.GetPlot(1, 4).IsWOfRiver || g.GetPlot(1, 4).RiverNSDirection != RiverFlowSouth {
+		t.Errorf("Unexpected river plots: %d", len(changed))
+	}
+
+	if _, err = g.Paint(0, 0, Brush{Tool: BrushTerrain}); err == nil {
+		t.Error("Removing terrain must fail")
+	}
+}
+
+func TestPlotGridFill(t *testing.T) {
+	m := newTestPaintMap(5, 3, false)
+	g := NewPlotGrid(m)
+
+	// The lake splits the grassland, only the left part is filled
+	changed, err := g.FloodFill(0, 0, Brush{Tool: BrushTerrain, Value: "TERRAIN_PLAINS"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 6 || g.GetPlot(1, 2).TerrainType != "TERRAIN_PLAINS" || g.GetPlot(3, 0).TerrainType != "TERRAIN_GRASS" {
+		t.Errorf("Unexpected filled plots: %d", len(changed))
+	}
+
+	selection := Selection{X1: 4, Y1: 2, X2: 2, Y2: 1}
+	changed, _ = g.FillSelection(selection, Brush{Tool: BrushPlotType, Value: "1"})
+	if len(changed) != 6 || g.GetPlot(3, 1).PlotType != PlotTypeHills || !selection.Contains(2, 2) || selection.Contains(1, 1) {
+		t.Errorf("Unexpected selection plots: %d", len(changed))
+	}
+	if len(g.GetPlotsIn(selection)) != 6 {
+		t.Errorf("GetPlotsIn returned %d plots", len(g.GetPlotsIn(selection)))
+	}
+
+	// Missing plots are created when painted
+	m.Plots = m.Plots[1:]
+	g = NewPlotGrid(m)
+	if changed, _ = g.Paint(0, 0, Brush{Tool: BrushTerrain, Value: "TERRAIN_COAST", Size: 1}); len(changed) != 1 || len(m.Plots) != 15 {
+		t.Errorf("Missing plot was not created: %d plots", len(m.Plots))
+	}
+}
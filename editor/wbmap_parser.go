@@ -2,6 +2,7 @@ package editor
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -9,6 +10,9 @@ import (
 	"strings"
 )
 
+// progressInterval is how often map parsing and generation report progress and check cancellation, in lines or plots
+const progressInterval = 1000
+
 const (
 	versionPrefix  = "Version="
 	defaultVersion = 11
@@ -43,8 +47,18 @@ const (
 	stateInsideUnit
 )
 
+// ParseWbMap parses the map in WorldBuilder format
 func ParseWbMap(reader io.Reader) (*WbMap, error) {
-	fileScanner := bufio.NewScanner(reader)
+	return ParseWbMapContext(context.Background(), reader, 0)
+}
+
+// ParseWbMapContext parses the map reporting read bytes of size (0 if unknown) to the reporter of the context
+// (see ProgressFrom). Parsing stops if the context is cancelled
+func ParseWbMapContext(ctx context.Context, reader io.Reader, size int64) (*WbMap, error) {
+	progress := ProgressFrom(ctx)
+	progress.Phase("Parsing map", int(size))
+	counter := &countingReader{reader: reader}
+	fileScanner := bufio.NewScanner(counter)
 	fileScanner.Split(bufio.ScanLines)
 
 	var parsed map[string]string
@@ -61,10 +75,16 @@ func ParseWbMap(reader io.Reader) (*WbMap, error) {
 	line := 0
 	parserState := stateGlobal
 
-	ConsoleWrite("Parsing map contents...")
+	Logger.Debug("Parsing map contents", "size", size)
 
 	for fileScanner.Scan() {
 		line++
+		if line%progressInterval == 0 {
+			if err = ctx.Err(); err != nil {
+				return nil, err
+			}
+			progress.SetDone(int(counter.read), "")
+		}
 		content := fileScanner.Text()
 		content = strings.Trim(content, " \t")
 		if content == "" || strings.HasPrefix(content, "#") {
@@ -258,9 +278,7 @@ func ParseWbMap(reader io.Reader) (*WbMap, error) {
 		}
 	}
 
-	ConsoleWrite("Loaded %d teams", len(wbMap.Teams))
-	ConsoleWrite("Loaded %d players (+ %d player placeholders)", realPlayers, emptyPlayers)
-	ConsoleWrite("Loaded %d plots", len(wbMap.Plots))
+	Logger.Info("Map parsed", "teams", len(wbMap.Teams), "players", realPlayers, "placeholders", emptyPlayers, "plots", len(wbMap.Plots))
 
 	if wbMap.Game == nil {
 		return nil, errors.New("no game info specified")
@@ -269,6 +287,18 @@ func ParseWbMap(reader io.Reader) (*WbMap, error) {
 	return wbMap, nil
 }
 
+// countingReader counts bytes read from the reader, used to report parsing progress
+type countingReader struct {
+	reader io.Reader
+	read   int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	return n, err
+}
+
 func createParserError(err string, line int, p ...any) error {
 	err = fmt.Sprintf(err, p...)
 
@@ -2,6 +2,7 @@ package editor
 
 import (
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -34,3 +35,42 @@ func TestParseWbMap(t *testing.T) {
 		t.Fatalf("Failed to parse plots section")
 	}
 }
+
+const testCityWbSave = `Version=11
+BeginGame
+EndGame
+BeginPlot
+	x=1,y=2
+	TerrainType=TERRAIN_GRASS
+	PlotType=2
+	BeginCity
+		CityOwner=0
+		CityName=Rome
+		CityPopulation=3
+		BuildingType=BUILDING_PALACE
+		BuildingType=BUILDING_MONUMENT
+		ReligionType=RELIGION_JUDAISM
+		HolyCityReligionType=RELIGION_JUDAISM
+		Player1Culture=10
+		Player0Culture=250
+	EndCity
+EndPlot
+`
+
+func TestParseCity(t *testing.T) {
+	wb, err := ParseWbMap(strings.NewReader(testCityWbSave))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	city := wb.Plots[0].Cities[0]
+	if len(city.BuildingType) != 2 || len(city.ReligionType) != 1 || len(city.HolyCityReligionType) != 1 || city.PlayerCulture[0] != 250 || city.PlayerCulture[1] != 10 {
+		t.Fatalf("Unexpected city %+v", city)
+	}
+
+	// Culture is written in player order, so the file doesn't change between saves
+	generated := string(city.ToWbFormat())
+	if !strings.Contains(generated, "BuildingType=BUILDING_MONUMENT") || strings.Index(generated, "Player0Culture=250") > strings.Index(generated, "Player1Culture=10") {
+		t.Errorf("Unexpected generated city:\n%s", generated)
+	}
+}
@@ -0,0 +1,312 @@
+package editor
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// StandardHandicapDefine is a global define with the default handicap of AI players
+const StandardHandicapDefine = "STANDARD_HANDICAP"
+
+// MaxCivPlayers is a number of player slots and teams supported by the game (MAX_CIV_PLAYERS), barbarians are not included
+const MaxCivPlayers = 18
+
+// IsEmpty returns true if the player slot is not used (the game writes NONE civilization for such slots)
+func (p *Player) IsEmpty() bool {
+	return p.CivType == "" || p.CivType == NonePlayer
+}
+
+// GetCivic returns the civic of the player for the civic option, or empty string if it's not set
+func (p *Player) GetCivic(civicOption string) string {
+	for i, option := range p.CivicOption {
+		if option == civicOption && i < len(p.Civic) {
+			return p.Civic[i]
+		}
+	}
+	return ""
+}
+
+// SetCivic sets the civic for the civic option. CivicOption and Civic are stored as pairs, empty civic removes the pair
+func (p *Player) SetCivic(civicOption string, civic string) {
+	for i, option := range p.CivicOption {
+		if option != civicOption || i >= len(p.Civic) {
+			continue
+		}
+
+		if civic == "" {
+			p.CivicOption = append(p.CivicOption[:i], p.CivicOption[i+1:]...)
+			p.Civic = append(p.Civic[:i], p.Civic[i+1:]...)
+		} else {
+			p.Civic[i] = civic
+		}
+		return
+	}
+
+	if civic != "" {
+		p.CivicOption = append(p.CivicOption, civicOption)
+		p.Civic = append(p.Civic, civic)
+	}
+}
+
+// GetAttitude returns the extra attitude of the player towards another player
+func (p *Player) GetAttitude(player uint) int {
+	for i, other := range p.AttitudePlayer {
+		if other == player && i < len(p.AttitudeExtra) {
+			return p.AttitudeExtra[i]
+		}
+	}
+	return 0
+}
+
+// SetAttitude sets the extra attitude towards another player. AttitudePlayer and AttitudeExtra are stored as pairs,
+// zero attitude removes the pair
+func (p *Player) SetAttitude(player uint, extra int) {
+	for i, other := range p.AttitudePlayer {
+		if other != player || i >= len(p.AttitudeExtra) {
+			continue
+		}
+
+		if extra == 0 {
+			p.AttitudePlayer = append(p.AttitudePlayer[:i], p.AttitudePlayer[i+1:]...)
+			p.AttitudeExtra = append(p.AttitudeExtra[:i], p.AttitudeExtra[i+1:]...)
+		} else {
+			p.AttitudeExtra[i] = extra
+		}
+		return
+	}
+
+	if extra != 0 {
+		p.AttitudePlayer = append(p.AttitudePlayer, player)
+		p.AttitudeExtra = append(p.AttitudeExtra, extra)
+	}
+}
+
+// GetPlayerName returns a name of the player slot for lists (e.g. "Player 0: Greece")
+func (m *WbMap) GetPlayerName(index int) string {
+	name := "Player " + strconv.Itoa(index)
+	if index < 0 || index >= len(m.Players) {
+		return name
+	}
+
+	if player := m.Players[index]; player.IsEmpty() {
+		name += " (empty)"
+	} else if player.CivShortDesc != "" {
+		name += ": " + player.CivShortDesc
+	} else {
+		name += ": " + player.CivType
+	}
+	return name
+}
+
+// AddPlayer takes the first empty player slot or adds a new one if all slots are used, and returns its index.
+// The player gets the first playable civilization not used by other players (see ApplyCivilization), so the slot is used
+// and the next call takes another one. The player gets its own team, so relations of other teams are not shared with it.
+// Error is returned if all MaxCivPlayers slots are used or there is no free civilization (e.g. game data is not loaded)
+func (m *WbMap) AddPlayer(r *GameRegistries) (int, error) {
+	index := len(m.Players)
+	for i, player := range m.Players {
+		if player.IsEmpty() {
+			index = i
+			break
+		}
+	}
+	if index == len(m.Players) && index >= MaxCivPlayers {
+		return 0, fmt.Errorf("all %d player slots are used", MaxCivPlayers)
+	}
+
+	civType := m.getFreeCivilization(r)
+	if civType == "" {
+		return 0, errors.New("all playable civilizations are used")
+	}
+	team, err := m.getFreeTeam(index)
+	if err != nil {
+		return 0, err
+	}
+	if index == len(m.Players) {
+		m.Players = append(m.Players, &Player{CivType: NonePlayer})
+	}
+
+	player := m.Players[index]
+	player.Team = team
+	player.PlayableCiv = true
+	r.ApplyCivilization(player, civType)
+	return index, nil
+}
+
+// RemovePlayer makes the player slot empty. Units and cities of the player are removed from the map,
+// as well as attitudes of other players towards it. If no other player is in the team, the team is reset too.
+// Slots are not deleted, so indexes of other players (used by units, cities and attitudes) stay the same
+func (m *WbMap) RemovePlayer(index int) error {
+	if index < 0 || index >= len(m.Players) {
+		return fmt.Errorf("player %d not found", index)
+	}
+
+	player := m.Players[index]
+	for _, plot := range m.Plots {
+		var units []*Unit
+		for _, unit := range plot.Units {
+			if unit.UnitOwner != index {
+				units = append(units, unit)
+			}
+		}
+		var cities []*City
+		for _, city := range plot.Cities {
+			if city.CityOwner != uint(index) {
+				cities = append(cities, city)
+			}
+		}
+		plot.Units, plot.Cities = units, cities
+	}
+
+	for _, other := range m.Players {
+		other.SetAttitude(uint(index), 0)
+	}
+
+	m.Players[index] = &Player{
+		CivType:    NonePlayer,
+		LeaderType: NonePlayer,
+		Color:      NonePlayer,
+		ArtStyle:   NonePlayer,
+		Team:       player.Team,
+		Handicap:   player.Handicap,
+	}
+	if !m.isTeamUsed(player.Team, index) {
+		m.resetTeam(player.Team)
+	}
+
+	return nil
+}
+
+// isTeamUsed returns true if any non-empty player (except the one with the index) is in the team
+func (m *WbMap) isTeamUsed(team uint, except int) bool {
+	for i, player := range m.Players {
+		if i != except && player.Team == team && !player.IsEmpty() {
+			return true
+		}
+	}
+	return false
+}
+
+// getFreeCivilization returns the first playable civilization in XML order not used by players,
+// or empty string if all are used
+func (m *WbMap) getFreeCivilization(r *GameRegistries) string {
+	for _, choice := range GetChoices(r, r.CivilizationInfos, OrderXML) {
+		used := slices.ContainsFunc(m.Players, func(p *Player) bool { return p.CivType == choice.Type })
+		if r.CivilizationInfos[choice.Type].Playable && !used {
+			return choice.Type
+		}
+	}
+	return ""
+}
+
+// CheckPlayerTeam returns an error if the team of the player doesn't exist or another player uses it
+func (m *WbMap) CheckPlayerTeam(index int) error {
+	team := m.Players[index].Team
+	if m.GetTeam(team) == nil {
+		return fmt.Errorf("team %d not found", team)
+	}
+	for i, player := range m.Players {
+		if i != index && player.Team == team && !player.IsEmpty() {
+			return fmt.Errorf("team %d is used by %s", team, m.GetPlayerName(i))
+		}
+	}
+	return nil
+}
+
+// getFreeTeam returns a team without players for the player slot (which may be a new one), preferring its current team.
+// A new team is added if all are used, error is returned if there are MaxCivPlayers teams already
+func (m *WbMap) getFreeTeam(index int) (uint, error) {
+	if index < len(m.Players) {
+		current := m.Players[index].Team
+		if m.GetTeam(current) != nil && !m.isTeamUsed(current, index) {
+			return current, nil
+		}
+	}
+
+	var next uint
+	for _, team := range m.Teams {
+		if !m.isTeamUsed(team.TeamID, index) {
+			return team.TeamID, nil
+		}
+		if team.TeamID >= next {
+			next = team.TeamID + 1
+		}
+	}
+	if next >= MaxCivPlayers {
+		return 0, errors.New("all teams are used")
+	}
+
+	m.Teams = append(m.Teams, &Team{TeamID: next, ContactWithTeam: []uint{next}})
+	return next, nil
+}
+
+// resetTeam removes all relations, techs and projects of the team, relations of other teams with it are removed as well
+func (m *WbMap) resetTeam(id uint) {
+	team := m.GetTeam(id)
+	if team == nil {
+		return
+	}
+
+	for _, other := range m.Teams {
+		for _, relation := range TeamRelations {
+			list := other.getRelationList(relation)
+			*list = RemoveFromSlice(*list, id)
+		}
+	}
+
+	// The game writes contact of the team with itself
+	*team = Team{TeamID: id, ContactWithTeam: []uint{id}}
+}
+
+// ApplyCivilization sets the civilization of the player with its default names, color, art style, leader and civics.
+// Leader is kept if it's available for the civilization
+func (r *GameRegistries) ApplyCivilization(p *Player, civType string) {
+	p.CivType = civType
+	civ, ok := r.CivilizationInfos[civType]
+	if !ok {
+		return
+	}
+
+	p.CivDesc = r.GetLangString(civ.Description)
+	p.CivShortDesc = r.GetLangString(civ.ShortDescription)
+	p.CivAdjective = r.GetLangString(civ.Adjective)
+	p.Color = civ.DefaultPlayerColor
+	p.ArtStyle = civ.ArtStyle
+	if !IsInSlice(civ.Leaders, p.LeaderType) && len(civ.Leaders) > 0 {
+		r.ApplyLeader(p, civ.Leaders[0])
+	}
+	if p.Handicap == "" || p.Handicap == NonePlayer {
+		p.Handicap = r.GlobalStringDefines[StandardHandicapDefine]
+	}
+
+	p.CivicOption, p.Civic = nil, nil
+	for _, civic := range civ.InitialCivics {
+		if info, ok := r.CivicInfos[civic]; ok {
+			p.SetCivic(info.CivicOption, civic)
+		}
+	}
+}
+
+// ApplyLeader sets the leader of the player with its default name
+func (r *GameRegistries) ApplyLeader(p *Player, leaderType string) {
+	p.LeaderType = leaderType
+	if leader, ok := r.LeaderHeadInfos[leaderType]; ok {
+		p.LeaderName = r.GetLangString(leader.Description)
+	}
+}
+
+// GetCityNames returns default city names of the civilization in the configured language
+func (r *GameRegistries) GetCityNames(civType string) []string {
+	civ, ok := r.CivilizationInfos[civType]
+	if !ok {
+		return nil
+	}
+
+	names := make([]string, 0, len(civ.Cities))
+	for _, city := range civ.Cities {
+		names = append(names, r.GetLangString(city))
+	}
+	return names
+}
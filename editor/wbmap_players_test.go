@@ -0,0 +1,119 @@
+package editor
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// newTestCivRegistries returns registries with playable civilizations CIVILIZATION_0 and so on in XML order,
+// the last one is not playable
+func newTestCivRegistries(count int) *GameRegistries {
+	r := NewGameRegistries()
+	for i := 0; i < count; i++ {
+		civType := "CIVILIZATION_" + strconv.Itoa(i)
+		r.CivilizationInfos[civType] = &CivilizationInfo{TypeInfo: TypeInfo{Type: civType}, Playable: i < count-1}
+		r.XmlTypeOrder[civType] = i
+	}
+	return r
+}
+
+func TestPlayerPairs(t *testing.T) {
+	p := &Player{}
+	p.SetCivic("CIVICOPTION_GOVERNMENT", "CIVIC_DESPOTISM")
+	p.SetCivic("CIVICOPTION_LEGAL", "CIVIC_BARBARISM")
+	p.SetCivic("CIVICOPTION_GOVERNMENT", "CIVIC_MONARCHY")
+	if p.GetCivic("CIVICOPTION_GOVERNMENT") != "CIVIC_MONARCHY" || len(p.Civic) != 2 {
+		t.Errorf("SetCivic failed: %v %v", p.CivicOption, p.Civic)
+	}
+
+	p.SetCivic("CIVICOPTION_GOVERNMENT", "")
+	if !slices.Equal(p.CivicOption, []string{"CIVICOPTION_LEGAL"}) || !slices.Equal(p.Civic, []string{"CIVIC_BARBARISM"}) {
+		t.Errorf("Removing civic failed: %v %v", p.CivicOption, p.Civic)
+	}
+
+	p.SetAttitude(3, -2)
+	p.SetAttitude(5, 4)
+	p.SetAttitude(3, 0)
+	if p.GetAttitude(5) != 4 || p.GetAttitude(3) != 0 || len(p.AttitudePlayer) != 1 || len(p.AttitudeExtra) != 1 {
+		t.Errorf("SetAttitude failed: %v %v", p.AttitudePlayer, p.AttitudeExtra)
+	}
+}
+
+func TestAddRemovePlayer(t *testing.T) {
+	m := &WbMap{
+		Teams: []*Team{{TeamID: 0, ContactWithTeam: []uint{0, 1}, AtWar: []uint{1}}, {TeamID: 1, ContactWithTeam: []uint{0, 1}, AtWar: []uint{0}}},
+		Players: []*Player{
+			{CivType: "CIVILIZATION_GREECE", Team: 0},
+			{CivType: "CIVILIZATION_ROME", Team: 1, AttitudePlayer: []uint{0}, AttitudeExtra: []int{-1}},
+		},
+		Plots: []*Plot{{Units: []*Unit{{UnitOwner: 0}, {UnitOwner: 1}}, Cities: []*City{{CityOwner: 0}}}},
+	}
+
+	r := newTestCivRegistries(MaxCivPlayers + 2)
+	if _, err := m.AddPlayer(NewGameRegistries()); err == nil || len(m.Players) != 2 {
+		t.Error("Player must not be added without civilizations")
+	}
+
+	// No empty slots, so a new slot and a new team are added
+	if index, err := m.AddPlayer(r); err != nil || index != 2 || m.Players[2].Team != 2 || m.GetTeam(2) == nil || m.Players[2].CivType != "CIVILIZATION_0" {
+		t.Fatalf("AddPlayer failed: index %d, %+v", index, m.Players[index])
+	}
+	// The added player uses its slot and civilization, so the next one takes others
+	if index, err := m.AddPlayer(r); err != nil || index != 3 || m.Players[3].CivType != "CIVILIZATION_1" || m.Players[3].Team != 3 {
+		t.Fatalf("AddPlayer must not reuse the added slot: index %d, %+v", index, m.Players[index])
+	}
+
+	if err := m.RemovePlayer(0); err != nil {
+		t.Fatal(err)
+	}
+	if !m.Players[0].IsEmpty() || len(m.Plots[0].Units) != 1 || len(m.Plots[0].Cities) != 0 || len(m.Players[1].AttitudePlayer) != 0 {
+		t.Errorf("RemovePlayer left player data: %+v, %+v", m.Players[0], m.Plots[0])
+	}
+	if m.Teams[1].HasRelation(RelationWar, 0) || m.Teams[1].HasRelation(RelationContact, 0) || len(m.Teams[0].AtWar) != 0 {
+		t.Errorf("RemovePlayer left team relations: %+v, %+v", m.Teams[0], m.Teams[1])
+	}
+
+	// The empty slot is reused with its team
+	if index, _ := m.AddPlayer(r); index != 0 || m.Players[0].Team != 0 || len(m.Teams) != 4 {
+		t.Errorf("AddPlayer did not reuse empty slot: index %d, %d teams", index, len(m.Teams))
+	}
+
+	if m.RemovePlayer(5) == nil {
+		t.Error("Removing unknown player must fail")
+	}
+
+	// The game supports MaxCivPlayers slots
+	for len(m.Players) < MaxCivPlayers {
+		if _, err := m.AddPlayer(r); err != nil {
+			t.Fatalf("AddPlayer failed with %d players: %v", len(m.Players), err)
+		}
+	}
+	if _, err := m.AddPlayer(r); err == nil || len(m.Players) != MaxCivPlayers || len(m.Teams) != MaxCivPlayers {
+		t.Errorf("AddPlayer must fail when all slots are used: %v, %d players, %d teams", err, len(m.Players), len(m.Teams))
+	}
+}
+
+func TestCheckPlayerTeam(t *testing.T) {
+	m := &WbMap{
+		Teams:   []*Team{{TeamID: 0}, {TeamID: 1}, {TeamID: 2}},
+		Players: []*Player{{CivType: "CIVILIZATION_GREECE", Team: 0}, {CivType: "CIVILIZATION_ROME", Team: 1}, {CivType: NonePlayer, Team: 2}},
+	}
+	if err := m.CheckPlayerTeam(0); err != nil {
+		t.Errorf("Own team must be accepted: %v", err)
+	}
+
+	// Team of another player is rejected, team of an empty slot is free
+	m.Players[0].Team = 1
+	if err := m.CheckPlayerTeam(0); err == nil {
+		t.Error("Team of another player must be rejected")
+	}
+	m.Players[0].Team = 2
+	if err := m.CheckPlayerTeam(0); err != nil {
+		t.Errorf("Team of empty slot must be accepted: %v", err)
+	}
+	m.Players[0].Team = 5
+	if err := m.CheckPlayerTeam(0); err == nil {
+		t.Error("Missing team must be rejected")
+	}
+}
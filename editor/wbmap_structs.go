@@ -2,8 +2,10 @@ package editor
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 )
 
@@ -65,6 +67,16 @@ func (m *WbMap) Unpack(packed map[string]string) error {
 }
 
 func (m *WbMap) ToWbFormat() []byte {
+	data, _ := m.ToWbFormatContext(context.Background())
+	return data
+}
+
+// ToWbFormatContext generates the map reporting written plots to the reporter of the context (see ProgressFrom).
+// Generation stops if the context is cancelled
+func (m *WbMap) ToWbFormatContext(ctx context.Context) ([]byte, error) {
+	progress := ProgressFrom(ctx)
+	progress.Phase("Generating map", len(m.Plots))
+
 	buf := bytes.NewBuffer(nil)
 	buf.Write([]byte(fmt.Sprintf("Version=%d\n", m.Version)))
 	buf.Write(m.Game.ToWbFormat())
@@ -75,10 +87,16 @@ func (m *WbMap) ToWbFormat() []byte {
 		buf.Write(player.ToWbFormat())
 	}
 	buf.Write(m.Map.ToWbFormat())
-	for _, plot := range m.Plots {
+	for i, plot := range m.Plots {
+		if i%progressInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
 		buf.Write(plot.ToWbFormat())
+		progress.Step("")
 	}
-	return buf.Bytes()
+	return buf.Bytes(), nil
 }
 
 /*
@@ -852,13 +870,13 @@ type City struct {
 	ProductionProcess string
 	// BuildingType: the buildings that the city already has at game start.
 	// Any number of BuildingTypes can be defined on separate lines. These values are defined in CIV4BuildingInfos.xml
-	BuildingType string
+	BuildingType []string
 	// The religions that the city has at game start
 	// Any number of religions can be defined on separate lines. These values are defined in CIV4ReligionInfos.xml
-	ReligionType string
+	ReligionType []string
 	// HolyCityReligionType: the Holy City of the defined religions. Any number of these can be defined on separate lines.
 	// These values are defined in CIV4ReligionInfos.xml
-	HolyCityReligionType string
+	HolyCityReligionType []string
 	// ScriptData: any scripts assigned to the city. This analysis does not go into these scripts.
 	ScriptData string
 	// The starting culture that the city has. Key is the player number and value is the amount of culture.
@@ -867,7 +885,7 @@ type City struct {
 	PlayerCulture map[uint]uint64
 }
 
-var playerCultureRegex = regexp.MustCompile("`Player([0-9]+)Culture`")
+var playerCultureRegex = regexp.MustCompile(`^Player([0-9]+)Culture$`)
 
 func (c *City) Unpack(packed map[string]string) error {
 	for k, v := range packed {
@@ -887,6 +905,9 @@ func (c *City) Unpack(packed map[string]string) error {
 				return err
 			}
 
+			if c.PlayerCulture == nil {
+				c.PlayerCulture = make(map[uint]uint64)
+			}
 			c.PlayerCulture[uint(i)] = uint64(numValue)
 			continue
 		}
@@ -915,11 +936,11 @@ func (c *City) Unpack(packed map[string]string) error {
 		case "ProductionProcess":
 			c.ProductionProcess = v
 		case "BuildingType":
-			c.BuildingType = v
+			c.BuildingType = append(c.BuildingType, v)
 		case "ReligionType":
-			c.ReligionType = v
+			c.ReligionType = append(c.ReligionType, v)
 		case "HolyCityReligionType":
-			c.HolyCityReligionType = v
+			c.HolyCityReligionType = append(c.HolyCityReligionType, v)
 		case "ScriptData":
 			c.ScriptData = v
 		default:
@@ -939,12 +960,17 @@ func (c *City) AddAsSubsection(generator *SimpleGenerator) {
 	generator.AddKeyValueString("ProductionBuilding", c.ProductionBuilding)
 	generator.AddKeyValueString("ProductionProject", c.ProductionProject)
 	generator.AddKeyValueString("ProductionProcess", c.ProductionProcess)
-	generator.AddKeyValueString("BuildingType", c.BuildingType)
-	generator.AddKeyValueString("ReligionType", c.ReligionType)
-	generator.AddKeyValueString("HolyCityReligionType", c.HolyCityReligionType)
+	generator.AddKeyValueArray("BuildingType", c.BuildingType)
+	generator.AddKeyValueArray("ReligionType", c.ReligionType)
+	generator.AddKeyValueArray("HolyCityReligionType", c.HolyCityReligionType)
 	generator.AddKeyValueString("ScriptData", c.ScriptData)
-	for k, v := range c.PlayerCulture {
-		generator.AddKeyValueUint(fmt.Sprintf("Player%vCulture", k), v)
+	players := make([]uint, 0, len(c.PlayerCulture))
+	for player := range c.PlayerCulture {
+		players = append(players, player)
+	}
+	sort.Slice(players, func(i, j int) bool { return players[i] < players[j] })
+	for _, player := range players {
+		generator.AddKeyValueUint(fmt.Sprintf("Player%dCulture", player), c.PlayerCulture[player])
 	}
 	generator.EndSection()
 }
@@ -968,7 +994,7 @@ type Unit struct {
 	Experience int
 	// The promotions this unit has. You assign as many PromotionType lines as Levels given to the unit above.
 	// These values are defined in CIV4PromotionInfos.xml.
-	PromotionType string
+	PromotionType []string
 	// The usage of the unit for the AI. Assigning the correct UnitAIType for a unit
 	// is important as it tells the AI what the unit is used for.
 	// EG: Settler units should get UnitAIType=UNITAI_SETTLE
@@ -1004,7 +1030,7 @@ func (u *Unit) Unpack(packed map[string]string) error {
 			}
 			u.Experience = i
 		case "PromotionType":
-			u.PromotionType = v
+			u.PromotionType = append(u.PromotionType, v)
 		case "UnitAIType":
 			u.UnitAIType = v
 		case "Damage":
@@ -1031,7 +1057,7 @@ func (u *Unit) AddAsSubsection(generator *SimpleGenerator) {
 	generator.StartSection(BeginUnit, EndUnit)
 	generator.AddCommaSeparatedValues(fmt.Sprintf("UnitType=%s", u.UnitType), fmt.Sprintf("UnitOwner=%d", u.UnitOwner))
 	generator.AddCommaSeparatedValues(fmt.Sprintf("Level=%d", u.Level), fmt.Sprintf("Experience=%d", u.Experience))
-	generator.AddKeyValueString("PromotionType", u.PromotionType)
+	generator.AddKeyValueArray("PromotionType", u.PromotionType)
 	generator.AddKeyValueString("UnitAIType", u.UnitAIType)
 	generator.AddKeyValueUint("Damage", uint64(u.Damage))
 	generator.AddKeyValueInt("FacingDirection", u.FacingDirection)
@@ -0,0 +1,199 @@
+package editor
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// TeamRelation is a diplomatic relation between teams, its value is the key of the Team list in WorldBuilder format
+type TeamRelation string
+
+const (
+	RelationContact           TeamRelation = "ContactWithTeam"
+	RelationWar               TeamRelation = "AtWar"
+	RelationPermanentWarPeace TeamRelation = "PermanentWarPeace"
+	RelationOpenBorders       TeamRelation = "OpenBordersWithTeam"
+	RelationDefensivePact     TeamRelation = "DefensivePactWithTeam"
+)
+
+// TeamRelations contains all relations in the order they are shown in the editor
+var TeamRelations = []TeamRelation{RelationContact, RelationWar, RelationPermanentWarPeace, RelationOpenBorders, RelationDefensivePact}
+
+// TeamRelationNames are human-readable names of relations
+var TeamRelationNames = map[TeamRelation]string{
+	RelationContact:           "Contact",
+	RelationWar:               "War",
+	RelationPermanentWarPeace: "Permanent war/peace",
+	RelationOpenBorders:       "Open borders",
+	RelationDefensivePact:     "Defensive pact",
+}
+
+// GetTeam returns the team with the ID or nil if there is no such team
+func (m *WbMap) GetTeam(id uint) *Team {
+	for _, team := range m.Teams {
+		if team.TeamID == id {
+			return team
+		}
+	}
+	return nil
+}
+
+// GetTeamName returns a name of the team for lists, with civilizations of its players (e.g. "Team 0: Rome, Greece")
+func (m *WbMap) GetTeamName(id uint) string {
+	name := "Team " + strconv.Itoa(int(id))
+	separator := ": "
+	for _, player := range m.Players {
+		if player.Team == id && player.CivShortDesc != "" {
+			name += separator + player.CivShortDesc
+			separator = ", "
+		}
+	}
+	return name
+}
+
+// GetTeamChoices returns all teams of the map as choices, team ID is used as type
+func (m *WbMap) GetTeamChoices() []Choice {
+	choices := make([]Choice, 0, len(m.Teams))
+	for _, team := range m.Teams {
+		choices = append(choices, Choice{Type: strconv.Itoa(int(team.TeamID)), Description: m.GetTeamName(team.TeamID)})
+	}
+	return choices
+}
+
+// getRelationList returns a pointer to the list of teams the team has the relation with
+func (t *Team) getRelationList(relation TeamRelation) *[]uint {
+	switch relation {
+	case RelationContact:
+		return &t.ContactWithTeam
+	case RelationWar:
+		return &t.AtWar
+	case RelationPermanentWarPeace:
+		return &t.PermanentWarPeace
+	case RelationOpenBorders:
+		return &t.OpenBordersWithTeam
+	case RelationDefensivePact:
+		return &t.DefensivePactWithTeam
+	}
+	return nil
+}
+
+// HasRelation returns true if the team has the relation with another team
+func (t *Team) HasRelation(relation TeamRelation, other uint) bool {
+	list := t.getRelationList(relation)
+	return list != nil && IsInSlice(*list, other)
+}
+
+// SetTeamRelation sets or removes the relation between two teams, keeping the map consistent as the game expects:
+// all relations except permanent war/peace are mutual, war and agreements require contact,
+// and teams at war cannot have open borders or defensive pact
+func (m *WbMap) SetTeamRelation(relation TeamRelation, from uint, to uint, value bool) error {
+	first, second := m.GetTeam(from), m.GetTeam(to)
+	if first == nil || second == nil {
+		return fmt.Errorf("team %d or %d not found", from, to)
+	}
+	if from == to {
+		return fmt.Errorf("team %d cannot have relation with itself", from)
+	}
+	if first.getRelationList(relation) == nil {
+		return fmt.Errorf("unknown relation %s", relation)
+	}
+
+	set := func(relation TeamRelation, value bool) {
+		list := first.getRelationList(relation)
+		*list = SwitchInSlice(value, *list, to)
+		if relation != RelationPermanentWarPeace {
+			list = second.getRelationList(relation)
+			*list = SwitchInSlice(value, *list, from)
+		}
+	}
+
+	set(relation, value)
+	switch {
+	case relation == RelationContact && !value:
+		set(RelationWar, false)
+		set(RelationOpenBorders, false)
+		set(RelationDefensivePact, false)
+	case relation == RelationWar && value:
+		set(RelationContact, true)
+		set(RelationOpenBorders, false)
+		set(RelationDefensivePact, false)
+	case (relation == RelationOpenBorders || relation == RelationDefensivePact) && value:
+		set(RelationContact, true)
+		set(RelationWar, false)
+	}
+
+	return nil
+}
+
+// IsTechAvailable returns true if prerequisites of the tech are known: all AND prerequisites and one of OR prerequisites.
+// Unknown techs (e.g. from another mod) are always available
+func (r *GameRegistries) IsTechAvailable(techs []string, tech string) bool {
+	info, ok := r.TechInfos[tech]
+	if !ok {
+		return true
+	}
+
+	for _, prereq := range info.AndPrereqs {
+		if !IsInSlice(techs, prereq) {
+			return false
+		}
+	}
+	for _, prereq := range info.OrPrereqs {
+		if IsInSlice(techs, prereq) {
+			return true
+		}
+	}
+	return len(info.OrPrereqs) == 0
+}
+
+// AddTechWithPrereqs adds the tech and all its missing prerequisites to the list.
+// If none of OR prerequisites is known, the first one is added
+func (r *GameRegistries) AddTechWithPrereqs(techs []string, tech string) []string {
+	if IsInSlice(techs, tech) {
+		return techs
+	}
+
+	if info, ok := r.TechInfos[tech]; ok {
+		// The tech is added before prerequisites to stop on circular references in broken mods
+		techs = append(techs, tech)
+		for _, prereq := range info.AndPrereqs {
+			techs = r.AddTechWithPrereqs(techs, prereq)
+		}
+		if len(info.OrPrereqs) > 0 && !r.IsTechAvailable(techs, tech) {
+			techs = r.AddTechWithPrereqs(techs, info.OrPrereqs[0])
+		}
+		return techs
+	}
+
+	return append(techs, tech)
+}
+
+// RemoveTechWithDependents removes the tech and all techs that are not available without it.
+// Only techs requiring removed ones are checked, so other techs are kept even if their prerequisites are missing
+func (r *GameRegistries) RemoveTechWithDependents(techs []string, tech string) []string {
+	result := RemoveFromSlice(slices.Clone(techs), tech)
+	for removed := []string{tech}; len(removed) > 0; {
+		var dependents []string
+		for _, t := range result {
+			if r.requiresAnyTech(t, removed) && !r.IsTechAvailable(result, t) {
+				dependents = append(dependents, t)
+			}
+		}
+		for _, t := range dependents {
+			result = RemoveFromSlice(result, t)
+		}
+		removed = dependents
+	}
+	return result
+}
+
+// requiresAnyTech returns true if one of the techs is an AND or OR prerequisite of the tech
+func (r *GameRegistries) requiresAnyTech(tech string, techs []string) bool {
+	info, ok := r.TechInfos[tech]
+	if !ok {
+		return false
+	}
+	known := func(prereq string) bool { return IsInSlice(techs, prereq) }
+	return slices.ContainsFunc(info.AndPrereqs, known) || slices.ContainsFunc(info.OrPrereqs, known)
+}
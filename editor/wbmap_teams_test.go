@@ -0,0 +1,75 @@
+package editor
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSetTeamRelation(t *testing.T) {
+	m := &WbMap{Teams: []*Team{{TeamID: 0}, {TeamID: 1}, {TeamID: 2}}}
+
+	if err := m.SetTeamRelation(RelationOpenBorders, 0, 1, true); err != nil {
+		t.Fatal(err)
+	}
+	if !m.Teams[1].HasRelation(RelationOpenBorders, 0) || !m.Teams[1].HasRelation(RelationContact, 0) {
+		t.Errorf("Open borders must be mutual and imply contact: %+v", m.Teams[1])
+	}
+
+	// War cancels agreements of both teams
+	_ = m.SetTeamRelation(RelationWar, 1, 0, true)
+	if !m.Teams[0].HasRelation(RelationWar, 1) || m.Teams[0].HasRelation(RelationOpenBorders, 1) || m.Teams[1].HasRelation(RelationOpenBorders, 0) {
+		t.Errorf("Unexpected relations after war: %+v, %+v", m.Teams[0], m.Teams[1])
+	}
+
+	// Permanent war/peace is set for one team only
+	_ = m.SetTeamRelation(RelationPermanentWarPeace, 0, 2, true)
+	if !m.Teams[0].HasRelation(RelationPermanentWarPeace, 2) || m.Teams[2].HasRelation(RelationPermanentWarPeace, 0) {
+		t.Errorf("Permanent war/peace must be directional: %+v, %+v", m.Teams[0], m.Teams[2])
+	}
+
+	// Losing contact removes war
+	_ = m.SetTeamRelation(RelationContact, 0, 1, false)
+	if len(m.Teams[0].AtWar) != 0 || len(m.Teams[1].AtWar) != 0 || len(m.Teams[1].ContactWithTeam) != 0 {
+		t.Errorf("Unexpected relations after losing contact: %+v, %+v", m.Teams[0], m.Teams[1])
+	}
+
+	if m.SetTeamRelation(RelationWar, 0, 0, true) == nil || m.SetTeamRelation(RelationWar, 0, 5, true) == nil {
+		t.Error("Relation with itself or unknown team must fail")
+	}
+}
+
+func TestTechPrereqs(t *testing.T) {
+	r := NewGameRegistries()
+	r.TechInfos["TECH_MINING"] = &TechInfo{TypeInfo: TypeInfo{Type: "TECH_MINING"}}
+	r.TechInfos["TECH_HUNTING"] = &TechInfo{TypeInfo: TypeInfo{Type: "TECH_HUNTING"}}
+	r.TechInfos["TECH_AGRICULTURE"] = &TechInfo{TypeInfo: TypeInfo{Type: "TECH_AGRICULTURE"}}
+	r.TechInfos["TECH_WHEEL"] = &TechInfo{TypeInfo: TypeInfo{Type: "TECH_WHEEL"}, OrPrereqs: []string{"TECH_AGRICULTURE", "TECH_HUNTING"}}
+	r.TechInfos["TECH_BRONZE_WORKING"] = &TechInfo{TypeInfo: TypeInfo{Type: "TECH_BRONZE_WORKING"}, AndPrereqs: []string{"TECH_MINING"}}
+	r.TechInfos["TECH_MONARCHY"] = &TechInfo{TypeInfo: TypeInfo{Type: "TECH_MONARCHY"}, AndPrereqs: []string{"TECH_WHEEL", "TECH_BRONZE_WORKING"}}
+
+	techs := r.AddTechWithPrereqs([]string{"TECH_HUNTING"}, "TECH_MONARCHY")
+	slices.Sort(techs)
+	expected := []string{"TECH_BRONZE_WORKING", "TECH_HUNTING", "TECH_MINING", "TECH_MONARCHY", "TECH_WHEEL"}
+	if !slices.Equal(techs, expected) {
+		t.Errorf("AddTechWithPrereqs: got %v, expected %v", techs, expected)
+	}
+
+	// The first OR prerequisite is added if none is known
+	techs = r.AddTechWithPrereqs(nil, "TECH_WHEEL")
+	if !slices.Contains(techs, "TECH_AGRICULTURE") || slices.Contains(techs, "TECH_HUNTING") {
+		t.Errorf("AddTechWithPrereqs with OR prerequisites: %v", techs)
+	}
+
+	techs = r.RemoveTechWithDependents([]string{"TECH_HUNTING", "TECH_MINING", "TECH_WHEEL", "TECH_BRONZE_WORKING", "TECH_MONARCHY", "TECH_UNKNOWN"}, "TECH_MINING")
+	expected = []string{"TECH_HUNTING", "TECH_WHEEL", "TECH_UNKNOWN"}
+	if !slices.Equal(techs, expected) {
+		t.Errorf("RemoveTechWithDependents: got %v, expected %v", techs, expected)
+	}
+
+	// Wheel misses its prerequisites already, but it doesn't depend on the removed tech, so it's kept
+	techs = r.RemoveTechWithDependents([]string{"TECH_WHEEL", "TECH_MINING", "TECH_BRONZE_WORKING"}, "TECH_MINING")
+	expected = []string{"TECH_WHEEL"}
+	if !slices.Equal(techs, expected) {
+		t.Errorf("RemoveTechWithDependents with unrelated techs: got %v, expected %v", techs, expected)
+	}
+}
@@ -0,0 +1,150 @@
+package editor
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Unit domains (see UnitInfo.Domain)
+const (
+	DomainLand = "DOMAIN_LAND"
+	DomainSea  = "DOMAIN_SEA"
+)
+
+// FacingDirections are names of unit facing directions (see Unit.FacingDirection), index is the value
+var FacingDirections = []string{"North", "North-east", "East", "South-east", "South", "South-west", "West", "North-west"}
+
+// NewUnit returns a unit of the type with its default AI
+func (r *GameRegistries) NewUnit(unitType string, owner int) *Unit {
+	unit := &Unit{UnitType: unitType, UnitOwner: owner}
+	if info, ok := r.UnitInfos[unitType]; ok {
+		unit.UnitAIType = info.DefaultUnitAI
+	}
+	return unit
+}
+
+// CheckPromotion returns an error if the unit cannot have the promotion: its combat type is not allowed
+// or prerequisite promotions are missing. Unknown units and promotions (e.g. from another mod) are not checked
+func (r *GameRegistries) CheckPromotion(u *Unit, promotion string) error {
+	info, ok := r.PromotionInfos[promotion]
+	if !ok {
+		return nil
+	}
+
+	if !r.IsPromotionForUnit(u.UnitType, promotion) {
+		return fmt.Errorf("%s is not available for %s", promotion, u.UnitType)
+	}
+	if info.PrereqPromotion != "" && !IsInSlice(u.PromotionType, info.PrereqPromotion) {
+		return fmt.Errorf("%s requires %s", promotion, info.PrereqPromotion)
+	}
+	if len(info.PrereqOrPromotions) == 0 {
+		return nil
+	}
+	for _, prereq := range info.PrereqOrPromotions {
+		if IsInSlice(u.PromotionType, prereq) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s requires one of %v", promotion, info.PrereqOrPromotions)
+}
+
+// IsPromotionForUnit returns true if the promotion is available for the unit combat type (e.g. UNITCOMBAT_MELEE)
+func (r *GameRegistries) IsPromotionForUnit(unitType string, promotion string) bool {
+	info, ok := r.PromotionInfos[promotion]
+	unit, known := r.UnitInfos[unitType]
+	if !ok || !known || len(info.UnitCombats) == 0 {
+		return true
+	}
+	return IsInSlice(info.UnitCombats, unit.Combat)
+}
+
+// AddPromotion adds the promotion to the unit if it's available (see CheckPromotion)
+func (r *GameRegistries) AddPromotion(u *Unit, promotion string) error {
+	if err := r.CheckPromotion(u, promotion); err != nil {
+		return err
+	}
+
+	u.PromotionType = AddToSlice(u.PromotionType, promotion)
+	return nil
+}
+
+// RemovePromotion removes the promotion and all promotions that are not available without it
+func (r *GameRegistries) RemovePromotion(u *Unit, promotion string) {
+	u.PromotionType = RemoveFromSlice(u.PromotionType, promotion)
+	for removed := true; removed; {
+		removed = false
+		for _, p := range u.PromotionType {
+			if r.CheckPromotion(u, p) != nil {
+				u.PromotionType = RemoveFromSlice(u.PromotionType, p)
+				removed = true
+				break
+			}
+		}
+	}
+}
+
+// CheckUnitPlot returns an error if the unit cannot be on the plot: land units cannot be on water,
+// and sea units cannot be on land outside cities
+func (r *GameRegistries) CheckUnitPlot(unitType string, plot *Plot) error {
+	info, ok := r.UnitInfos[unitType]
+	if !ok {
+		return nil
+	}
+
+	if info.Domain == DomainLand && plot.PlotType == PlotTypeOcean {
+		return fmt.Errorf("land unit %s cannot be on water plot %d,%d", unitType, plot.X, plot.Y)
+	}
+	if info.Domain == DomainSea && plot.PlotType != PlotTypeOcean && len(plot.Cities) == 0 {
+		return fmt.Errorf("sea unit %s cannot be on land plot %d,%d", unitType, plot.X, plot.Y)
+	}
+	return nil
+}
+
+// AddUnit adds a new unit of the type and owner to the plot
+func (g *PlotGrid) AddUnit(r *GameRegistries, x, y int, unitType string, owner int) (*Unit, error) {
+	plot := g.GetPlot(x, y)
+	if plot == nil {
+		return nil, fmt.Errorf("plot %d,%d not found", x, y)
+	}
+	if err := r.CheckUnitPlot(unitType, plot); err != nil {
+		return nil, err
+	}
+
+	unit := r.NewUnit(unitType, owner)
+	plot.Units = append(plot.Units, unit)
+	return unit, nil
+}
+
+// RemoveUnit removes the unit from the plot by its index in the stack
+func (g *PlotGrid) RemoveUnit(x, y int, index int) error {
+	plot := g.GetPlot(x, y)
+	if plot == nil || index < 0 || index >= len(plot.Units) {
+		return fmt.Errorf("unit %d not found on plot %d,%d", index, x, y)
+	}
+
+	// The slice is cloned, its backing array may be kept by a history snapshot
+	plot.Units = slices.Delete(slices.Clone(plot.Units), index, index+1)
+	return nil
+}
+
+// AddStartingUnits adds units of the types to starting plots of all players (e.g. settler and warrior).
+// Players with random start location are skipped, errors of other players are joined. Returns a number of added units
+func (g *PlotGrid) AddStartingUnits(r *GameRegistries, unitTypes []string) (int, error) {
+	var errs []error
+	added := 0
+	for i, player := range g.Map.Players {
+		if player.IsEmpty() || player.RandomStartLocation {
+			continue
+		}
+
+		for _, unitType := range unitTypes {
+			if _, err := g.AddUnit(r, player.StartingX, player.StartingY, unitType, i); err != nil {
+				errs = append(errs, fmt.Errorf("player %d: %w", i, err))
+				continue
+			}
+			added++
+		}
+	}
+	return added, errors.Join(errs...)
+}
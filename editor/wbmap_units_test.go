@@ -0,0 +1,81 @@
+package editor
+
+import (
+	"testing"
+)
+
+func newTestUnitRegistries() *GameRegistries {
+	r := NewGameRegistries()
+	r.UnitInfos["UNIT_WARRIOR"] = &UnitInfo{TypeInfo: TypeInfo{Type: "UNIT_WARRIOR"}, Combat: "UNITCOMBAT_MELEE", Domain: DomainLand, DefaultUnitAI: "UNITAI_ATTACK"}
+	r.UnitInfos["UNIT_WORKBOAT"] = &UnitInfo{TypeInfo: TypeInfo{Type: "UNIT_WORKBOAT"}, Domain: DomainSea}
+	r.PromotionInfos["PROMOTION_COMBAT1"] = &PromotionInfo{TypeInfo: TypeInfo{Type: "PROMOTION_COMBAT1"}, UnitCombats: []string{"UNITCOMBAT_MELEE"}}
+	r.PromotionInfos["PROMOTION_COMBAT2"] = &PromotionInfo{TypeInfo: TypeInfo{Type: "PROMOTION_COMBAT2"}, PrereqPromotion: "PROMOTION_COMBAT1", UnitCombats: []string{"UNITCOMBAT_MELEE"}}
+	r.PromotionInfos["PROMOTION_NAVIGATION1"] = &PromotionInfo{TypeInfo: TypeInfo{Type: "PROMOTION_NAVIGATION1"}, UnitCombats: []string{"UNITCOMBAT_NAVAL"}}
+	return r
+}
+
+func TestUnitPromotions(t *testing.T) {
+	r := newTestUnitRegistries()
+	unit := r.NewUnit("UNIT_WARRIOR", 0)
+	if unit.UnitAIType != "UNITAI_ATTACK" {
+		t.Errorf("Unexpected default AI %s", unit.UnitAIType)
+	}
+
+	if r.AddPromotion(unit, "PROMOTION_COMBAT2") == nil || r.AddPromotion(unit, "PROMOTION_NAVIGATION1") == nil {
+		t.Error("Promotion without prerequisite or for another combat type must fail")
+	}
+	if r.AddPromotion(unit, "PROMOTION_COMBAT1") != nil || r.AddPromotion(unit, "PROMOTION_COMBAT2") != nil || len(unit.PromotionType) != 2 {
+		t.Fatalf("AddPromotion failed: %v", unit.PromotionType)
+	}
+
+	r.RemovePromotion(unit, "PROMOTION_COMBAT1")
+	if len(unit.PromotionType) != 0 {
+		t.Errorf("Dependent promotion must be removed: %v", unit.PromotionType)
+	}
+}
+
+func TestAddStartingUnits(t *testing.T) {
+	m := newTestPaintMap(5, 3, false)
+	m.Players = []*Player{
+		{CivType: "CIVILIZATION_ROME", StartingX: 1, StartingY: 1},
+		{CivType: "CIVILIZATION_GREECE", StartingX: 2, StartingY: 1},
+		{CivType: "CIVILIZATION_EGYPT", RandomStartLocation: true},
+		{CivType: NonePlayer},
+	}
+	g := NewPlotGrid(m)
+	r := newTestUnitRegistries()
+
+	// Greece starts on water (the middle column), so the warrior is not added there
+	added, err := g.AddStartingUnits(r, []string{"UNIT_WARRIOR"})
+	if added != 1 || err == nil || len(g.GetPlot(1, 1).Units) != 1 || g.GetPlot(1, 1).Units[0].UnitOwner != 0 {
+		t.Errorf("Unexpected result: %d units added, error %v", added, err)
+	}
+
+	if _, err = g.AddUnit(r, 1, 1, "UNIT_WORKBOAT", 0); err == nil {
+		t.Error("Sea unit must not be added on land")
+	}
+	if g.RemoveUnit(1, 1, 0) != nil || len(g.GetPlot(1, 1).Units) != 0 || g.RemoveUnit(1, 1, 0) == nil {
+		t.Error("RemoveUnit failed")
+	}
+}
+
+func TestRemoveUnitUndo(t *testing.T) {
+	h := NewHistory()
+	g := NewPlotGrid(newTestPaintMap(5, 3, false))
+	r := newTestUnitRegistries()
+	plot := g.GetPlot(1, 1)
+	units := []*Unit{r.NewUnit("UNIT_WARRIOR", 0), r.NewUnit("UNIT_WARRIOR", 1), r.NewUnit("UNIT_WARRIOR", 2)}
+	plot.Units = units
+
+	// Removing must not shift units in the array kept by the snapshot
+	if err := h.Try("Remove unit", func() error { return g.RemoveUnit(1, 1, 0) }, plot); err != nil {
+		t.Fatal(err)
+	}
+	if len(plot.Units) != 2 || plot.Units[0] != units[1] || units[0].UnitOwner != 0 {
+		t.Fatalf("Unexpected units after removal: %v", plot.Units)
+	}
+	h.Undo()
+	if len(plot.Units) != 3 || plot.Units[0].UnitOwner != 0 || plot.Units[1].UnitOwner != 1 || plot.Units[2].UnitOwner != 2 {
+		t.Errorf("Undo did not restore the stack: %v", plot.Units)
+	}
+}
@@ -0,0 +1,160 @@
+package editor
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// MapIssue is a problem of the map found by ValidateMap
+type MapIssue struct {
+	// Section is the part of the map with the problem, e.g. "Game", "Team 1", "Player 0" or "Plot 3,4"
+	Section string `json:"section"`
+	// X and Y are coordinates of the plot, -1 if the issue is not related to a plot
+	X       int    `json:"x"`
+	Y       int    `json:"y"`
+	Message string `json:"message"`
+}
+
+// String returns issue in "section: message" format
+func (i *MapIssue) String() string {
+	return i.Section + ": " + i.Message
+}
+
+// mapValidator collects issues of the map
+type mapValidator struct {
+	r      *GameRegistries
+	m      *WbMap
+	issues []MapIssue
+}
+
+// add records the issue, x and y are -1 for issues not related to a plot
+func (v *mapValidator) add(section string, x, y int, format string, args ...any) {
+	v.issues = append(v.issues, MapIssue{Section: section, X: x, Y: y, Message: fmt.Sprintf(format, args...)})
+}
+
+// checkTypes records types missing in the registry. Registries which are not loaded are not checked,
+// so maps can be edited without game data
+func checkTypes[T any](v *mapValidator, section string, x, y int, key string, registry map[string]T, types ...string) {
+	if len(registry) == 0 {
+		return
+	}
+	for _, value := range types {
+		if _, ok := registry[value]; !ok && value != "" && value != NonePlayer {
+			v.add(section, x, y, "unknown %s %s", key, value)
+		}
+	}
+}
+
+// ValidateMap checks references of the map to game types, teams and players,
+// positions of starting locations, cities and units. Returns nothing if the map is valid
+func (r *GameRegistries) ValidateMap(m *WbMap) []MapIssue {
+	v := &mapValidator{r: r, m: m}
+	grid := NewPlotGrid(m)
+
+	if m.Game == nil {
+		v.add("Game", -1, -1, "game settings are missing")
+	} else {
+		g := m.Game
+		checkTypes(v, "Game", -1, -1, "Era", r.EraInfos, g.Era)
+		checkTypes(v, "Game", -1, -1, "Speed", r.SpeedInfos, g.Speed)
+		checkTypes(v, "Game", -1, -1, "Calendar", r.CalendarInfos, g.Calendar)
+		checkTypes(v, "Game", -1, -1, "Victory", r.VictoryInfos, g.Victory...)
+		checkTypes(v, "Game", -1, -1, "Option", r.GameOptionInfos, g.Option...)
+		checkTypes(v, "Game", -1, -1, "MPOption", r.GameMPInfos, g.MPOption...)
+		checkTypes(v, "Game", -1, -1, "ForceControl", r.ForceControlInfos, g.ForceControl...)
+		if g.MaxTurns != 0 && g.MaxTurns <= g.GameTurn {
+			v.add("Game", -1, -1, "MaxTurns %d must be greater than GameTurn %d", g.MaxTurns, g.GameTurn)
+		}
+	}
+
+	teams := make(map[uint]bool)
+	for _, team := range m.Teams {
+		section := "Team " + strconv.Itoa(int(team.TeamID))
+		if teams[team.TeamID] {
+			v.add(section, -1, -1, "duplicate team ID")
+		}
+		teams[team.TeamID] = true
+		checkTypes(v, section, -1, -1, "Tech", r.TechInfos, team.Tech...)
+		checkTypes(v, section, -1, -1, "ProjectType", r.ProjectInfos, team.ProjectType...)
+	}
+	for _, team := range m.Teams {
+		for _, relation := range TeamRelations {
+			for _, other := range *team.getRelationList(relation) {
+				if !teams[other] {
+					v.add("Team "+strconv.Itoa(int(team.TeamID)), -1, -1, "%s refers to missing team %d", relation, other)
+				}
+			}
+		}
+	}
+
+	for i, player := range m.Players {
+		if player.IsEmpty() {
+			continue
+		}
+
+		section := "Player " + strconv.Itoa(i)
+		if !teams[player.Team] {
+			v.add(section, -1, -1, "team %d not found", player.Team)
+		}
+		checkTypes(v, section, -1, -1, "CivType", r.CivilizationInfos, player.CivType)
+		checkTypes(v, section, -1, -1, "LeaderType", r.LeaderHeadInfos, player.LeaderType)
+		checkTypes(v, section, -1, -1, "Color", r.PlayerColorInfos, player.Color)
+		checkTypes(v, section, -1, -1, "ArtStyle", r.ArtStyleInfos, player.ArtStyle)
+		checkTypes(v, section, -1, -1, "Handicap", r.HandicapInfos, player.Handicap)
+		checkTypes(v, section, -1, -1, "StateReligion", r.ReligionInfos, player.StateReligion)
+		checkTypes(v, section, -1, -1, "StartingEra", r.EraInfos, player.StartingEra)
+		checkTypes(v, section, -1, -1, "Civic", r.CivicInfos, player.Civic...)
+		if !player.RandomStartLocation && grid.GetPlot(player.StartingX, player.StartingY) == nil {
+			v.add(section, player.StartingX, player.StartingY, "starting plot %d,%d is outside the map", player.StartingX, player.StartingY)
+		}
+	}
+
+	for _, plot := range m.Plots {
+		v.validatePlot(grid, plot)
+	}
+
+	return v.issues
+}
+
+// validatePlot checks types of the plot and its cities and units
+func (v *mapValidator) validatePlot(grid *PlotGrid, plot *Plot) {
+	r, x, y := v.r, int(plot.X), int(plot.Y)
+	section := "Plot " + strconv.Itoa(x) + "," + strconv.Itoa(y)
+	if v.m.Map != nil && (plot.X >= uint(v.m.Map.GridWidth) || plot.Y >= uint(v.m.Map.GridHeight)) {
+		v.add(section, x, y, "plot is outside the map %dx%d", v.m.Map.GridWidth, v.m.Map.GridHeight)
+	}
+	if plot.TerrainType == "" {
+		v.add(section, x, y, "terrain is missing")
+	}
+	checkTypes(v, section, x, y, "TerrainType", r.TerrainInfos, plot.TerrainType)
+	checkTypes(v, section, x, y, "FeatureType", r.FeatureInfos, plot.FeatureType...)
+	checkTypes(v, section, x, y, "BonusType", r.BonusInfos, plot.BonusType)
+	checkTypes(v, section, x, y, "ImprovementType", r.ImprovementInfos, plot.ImprovementType)
+	checkTypes(v, section, x, y, "RouteType", r.RouteInfos, plot.RouteType)
+
+	for _, city := range plot.Cities {
+		if !v.isActivePlayer(int(city.CityOwner)) {
+			v.add(section, x, y, "owner %d of city %s is not an active player", city.CityOwner, city.CityName)
+		}
+		if err := grid.ValidateCityPlot(x, y); err != nil {
+			v.add(section, x, y, "city %s: %s", city.CityName, err)
+		}
+		checkTypes(v, section, x, y, "BuildingType", r.BuildingInfos, city.BuildingType...)
+		checkTypes(v, section, x, y, "ReligionType", r.ReligionInfos, city.ReligionType...)
+	}
+	for _, unit := range plot.Units {
+		if !v.isActivePlayer(unit.UnitOwner) {
+			v.add(section, x, y, "owner %d of unit %s is not an active player", unit.UnitOwner, unit.UnitType)
+		}
+		checkTypes(v, section, x, y, "UnitType", r.UnitInfos, unit.UnitType)
+		checkTypes(v, section, x, y, "PromotionType", r.PromotionInfos, unit.PromotionType...)
+		if err := r.CheckUnitPlot(unit.UnitType, plot); err != nil {
+			v.add(section, x, y, "%s", err)
+		}
+	}
+}
+
+// isActivePlayer returns true if the player slot exists and is not empty
+func (v *mapValidator) isActivePlayer(index int) bool {
+	return index >= 0 && index < len(v.m.Players) && !v.m.Players[index].IsEmpty()
+}
@@ -0,0 +1,48 @@
+package editor
+
+import "testing"
+
+func TestValidateMap(t *testing.T) {
+	m := newTestPaintMap(5, 3, false)
+	m.Game = &Game{Era: "ERA_FUTURE", GameTurn: 10, MaxTurns: 5}
+	m.Teams = []*Team{{TeamID: 0, ContactWithTeam: []uint{0}, AtWar: []uint{3}}}
+	m.Players = []*Player{
+		{CivType: "CIVILIZATION_ROME", Team: 0, StartingX: 1, StartingY: 1},
+		{CivType: NonePlayer, LeaderType: NonePlayer, Team: 7},
+		{CivType: "CIVILIZATION_ROME", Team: 5, StartingX: 9, StartingY: 1},
+	}
+	m.Plots[6].Units = []*Unit{{UnitType: "UNIT_WARRIOR", UnitOwner: 1}}
+	m.Plots[7].Units = []*Unit{{UnitType: "UNIT_WARRIOR", UnitOwner: 0}}
+
+	r := newTestUnitRegistries()
+	r.EraInfos["ERA_ANCIENT"] = &TypeInfo{Type: "ERA_ANCIENT"}
+	issues := r.ValidateMap(m)
+
+	// Empty player slot is skipped, registries which are not loaded (civilizations) are not checked
+	expected := []string{
+		"Game: unknown Era ERA_FUTURE",
+		"Game: MaxTurns 5 must be greater than GameTurn 10",
+		"Team 0: AtWar refers to missing team 3",
+		"Player 2: team 5 not found",
+		"Player 2: starting plot 9,1 is outside the map",
+		"Plot 1,1: owner 1 of unit UNIT_WARRIOR is not an active player",
+		"Plot 2,1: land unit UNIT_WARRIOR cannot be on water plot 2,1",
+	}
+	if len(issues) != len(expected) {
+		t.Fatalf("Unexpected issues: %+v", issues)
+	}
+	for i, issue := range issues {
+		if issue.String() != expected[i] {
+			t.Errorf("Expected %q, got %q", expected[i], issue.String())
+		}
+	}
+	if issues[6].X != 2 || issues[6].Y != 1 || issues[0].X != -1 {
+		t.Errorf("Unexpected issue coordinates: %+v", issues)
+	}
+
+	m.Game, m.Teams[0].AtWar, m.Players = &Game{Era: "ERA_ANCIENT"}, nil, m.Players[:1]
+	m.Plots[6].Units, m.Plots[7].Units = nil, nil
+	if issues = r.ValidateMap(m); len(issues) != 0 {
+		t.Errorf("Valid map has issues: %+v", issues)
+	}
+}
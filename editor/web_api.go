@@ -0,0 +1,556 @@
+package editor
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// mapFileFilters are file filters of map dialogs
+var mapFileFilters = []runtime.FileFilter{{
+	DisplayName: "WorldBuilder saves",
+	Pattern:     "*.CivBeyondSwordWBSave;*.CivWarlordsWBSave;*.Civ4WorldBuilderSave",
+}}
+
+// MapInfo describes the map open in the web editor
+type MapInfo struct {
+	FilePath string `json:"file_path"`
+	Title    string `json:"title"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	WrapX    bool   `json:"wrap_x"`
+	WrapY    bool   `json:"wrap_y"`
+	Dirty    bool   `json:"dirty"`
+	CanUndo  bool   `json:"can_undo"`
+	CanRedo  bool   `json:"can_redo"`
+	// UndoName is the name of the step undone next, empty if there is nothing to undo
+	UndoName string `json:"undo_name"`
+}
+
+// NewMap creates an empty ocean map of the size (see NewWbMap), it has to be saved to a file with SaveMap
+func (a *App) NewMap(width int, height int) (*MapInfo, error) {
+	m, err := NewWbMap(width, height)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	doc := &MapDocument{WbMap: m, History: NewHistory()}
+	doc.History.MarkUnsaved()
+	a.setDocument(doc)
+	return a.getMapInfo(), nil
+}
+
+// OpenMap parses the map file, empty path shows a file dialog. Nil is returned if the dialog is cancelled
+func (a *App) OpenMap(path string) (*MapInfo, error) {
+	if path == "" {
+		var err error
+		path, err = runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{Title: "Open map", Filters: mapFileFilters})
+		if err != nil || path == "" {
+			return nil, err
+		}
+	}
+
+	ctx, reporter := a.startProgress("Opening " + filepath.Base(path))
+	doc, err := OpenMapDocument(ctx, path)
+	reporter.Finish(err)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.setDocument(doc)
+	addRecentFile(path)
+	return a.getMapInfo(), nil
+}
+
+// SaveMap writes the map to the file (see SaveWbMap). Empty path means the current file of the map,
+// a file dialog is shown if the map has no file yet. Nil is returned if the dialog is cancelled
+func (a *App) SaveMap(path string) (*MapInfo, error) {
+	a.mu.Lock()
+	doc, err := a.getDocument()
+	if err == nil && path == "" {
+		path = doc.FilePath
+	}
+	a.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// The dialog is shown without the lock, so other methods are not blocked while it's open
+	if path == "" {
+		path, err = runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{Title: "Save map", Filters: mapFileFilters})
+		if err != nil || path == "" {
+			return nil, err
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.doc != doc {
+		return nil, errors.New("map was closed before saving")
+	}
+
+	ctx, reporter := a.startProgress("Saving " + filepath.Base(path))
+	err = SaveWbMap(ctx, path, doc.WbMap)
+	reporter.Finish(err)
+	if err != nil {
+		return nil, err
+	}
+	if doc.FilePath != path {
+		// Recovery of the map is named by its file now
+		a.autosaver.RemoveRecovery()
+		a.autosaver.SetMap(path, doc.WbMap, doc.History)
+	}
+	doc.FilePath = path
+	doc.History.MarkSaved()
+	addRecentFile(path)
+	return a.getMapInfo(), nil
+}
+
+// GetMapInfo returns the file, size and history state of the open map
+func (a *App) GetMapInfo() (*MapInfo, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.getDocument(); err != nil {
+		return nil, err
+	}
+	return a.getMapInfo(), nil
+}
+
+// Undo reverts the last change of the map
+func (a *App) Undo() (*MapInfo, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	doc, err := a.getDocument()
+	if err != nil {
+		return nil, err
+	}
+
+	doc.History.Undo()
+	a.setDocument(doc)
+	return a.getMapInfo(), nil
+}
+
+// Redo applies the last undone change of the map again
+func (a *App) Redo() (*MapInfo, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	doc, err := a.getDocument()
+	if err != nil {
+		return nil, err
+	}
+
+	doc.History.Redo()
+	a.setDocument(doc)
+	return a.getMapInfo(), nil
+}
+
+// GetGame returns game settings of the map
+func (a *App) GetGame() (*Game, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	doc, err := a.getDocument()
+	if err != nil {
+		return nil, err
+	}
+
+	game := cloneGame(*doc.WbMap.Game)
+	return &game, nil
+}
+
+// PatchGame changes game settings, keys of the patch are field names of Game (e.g. {"Era": "ERA_MEDIEVAL"})
+func (a *App) PatchGame(patch map[string]any) (*Game, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	doc, err := a.getDocument()
+	if err != nil {
+		return nil, err
+	}
+
+	game := doc.WbMap.Game
+	err = doc.History.Try(getPatchName("Game", patch), func() error {
+		return applyPatch(game, patch)
+	}, game)
+	if err != nil {
+		return nil, err
+	}
+
+	result := cloneGame(*game)
+	return &result, nil
+}
+
+// GetTeams returns all teams of the map
+func (a *App) GetTeams() ([]*Team, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	doc, err := a.getDocument()
+	if err != nil {
+		return nil, err
+	}
+	return cloneSlice(doc.WbMap.Teams, cloneTeam), nil
+}
+
+// PatchTeam changes the team, keys of the patch are field names of Team. TeamID cannot be changed.
+// Use SetTeamRelation to change relations keeping both teams consistent
+func (a *App) PatchTeam(id uint, patch map[string]any) (*Team, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	doc, err := a.getDocument()
+	if err != nil {
+		return nil, err
+	}
+
+	team := doc.WbMap.GetTeam(id)
+	if team == nil {
+		return nil, fmt.Errorf("team %d not found", id)
+	}
+	err = doc.History.Try(getPatchName("Team "+strconv.Itoa(int(id)), patch), func() error {
+		return applyPatch(team, patch, "TeamID")
+	}, team)
+	if err != nil {
+		return nil, err
+	}
+
+	result := cloneTeam(*team)
+	return &result, nil
+}
+
+// SetTeamRelation sets or removes the relation (e.g. "AtWar") between two teams, see WbMap.SetTeamRelation
+func (a *App) SetTeamRelation(relation string, from uint, to uint, value bool) ([]*Team, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	doc, err := a.getDocument()
+	if err != nil {
+		return nil, err
+	}
+
+	m := doc.WbMap
+	err = doc.History.Try(TeamRelationNames[TeamRelation(relation)], func() error {
+		return m.SetTeamRelation(TeamRelation(relation), from, to, value)
+	}, &m.Teams)
+	if err != nil {
+		return nil, err
+	}
+	return cloneSlice(m.Teams, cloneTeam), nil
+}
+
+// GetPlayers returns all player slots of the map, including empty ones
+func (a *App) GetPlayers() ([]*Player, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	doc, err := a.getDocument()
+	if err != nil {
+		return nil, err
+	}
+	return cloneSlice(doc.WbMap.Players, clonePlayer), nil
+}
+
+// PatchPlayer changes the player, keys of the patch are field names of Player. The team must exist and not be used by another player
+func (a *App) PatchPlayer(index int, patch map[string]any) (*Player, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	doc, err := a.getDocument()
+	if err != nil {
+		return nil, err
+	}
+
+	m := doc.WbMap
+	if index < 0 || index >= len(m.Players) {
+		return nil, fmt.Errorf("player %d not found", index)
+	}
+	player := m.Players[index]
+	err = doc.History.Try(getPatchName("Player "+strconv.Itoa(index), patch), func() error {
+		if err := applyPatch(player, patch); err != nil {
+			return err
+		}
+		return m.CheckPlayerTeam(index)
+	}, player)
+	if err != nil {
+		return nil, err
+	}
+
+	result := clonePlayer(*player)
+	return &result, nil
+}
+
+// SetCivilization sets the civilization of the player with its default names, leader, color and civics (see ApplyCivilization)
+func (a *App) SetCivilization(index int, civType string) (*Player, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	doc, err := a.getDocument()
+	if err != nil {
+		return nil, err
+	}
+
+	m := doc.WbMap
+	if index < 0 || index >= len(m.Players) {
+		return nil, fmt.Errorf("player %d not found", index)
+	}
+	player := m.Players[index]
+	doc.History.Update("Civilization", func() {
+		a.data.Load().Registries().ApplyCivilization(player, civType)
+	}, player)
+
+	result := clonePlayer(*player)
+	return &result, nil
+}
+
+// AddPlayer takes the first empty player slot or adds a new one with a free civilization (see WbMap.AddPlayer),
+// returns its index. Error is returned if all player slots or civilizations are used
+func (a *App) AddPlayer() (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	doc, err := a.getDocument()
+	if err != nil {
+		return 0, err
+	}
+
+	index := 0
+	m := doc.WbMap
+	err = doc.History.Try("Add player", func() (err error) {
+		index, err = m.AddPlayer(a.data.Load().Registries())
+		return err
+	}, m)
+	return index, err
+}
+
+// RemovePlayer makes the player slot empty with all its units and cities (see WbMap.RemovePlayer)
+func (a *App) RemovePlayer(index int) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	doc, err := a.getDocument()
+	if err != nil {
+		return err
+	}
+
+	m := doc.WbMap
+	return doc.History.Try("Remove player", func() error { return m.RemovePlayer(index) }, m)
+}
+
+// GetPlots returns plots in the selection, missing plots are skipped
+func (a *App) GetPlots(s Selection) ([]*Plot, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.getDocument(); err != nil {
+		return nil, err
+	}
+	return cloneSlice(a.grid.GetPlotsIn(s), clonePlot), nil
+}
+
+// PatchPlot changes the plot, keys of the patch are field names of Plot. Coordinates cannot be changed
+func (a *App) PatchPlot(x int, y int, patch map[string]any) (*Plot, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	doc, err := a.getDocument()
+	if err != nil {
+		return nil, err
+	}
+
+	plot := a.grid.GetPlot(x, y)
+	if plot == nil {
+		return nil, fmt.Errorf("plot %d,%d not found", x, y)
+	}
+	err = doc.History.Try(getPatchName("Plot "+strconv.Itoa(x)+","+strconv.Itoa(y), patch), func() error {
+		if err := applyPatch(plot, patch, "X", "Y"); err != nil {
+			return err
+		}
+		if plot.PlotType > PlotTypeOcean {
+			return fmt.Errorf("invalid plot type %d", plot.PlotType)
+		}
+		return nil
+	}, plot)
+	if err != nil {
+		return nil, err
+	}
+
+	result := clonePlot(*plot)
+	return &result, nil
+}
+
+// Paint applies the brush to plots around the point (see PlotGrid.Paint), returns changed plots
+func (a *App) Paint(x int, y int, brush Brush) ([]*Plot, error) {
+	return a.paint(func(g *PlotGrid) ([]*Plot, error) { return g.Paint(x, y, brush) })
+}
+
+// FillSelection applies the brush to all plots in the selection, returns changed plots
+func (a *App) FillSelection(s Selection, brush Brush) ([]*Plot, error) {
+	return a.paint(func(g *PlotGrid) ([]*Plot, error) { return g.FillSelection(s, brush) })
+}
+
+// FloodFill applies the brush to the connected area of plots with the same value (see PlotGrid.FloodFill), returns changed plots
+func (a *App) FloodFill(x int, y int, brush Brush) ([]*Plot, error) {
+	return a.paint(func(g *PlotGrid) ([]*Plot, error) { return g.FloodFill(x, y, brush) })
+}
+
+// GetBrushChoices returns values of the brush tool (e.g. "TerrainType") for the current game data
+func (a *App) GetBrushChoices(tool string) []Choice {
+	return a.data.Load().Registries().GetBrushChoices(BrushTool(tool))
+}
+
+// GetRegistryNames returns names of registries available in GetChoices
+func (a *App) GetRegistryNames() []string {
+	return GetRegistryNames()
+}
+
+// ValidateMap checks the map against the current game data (see GameRegistries.ValidateMap)
+func (a *App) ValidateMap() ([]MapIssue, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	doc, err := a.getDocument()
+	if err != nil {
+		return nil, err
+	}
+	return a.data.Load().Registries().ValidateMap(doc.WbMap), nil
+}
+
+// LaunchGame starts the game using the profile (empty name means the selected one) with the open map
+// as a new game or in WorldBuilder (see LaunchMode). The map must be saved first. Without open map the game is started as is
+func (a *App) LaunchGame(profile string, mode string) error {
+	if GlobalConfig == nil {
+		return errors.New("game is not configured")
+	}
+	launchProfile, err := GlobalConfig.GetLaunchProfile(profile)
+	if err != nil {
+		return err
+	}
+	launchMode := LaunchMode(mode)
+	if launchMode != LaunchNewGame && launchMode != LaunchWorldBuilder {
+		return errors.New("unknown launch mode " + mode)
+	}
+
+	a.mu.Lock()
+	path := ""
+	if a.doc != nil {
+		if a.doc.FilePath == "" || a.doc.History.IsDirty() {
+			a.mu.Unlock()
+			return errors.New("save the map before launching the game")
+		}
+		path = a.doc.FilePath
+	}
+	a.mu.Unlock()
+
+	return LaunchGame(launchProfile, path, launchMode)
+}
+
+// GetGameStatus returns the state of the game started by LaunchGame
+func (a *App) GetGameStatus() GameStatus {
+	return GetGameStatus()
+}
+
+// KillGame stops the running game
+func (a *App) KillGame() error {
+	return KillGame()
+}
+
+// RestartGame stops the running game and starts it again with the same map, e.g. after the map is changed
+func (a *App) RestartGame() error {
+	return RestartGame()
+}
+
+// GetLaunchProfiles returns launch profiles of the configuration
+func (a *App) GetLaunchProfiles() []LaunchProfile {
+	if GlobalConfig == nil {
+		return nil
+	}
+	return GlobalConfig.GetLaunchProfiles()
+}
+
+// paint applies the brush as one history step and returns copies of changed plots
+func (a *App) paint(apply func(g *PlotGrid) ([]*Plot, error)) ([]*Plot, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	doc, err := a.getDocument()
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []*Plot
+	err = doc.History.Try("Paint", func() error {
+		changed, err = apply(a.grid)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cloneSlice(changed, clonePlot), nil
+}
+
+// getDocument returns the open map or an error if there is no map, a.mu must be locked
+func (a *App) getDocument() (*MapDocument, error) {
+	if a.doc == nil {
+		return nil, errors.New("no map is open")
+	}
+	return a.doc, nil
+}
+
+// setDocument makes the map current. It's called after undo and redo too, as they may remove or restore plots created by brush
+func (a *App) setDocument(doc *MapDocument) {
+	if a.doc != doc {
+		a.autosaver.SetMap(doc.FilePath, doc.WbMap, doc.History)
+	}
+	a.doc, a.grid = doc, NewPlotGrid(doc.WbMap)
+	a.grid.OnBeforeChange = doc.History.Track
+}
+
+// getMapInfo returns the state of the open map, a.mu must be locked
+func (a *App) getMapInfo() *MapInfo {
+	h := a.doc.History
+	return &MapInfo{
+		FilePath: a.doc.FilePath,
+		Title:    a.doc.GetTitle(),
+		Width:    a.grid.Width,
+		Height:   a.grid.Height,
+		WrapX:    a.grid.WrapX,
+		WrapY:    a.grid.WrapY,
+		Dirty:    h.IsDirty(),
+		CanUndo:  h.CanUndo(),
+		CanRedo:  h.CanRedo(),
+		UndoName: h.GetUndoName(),
+	}
+}
+
+// addRecentFile adds the map to recent files of the configuration
+func addRecentFile(path string) {
+	if GlobalConfig == nil {
+		return
+	}
+
+	GlobalConfig.AddRecentFile(path)
+	if err := SaveConfig(); err != nil {
+		Logger.Warn("Cannot save config", "error", err)
+	}
+}
+
+// getPatchName returns a history step name for the patch, e.g. "Player 1: CivDesc, LeaderName"
+func getPatchName(prefix string, patch map[string]any) string {
+	return prefix + ": " + strings.Join(SortKeys(patch), ", ")
+}
+
+// applyPatch sets fields of the target from the patch, keys are field names. Unknown and protected fields are rejected.
+// The target may be changed partially on error, so it must be applied inside History.Try
+func applyPatch(target any, patch map[string]any, protected ...string) error {
+	for key := range patch {
+		for _, field := range protected {
+			if strings.EqualFold(key, field) {
+				return fmt.Errorf("field %s cannot be changed", field)
+			}
+		}
+	}
+
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(target)
+}
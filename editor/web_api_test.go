@@ -0,0 +1,104 @@
+package editor
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAppMapEditing(t *testing.T) {
+	useTestStudioDirs(t)
+	a := NewApp()
+	if _, err := a.GetGame(); err == nil {
+		t.Fatal("Map methods must fail without open map")
+	}
+
+	info, err := a.NewMap(4, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Width != 4 || info.Height != 3 || !info.WrapX || !info.Dirty || info.Title != "Untitled *" {
+		t.Errorf("Unexpected new map: %+v", info)
+	}
+
+	game, err := a.PatchGame(map[string]any{"Description": "Test", "StartYear": 1939})
+	if err != nil || game.Description != "Test" || game.StartYear != 1939 {
+		t.Fatalf("PatchGame failed: %v", err)
+	}
+	if _, err = a.PatchGame(map[string]any{"Unknown": 1}); err == nil {
+		t.Error("Unknown field must be rejected")
+	}
+	if _, err = a.PatchGame(map[string]any{"Description": "Partial", "StartYear": "wrong"}); err == nil {
+		t.Error("Wrong type must be rejected")
+	}
+	if game, _ = a.GetGame(); game.Description != "Test" {
+		t.Errorf("Failed patch must be rolled back: %s", game.Description)
+	}
+
+	data := NewGameData("", "")
+	data.registries = newTestCivRegistries(3)
+	a.data.Store(data)
+	index, _ := a.AddPlayer()
+	if _, err = a.PatchPlayer(index, map[string]any{"Team": 5}); err == nil {
+		t.Error("Player must not be moved to missing team")
+	}
+	if other, err := a.AddPlayer(); err != nil || other == index {
+		t.Errorf("AddPlayer must take another slot: %d, %v", other, err)
+	} else if _, err = a.PatchPlayer(other, map[string]any{"Team": index}); err == nil || !strings.Contains(err.Error(), "is used by") {
+		t.Errorf("Player must not be moved to team of another player: %v", err)
+	}
+	player, err := a.PatchPlayer(index, map[string]any{"CivDesc": "Roman Empire"})
+	if err != nil || player.CivDesc != "Roman Empire" {
+		t.Errorf("PatchPlayer failed: %v", err)
+	}
+	if _, err = a.PatchTeam(player.Team, map[string]any{"TeamID": 3}); err == nil {
+		t.Error("TeamID must not be changed")
+	}
+
+	changed, err := a.Paint(1, 1, Brush{Tool: BrushPlotType, Value: "2", Size: 1})
+	if err != nil || len(changed) != 1 {
+		t.Fatalf("Paint failed: %v", err)
+	}
+	changed[0].PlotType = PlotTypePeak
+	if _, err = a.PatchPlot(1, 1, map[string]any{"TerrainType": "TERRAIN_GRASS"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = a.PatchPlot(1, 1, map[string]any{"x": 2}); err == nil {
+		t.Error("Plot coordinates must not be changed")
+	}
+	plots, _ := a.GetPlots(Selection{1, 1, 1, 1})
+	if len(plots) != 1 || plots[0].PlotType != PlotTypeFlat || plots[0].TerrainType != "TERRAIN_GRASS" {
+		t.Errorf("Returned plots must be copies: %+v", plots)
+	}
+
+	info, _ = a.Undo()
+	if info.UndoName != "Paint" || !info.CanRedo {
+		t.Errorf("Unexpected history: %+v", info)
+	}
+	a.Undo()
+	if plots, _ = a.GetPlots(Selection{1, 1, 1, 1}); plots[0].PlotType != PlotTypeOcean {
+		t.Errorf("Undo of paint failed: %+v", plots[0])
+	}
+
+	oldConfig := GlobalConfig
+	GlobalConfig = &Config{}
+	if err = a.LaunchGame("", string(LaunchNewGame)); err == nil || !strings.Contains(err.Error(), "save the map") {
+		t.Errorf("Unsaved map must not be launched: %v", err)
+	}
+	if a.LaunchGame("", "unknown") == nil || a.LaunchGame("Missing", string(LaunchNewGame)) == nil {
+		t.Error("Unknown launch mode and profile must be rejected")
+	}
+	GlobalConfig = oldConfig
+	path := filepath.Join(t.TempDir(), "test.CivBeyondSwordWBSave")
+	if info, err = a.SaveMap(path); err != nil || info.Dirty || info.FilePath != path {
+		t.Fatalf("SaveMap failed: %v", err)
+	}
+
+	a = NewApp()
+	if info, err = a.OpenMap(path); err != nil || info.Dirty || info.Width != 4 {
+		t.Fatalf("OpenMap failed: %v", err)
+	}
+	if game, _ = a.GetGame(); game.Description != "Test" {
+		t.Errorf("Unexpected opened game: %+v", game)
+	}
+}
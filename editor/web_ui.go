@@ -2,12 +2,27 @@ package editor
 
 import (
 	"context"
+	"errors"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"log/slog"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type App struct {
 	ctx context.Context
+	// data is game data for the configured game directory and mod, replaced when configuration changes
+	data atomic.Pointer[GameData]
+	// mu guards the open map, bound methods are called concurrently
+	mu   sync.Mutex
+	doc  *MapDocument
+	grid *PlotGrid
+	// autosaver writes the open map to the recovery directory, new maps are autosaved too
+	autosaver Autosaver
+	// cancelLoad cancels loading of game data started by loadGameData, guarded by loadMu
+	loadMu     sync.Mutex
+	cancelLoad context.CancelFunc
 }
 
 func NewApp() *App {
@@ -25,15 +40,26 @@ func (a *App) startup(ctx context.Context) {
 
 	a.ctx = ctx
 
-	console := GetConsoleChannel()
+	unsubscribe := SubscribeProgress(func(p Progress) {
+		runtime.EventsEmit(ctx, "progress", p)
+	})
+	unsubscribeGame := SubscribeGame(func(status GameStatus) {
+		runtime.EventsEmit(ctx, "game", status)
+	})
 	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case line := <-console:
-				runtime.EventsEmit(ctx, "console", line)
-			}
+		<-ctx.Done()
+		unsubscribe()
+		unsubscribeGame()
+	}()
+
+	entries, unsubscribeLog := SubscribeLog(100)
+	go func() {
+		<-ctx.Done()
+		unsubscribeLog()
+	}()
+	go func() {
+		for entry := range entries {
+			runtime.EventsEmit(ctx, "log", entry)
 		}
 	}()
 
@@ -44,6 +70,16 @@ func (a *App) startup(ctx context.Context) {
 	} else {
 		ConsoleWrite("Config file found, opening editor")
 	}
+	a.loadGameData()
+
+	a.autosaver.OnError = func(err error) { Logger.Warn("Autosave failed", "error", err) }
+	if GlobalConfig.AutoSave {
+		a.autosaver.Start(AutosaveInterval)
+		go func() {
+			<-ctx.Done()
+			a.autosaver.Stop()
+		}()
+	}
 
 	go func() {
 		ConsoleWrite(time.Now().String())
@@ -55,18 +91,102 @@ func (a *App) WriteConsole(line string) {
 	ConsoleWrite(line)
 }
 
+// GetLogEntries returns log entries kept in memory with the level ("DEBUG", "INFO", "WARN", "ERROR") or higher
+func (a *App) GetLogEntries(level string) []LogEntry {
+	var minLevel slog.Level
+	if err := minLevel.UnmarshalText([]byte(level)); err != nil {
+		minLevel = slog.LevelInfo
+	}
+	return GetLogEntries(minLevel)
+}
+
 func (a *App) GetConfig() *Config {
 	return GlobalConfig
 }
 
 func (a *App) SetConfig(config *Config) {
+	oldConfig := GlobalConfig
 	GlobalConfig = config
 	err := SaveConfig()
 	if err != nil {
 		ConsoleWrite(err.Error())
 	}
+
+	if oldConfig == nil || oldConfig.GameDir != config.GameDir || oldConfig.Mod != config.Mod {
+		a.loadGameData()
+	}
 }
 
 func (a *App) GetModsList() []string {
 	return GetModsList(GlobalConfig.GameDir)
 }
+
+// FindGameInstalls returns found game installations to pick game directory from
+func (a *App) FindGameInstalls() []GameInstall {
+	return FindGameInstalls()
+}
+
+func (a *App) GetLanguagesList() []string {
+	return GameLanguages
+}
+
+// GetChoices returns entries of the registry (e.g. "EraInfos") sorted by order ("xml" or "name"), see GetChoices
+func (a *App) GetChoices(registry string, order string) ([]Choice, error) {
+	return a.data.Load().Registries().GetRegistryChoices(registry, ChoiceOrder(order))
+}
+
+// GetLoadReport returns problems found while loading XML files of the current game data
+func (a *App) GetLoadReport() *XmlLoadReport {
+	return a.data.Load().Registries().XmlLoadReport
+}
+
+// LintMod checks XML files of the current mod for missing texts and broken references
+func (a *App) LintMod() *LintReport {
+	return LintGameData(a.data.Load())
+}
+
+// loadGameData replaces game data with the new one for the configured game directory and mod and loads it in background
+// Loading of the previous game data is cancelled, it's not used anymore
+func (a *App) loadGameData() {
+	a.loadMu.Lock()
+	defer a.loadMu.Unlock()
+	if a.cancelLoad != nil {
+		a.cancelLoad()
+		a.cancelLoad = nil
+	}
+
+	data := NewGameData(GlobalConfig.GameDir, GlobalConfig.Mod)
+	a.data.Store(data)
+	if err := CheckGameDirectory(GlobalConfig.GameDir); err != nil {
+		return
+	}
+
+	ctx, reporter := a.startProgress("Loading game data")
+	ctx, a.cancelLoad = context.WithCancel(ctx)
+	go func() {
+		err := data.Reload(ctx)
+		reporter.Finish(err)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			ConsoleWrite(err.Error())
+		}
+	}()
+}
+
+// GetProgress returns operations which are running now, e.g. to show them after the page is reloaded
+func (a *App) GetProgress() []Progress {
+	return GetRunningProgress()
+}
+
+// CancelOperation cancels the running operation by its ID (see Progress), returns false if it's already finished
+func (a *App) CancelOperation(id int) bool {
+	return CancelProgress(id)
+}
+
+// startProgress starts reporting of the operation, it's cancelled when the application is closed
+func (a *App) startProgress(operation string) (context.Context, *ProgressReporter) {
+	parent := a.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	return StartProgress(parent, operation)
+}
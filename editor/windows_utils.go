@@ -5,18 +5,18 @@ package editor
 import (
 	"golang.org/x/sys/windows"
 	"os"
+	"os/exec"
 	"strings"
 	"syscall"
 )
 
-// RunElevated runs an executable with elevated privileges. Windows only
-func RunElevated(exe string, argv []string) error {
+// RunElevated runs an executable with elevated privileges in the directory, argv must be quoted already. Windows only
+func RunElevated(exe string, argv []string, cwd string) error {
 	exe = "\"" + exe + "\""
 	verb := "runas"
-	cwd, _ := os.Getwd()
 	args := strings.Join(argv, " ")
 
-	ConsoleWrite("Running %s %s", exe, args)
+	Logger.Debug("Running elevated", "exe", exe, "args", args)
 
 	verbPtr, _ := syscall.UTF16PtrFromString(verb)
 	exePtr, _ := syscall.UTF16PtrFromString(exe)
@@ -25,3 +25,16 @@ func RunElevated(exe string, argv []string) error {
 
 	return windows.ShellExecute(0, verbPtr, exePtr, argPtr, cwdPtr, 1)
 }
+
+// setCommandLine sets the command line of the command as is, so Go doesn't quote arguments once more
+func setCommandLine(cmd *exec.Cmd, line string) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: line}
+}
+
+// setProcessGroup does nothing, the game is started without wrappers on Windows
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessGroup kills the process, the game is started without wrappers on Windows
+func killProcessGroup(process *os.Process) error {
+	return process.Kill()
+}
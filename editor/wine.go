@@ -0,0 +1,163 @@
+package editor
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// BtsSteamAppID is Steam application ID of Beyond the Sword, its Proton prefix is steamapps/compatdata/8800/pfx
+const BtsSteamAppID = "8800"
+
+// WineInstall is a game installed into Wine prefix, or into Steam library and played with Proton
+type WineInstall struct {
+	GameDir    string `json:"game_dir"`
+	WinePrefix string `json:"wine_prefix"`
+	// Proton is a directory of Steam Proton running the game, empty if the game is run with Wine
+	Proton string `json:"proton"`
+}
+
+// DetectWine returns Wine prefix and Proton of the game directory: a directory inside drive_c belongs to its prefix,
+// and a game of Steam library is run with Proton if its prefix exists in compatdata. Prefix is empty if it's unknown
+func DetectWine(gameDir string) WineInstall {
+	install := WineInstall{GameDir: gameDir}
+	slashed := filepath.ToSlash(gameDir)
+	if i := strings.Index(slashed, "/drive_c/"); i >= 0 {
+		install.WinePrefix = filepath.FromSlash(slashed[:i])
+		return install
+	}
+
+	if i := strings.Index(slashed, "/steamapps/common/"); i >= 0 {
+		library := filepath.FromSlash(slashed[:i])
+		compatData := filepath.Join(library, "steamapps", "compatdata", BtsSteamAppID)
+		if isDir(filepath.Join(compatData, "pfx")) {
+			install.WinePrefix = filepath.Join(compatData, "pfx")
+			install.Proton = findProton(compatData, library)
+		}
+	}
+	return install
+}
+
+// findProton returns Proton the prefix was created with, or the latest Proton of Steam libraries if it's unknown
+func findProton(compatData string, library string) string {
+	// config_info lists files of Proton used for the prefix, e.g. ".../common/Proton 8.0/files/share/fonts/"
+	if file, err := os.Open(filepath.Join(compatData, "config_info")); err == nil {
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := filepath.ToSlash(scanner.Text())
+			for _, dir := range []string{"/files/", "/dist/"} {
+				if i := strings.Index(line, dir); i >= 0 && isFile(filepath.Join(line[:i], "proton")) {
+					return filepath.FromSlash(line[:i])
+				}
+			}
+		}
+	}
+
+	var protons []string
+	for _, root := range append([]string{library}, getSteamRoots()...) {
+		scripts, _ := filepath.Glob(filepath.Join(root, "steamapps", "common", "Proton*", "proton"))
+		for _, script := range scripts {
+			protons = append(protons, filepath.Dir(script))
+		}
+	}
+	if len(protons) == 0 {
+		return ""
+	}
+	slices.Sort(protons)
+	return protons[len(protons)-1]
+}
+
+// ToWinePath translates the path to Windows path the game sees in the prefix: files of drive_c and other drives of
+// the prefix get their letters, others are accessed through Z: drive mapped to the root. Empty prefix means the default one
+func ToWinePath(prefix string, path string) string {
+	if prefix == "" {
+		prefix = getDefaultWinePrefix()
+	}
+
+	// Paths are compared with links resolved, e.g. ~/.steam/steam is usually a link to Steam directory
+	path = resolveLinks(path)
+	drives := map[string]string{"C:": resolveLinks(filepath.Join(prefix, "drive_c")), "Z:": "/"}
+	devices, _ := os.ReadDir(filepath.Join(prefix, "dosdevices"))
+	for _, device := range devices {
+		name := device.Name()
+		if len(name) != 2 || name[1] != ':' {
+			continue
+		}
+		if target, err := filepath.EvalSymlinks(filepath.Join(prefix, "dosdevices", name)); err == nil {
+			drives[strings.ToUpper(name)] = target
+		}
+	}
+
+	// The longest matching drive root is the closest one, e.g. C: is preferred over Z: for files of drive_c
+	letter, rel := "Z:", strings.TrimPrefix(path, "/")
+	longest := 0
+	for _, driveLetter := range SortKeys(drives) {
+		root := drives[driveLetter]
+		driveRel, err := filepath.Rel(root, path)
+		if err != nil || driveRel == ".." || strings.HasPrefix(driveRel, "../") || len(root) <= longest {
+			continue
+		}
+		letter, rel, longest = driveLetter, driveRel, len(root)
+	}
+	if rel == "." {
+		rel = ""
+	}
+	return letter + "\\" + strings.ReplaceAll(rel, "/", "\\")
+}
+
+// FromWinePath translates Windows path of the prefix to the path on this system, see ToWinePath.
+// Paths of unknown drives are returned as is
+func FromWinePath(prefix string, path string) string {
+	if len(path) < 2 || path[1] != ':' {
+		return path
+	}
+
+	letter := strings.ToLower(path[:2])
+	root := resolveLinks(filepath.Join(prefix, "dosdevices", letter))
+	if !isDir(root) {
+		switch letter {
+		case "c:":
+			root = filepath.Join(prefix, "drive_c")
+		case "z:":
+			root = "/"
+		default:
+			return path
+		}
+	}
+	return filepath.Join(root, strings.ReplaceAll(strings.TrimLeft(path[2:], "\\"), "\\", "/"))
+}
+
+// resolveLinks returns the path with symbolic links resolved, or the path as is if it doesn't exist
+func resolveLinks(path string) string {
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		return real
+	}
+	return path
+}
+
+// getDefaultWinePrefix returns the prefix used by Wine if WINEPREFIX is not set
+func getDefaultWinePrefix() string {
+	if prefix := os.Getenv("WINEPREFIX"); prefix != "" {
+		return prefix
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".wine")
+}
+
+// getWinePrefixes returns Wine prefixes of the user: the default one, ones made by winetricks and Lutris
+func getWinePrefixes() []string {
+	prefixes := []string{getDefaultWinePrefix()}
+	if home, err := os.UserHomeDir(); err == nil {
+		for _, pattern := range []string{filepath.Join(home, ".local", "share", "wineprefixes", "*"), filepath.Join(home, "Games", "*")} {
+			dirs, _ := filepath.Glob(pattern)
+			prefixes = append(prefixes, dirs...)
+		}
+	}
+
+	return slices.DeleteFunc(slices.Compact(prefixes), func(prefix string) bool {
+		return !isDir(filepath.Join(prefix, "drive_c"))
+	})
+}
@@ -0,0 +1,134 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+// makeDirs creates directories inside root
+func makeDirs(t *testing.T, root string, dirs ...string) {
+	for _, dir := range dirs {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// useTestHome makes a temporary directory the home of the user without Wine prefix set
+func useTestHome(t *testing.T) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("Wine is not used on Windows")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("WINEPREFIX", "")
+	return home
+}
+
+func TestToWinePath(t *testing.T) {
+	home := useTestHome(t)
+	prefix := filepath.Join(home, "prefix")
+	makeDirs(t, prefix, "drive_c/maps", "dosdevices")
+	makeDirs(t, home, "data")
+	for name, target := range map[string]string{"c:": "../drive_c", "d:": filepath.Join(home, "data"), "z:": "/"} {
+		if err := os.Symlink(target, filepath.Join(prefix, "dosdevices", name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := map[string]string{
+		filepath.Join(prefix, "drive_c", "maps", "a.CivBeyondSwordWBSave"): `C:\maps\a.CivBeyondSwordWBSave`,
+		filepath.Join(home, "data", "My Maps", "b.wbs"):                    `D:\My Maps\b.wbs`,
+		"/srv/maps/c.wbs": `Z:\srv\maps\c.wbs`,
+	}
+	for path, expected := range tests {
+		if winePath := ToWinePath(prefix, path); winePath != expected {
+			t.Errorf("ToWinePath(%s) = %s, expected %s", path, winePath, expected)
+		}
+	}
+
+	for winePath, expected := range map[string]string{
+		`C:\maps\a.wbs`:  filepath.Join(prefix, "drive_c", "maps", "a.wbs"),
+		`d:\My Maps`:     filepath.Join(home, "data", "My Maps"),
+		`Z:\srv\c.wbs`:   "/srv/c.wbs",
+		`X:\unknown`:     `X:\unknown`,
+		"/already/local": "/already/local",
+	} {
+		if path := FromWinePath(prefix, winePath); path != expected {
+			t.Errorf("FromWinePath(%s) = %s, expected %s", winePath, path, expected)
+		}
+	}
+
+	// Default prefix without dosdevices still has C: and Z: drives
+	makeDirs(t, home, ".wine/drive_c/Games")
+	if winePath := ToWinePath("", filepath.Join(home, ".wine", "drive_c", "Games")); winePath != `C:\Games` {
+		t.Errorf("Expected path in default prefix, got %s", winePath)
+	}
+}
+
+func TestDetectWine(t *testing.T) {
+	home := useTestHome(t)
+
+	gameDir := filepath.Join(home, "prefix", "drive_c", "Games", BtsDir)
+	if install := DetectWine(gameDir); install.WinePrefix != filepath.Join(home, "prefix") || install.Proton != "" {
+		t.Errorf("Unexpected Wine install %+v", install)
+	}
+	if install := DetectWine("/opt/civ4"); install.WinePrefix != "" {
+		t.Errorf("Unknown directory must not have prefix: %+v", install)
+	}
+
+	library := filepath.Join(home, "library")
+	steamGameDir := filepath.Join("steamapps", "common", "Sid Meier's Civilization IV Beyond the Sword", BtsDir)
+	compatData := filepath.Join(library, "steamapps", "compatdata", BtsSteamAppID)
+	proton := filepath.Join(library, "steamapps", "common", "Proton 8.0")
+	makeDirs(t, library, steamGameDir, "steamapps/common/Proton 9.0", "steamapps/common/Proton 8.0/files/share/fonts")
+	makeDirs(t, compatData, "pfx")
+	for _, dir := range []string{"Proton 8.0", "Proton 9.0"} {
+		if err := os.WriteFile(filepath.Join(library, "steamapps", "common", dir, "proton"), nil, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gameDir = filepath.Join(library, steamGameDir)
+	if install := DetectWine(gameDir); install.WinePrefix != filepath.Join(compatData, "pfx") || install.Proton != filepath.Join(library, "steamapps", "common", "Proton 9.0") {
+		t.Errorf("Expected the latest Proton without config_info, got %+v", install)
+	}
+
+	configInfo := "8.0-5\n" + filepath.Join(proton, "files", "share", "fonts") + "/\n"
+	if err := os.WriteFile(filepath.Join(compatData, "config_info"), []byte(configInfo), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if install := DetectWine(gameDir); install.Proton != proton {
+		t.Errorf("Expected Proton of config_info, got %+v", install)
+	}
+
+	config := &Config{GameDir: gameDir}
+	if profile, _ := config.GetLaunchProfile(""); profile.Proton != proton || profile.WinePrefix != filepath.Join(compatData, "pfx") {
+		t.Errorf("Default profile must use detected Proton: %+v", profile)
+	}
+}
+
+func TestProtonCommand(t *testing.T) {
+	useTestHome(t)
+	proton := "/steam/steamapps/common/Proton 8.0"
+	profile := LaunchProfile{Proton: proton, WinePrefix: "/steam/steamapps/compatdata/8800/pfx"}
+
+	cmd := profile.getCommand("linux", "/games/civ4", "", "/maps/a.wbs", LaunchWorldBuilder)
+	expectedArgs := []string{filepath.Join(proton, "proton"), "run", filepath.Join("/games/civ4", BtsExe), `/FXSLOAD=Z:\maps\a.wbs`, "/WB"}
+	if !slices.Equal(cmd.Args, expectedArgs) {
+		t.Errorf("Unexpected args %q", cmd.Args)
+	}
+	env := cmd.Env[len(cmd.Env)-2:]
+	if !slices.Equal(env, []string{"STEAM_COMPAT_DATA_PATH=/steam/steamapps/compatdata/8800", "STEAM_COMPAT_CLIENT_INSTALL_PATH=/steam"}) {
+		t.Errorf("Unexpected environment %q", env)
+	}
+
+	profile.Wrapper = []string{"gamemoderun", "wine"}
+	cmd = profile.getCommand("linux", "/games/civ4", "", "", LaunchNewGame)
+	if !slices.Equal(cmd.Args, []string{"gamemoderun", "wine", filepath.Join("/games/civ4", BtsExe)}) {
+		t.Errorf("Wrapper must replace Proton command: %q", cmd.Args)
+	}
+}
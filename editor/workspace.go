@@ -0,0 +1,149 @@
+package editor
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WorkspaceExt is an extension of workspace files
+const WorkspaceExt = ".civ4project"
+
+// Workspace is a project of a scenario: its maps, target mod and notes. It's saved as JSON,
+// map paths are relative to the workspace file (if possible), so the project can be moved with its maps
+type Workspace struct {
+	Name string `json:"name"`
+	// Mod is a mod the scenario is made for, empty value means the base game
+	Mod   string   `json:"mod"`
+	Maps  []string `json:"maps"`
+	Notes string   `json:"notes"`
+	// Path is the path of the workspace file, it's not saved
+	Path string `json:"-"`
+}
+
+// NewWorkspace returns an empty workspace for the file, named after it
+func NewWorkspace(path string) *Workspace {
+	return &Workspace{Name: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), Path: path}
+}
+
+// LoadWorkspace reads the workspace file
+func LoadWorkspace(path string) (*Workspace, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Workspace{}
+	if err = json.Unmarshal(data, w); err != nil {
+		return nil, err
+	}
+	w.Path = path
+	return w, nil
+}
+
+// IsWorkspaceFile returns true if the file is a workspace (by extension)
+func IsWorkspaceFile(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), WorkspaceExt)
+}
+
+// Save writes the workspace to its file
+func (w *Workspace) Save() error {
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(w.Path, data, 0644)
+}
+
+// GetMapPaths returns absolute paths of maps
+func (w *Workspace) GetMapPaths() []string {
+	paths := make([]string, 0, len(w.Maps))
+	for _, path := range w.Maps {
+		paths = append(paths, w.resolvePath(path))
+	}
+	return paths
+}
+
+// AddMap adds the map to the workspace if it's not added yet
+func (w *Workspace) AddMap(path string) {
+	if IsInSlice(w.GetMapPaths(), w.resolvePath(path)) {
+		return
+	}
+	w.Maps = append(w.Maps, w.relativePath(path))
+}
+
+// RemoveMap removes the map from the workspace, the file is not deleted
+func (w *Workspace) RemoveMap(path string) {
+	path = w.resolvePath(path)
+	for i, other := range w.Maps {
+		if w.resolvePath(other) == path {
+			w.Maps = append(w.Maps[:i], w.Maps[i+1:]...)
+			return
+		}
+	}
+}
+
+// resolvePath returns an absolute path of the workspace map
+func (w *Workspace) resolvePath(path string) string {
+	path = filepath.FromSlash(path)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(w.Path), path)
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return filepath.Clean(path)
+}
+
+// relativePath returns a path relative to the workspace file with forward slashes, or absolute path if it's on another drive
+func (w *Workspace) relativePath(path string) string {
+	path = w.resolvePath(path)
+	dir, err := filepath.Abs(filepath.Dir(w.Path))
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(dir, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// MapDocument is a map opened in the editor with its history of changes
+type MapDocument struct {
+	FilePath string
+	WbMap    *WbMap
+	History  *History
+}
+
+// OpenMapDocument parses the map file, progress is reported to the reporter of the context (see ParseWbMapContext)
+func OpenMapDocument(ctx context.Context, path string) (*MapDocument, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var size int64
+	if stat, err := file.Stat(); err == nil {
+		size = stat.Size()
+	}
+	wbMap, err := ParseWbMapContext(ctx, file, size)
+	if err != nil {
+		return nil, err
+	}
+	return &MapDocument{FilePath: path, WbMap: wbMap, History: NewHistory()}, nil
+}
+
+// GetTitle returns the file name, asterisk means unsaved changes
+func (d *MapDocument) GetTitle() string {
+	title := "Untitled"
+	if d.FilePath != "" {
+		title = filepath.Base(d.FilePath)
+	}
+	if d.History != nil && d.History.IsDirty() {
+		title += " *"
+	}
+	return title
+}
@@ -0,0 +1,60 @@
+package editor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWorkspace(filepath.Join(dir, "Scenario"+WorkspaceExt))
+	if w.Name != "Scenario" || !IsWorkspaceFile(w.Path) {
+		t.Errorf("Unexpected workspace %+v", w)
+	}
+
+	mapPath := filepath.Join(dir, "maps", "Europe.CivBeyondSwordWBSave")
+	w.Mod, w.Notes = "Rhye's and Fall", "1. Place cities\n2. Test"
+	w.AddMap(mapPath)
+	w.AddMap(filepath.Join(dir, "maps", "..", "maps", "Europe.CivBeyondSwordWBSave"))
+	if len(w.Maps) != 1 || w.Maps[0] != "maps/Europe.CivBeyondSwordWBSave" {
+		t.Fatalf("Map path must be relative and unique: %v", w.Maps)
+	}
+	if err := w.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadWorkspace(w.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Mod != w.Mod || loaded.Notes != w.Notes || len(loaded.GetMapPaths()) != 1 || loaded.GetMapPaths()[0] != mapPath {
+		t.Errorf("Unexpected loaded workspace %+v", loaded)
+	}
+
+	loaded.RemoveMap(mapPath)
+	if len(loaded.Maps) != 0 {
+		t.Errorf("Map is not removed: %v", loaded.Maps)
+	}
+}
+
+func TestOpenMapDocument(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.CivBeyondSwordWBSave")
+	if err := os.WriteFile(path, newTestSaveMap("Test").ToWbFormat(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := OpenMapDocument(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.WbMap.Game.Description != "Test" || doc.GetTitle() != "test.CivBeyondSwordWBSave" {
+		t.Errorf("Unexpected document %s", doc.GetTitle())
+	}
+
+	Set(doc.History, "Description", doc.WbMap.Game, &doc.WbMap.Game.Description)("Changed")
+	if doc.GetTitle() != "test.CivBeyondSwordWBSave *" {
+		t.Errorf("Changed document must be marked: %s", doc.GetTitle())
+	}
+}
@@ -2,10 +2,84 @@
 // This file is automatically generated. DO NOT EDIT
 import {editor} from '../models';
 
+export function AddPlayer():Promise<number>;
+
+export function CancelOperation(arg1:number):Promise<boolean>;
+
+export function FillSelection(arg1:editor.Selection,arg2:editor.Brush):Promise<Array<editor.Plot>>;
+
+export function FindGameInstalls():Promise<Array<editor.GameInstall>>;
+
+export function FloodFill(arg1:number,arg2:number,arg3:editor.Brush):Promise<Array<editor.Plot>>;
+
+export function GetBrushChoices(arg1:string):Promise<Array<editor.Choice>>;
+
+export function GetChoices(arg1:string,arg2:string):Promise<Array<editor.Choice>>;
+
 export function GetConfig():Promise<editor.Config>;
 
+export function GetGame():Promise<editor.Game>;
+
+export function GetGameStatus():Promise<editor.GameStatus>;
+
+export function GetLanguagesList():Promise<Array<string>>;
+
+export function GetLaunchProfiles():Promise<Array<editor.LaunchProfile>>;
+
+export function GetLoadReport():Promise<editor.XmlLoadReport>;
+
+export function GetLogEntries(arg1:string):Promise<Array<editor.LogEntry>>;
+
+export function GetMapInfo():Promise<editor.MapInfo>;
+
 export function GetModsList():Promise<Array<string>>;
 
+export function GetPlayers():Promise<Array<editor.Player>>;
+
+export function GetPlots(arg1:editor.Selection):Promise<Array<editor.Plot>>;
+
+export function GetProgress():Promise<Array<editor.Progress>>;
+
+export function GetRegistryNames():Promise<Array<string>>;
+
+export function GetTeams():Promise<Array<editor.Team>>;
+
+export function KillGame():Promise<void>;
+
+export function LaunchGame(arg1:string,arg2:string):Promise<void>;
+
+export function LintMod():Promise<editor.LintReport>;
+
+export function NewMap(arg1:number,arg2:number):Promise<editor.MapInfo>;
+
+export function OpenMap(arg1:string):Promise<editor.MapInfo>;
+
+export function Paint(arg1:number,arg2:number,arg3:editor.Brush):Promise<Array<editor.Plot>>;
+
+export function PatchGame(arg1:{[key: string]: any}):Promise<editor.Game>;
+
+export function PatchPlayer(arg1:number,arg2:{[key: string]: any}):Promise<editor.Player>;
+
+export function PatchPlot(arg1:number,arg2:number,arg3:{[key: string]: any}):Promise<editor.Plot>;
+
+export function PatchTeam(arg1:number,arg2:{[key: string]: any}):Promise<editor.Team>;
+
+export function Redo():Promise<editor.MapInfo>;
+
+export function RemovePlayer(arg1:number):Promise<void>;
+
+export function RestartGame():Promise<void>;
+
+export function SaveMap(arg1:string):Promise<editor.MapInfo>;
+
+export function SetCivilization(arg1:number,arg2:string):Promise<editor.Player>;
+
 export function SetConfig(arg1:editor.Config):Promise<void>;
 
+export function SetTeamRelation(arg1:string,arg2:number,arg3:number,arg4:boolean):Promise<Array<editor.Team>>;
+
+export function Undo():Promise<editor.MapInfo>;
+
+export function ValidateMap():Promise<Array<editor.MapIssue>>;
+
 export function WriteConsole(arg1:string):Promise<void>;
@@ -2,18 +2,166 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddPlayer() {
+  return window['go']['editor']['App']['AddPlayer']();
+}
+
+export function CancelOperation(arg1) {
+  return window['go']['editor']['App']['CancelOperation'](arg1);
+}
+
+export function FillSelection(arg1, arg2) {
+  return window['go']['editor']['App']['FillSelection'](arg1, arg2);
+}
+
+export function FindGameInstalls() {
+  return window['go']['editor']['App']['FindGameInstalls']();
+}
+
+export function FloodFill(arg1, arg2, arg3) {
+  return window['go']['editor']['App']['FloodFill'](arg1, arg2, arg3);
+}
+
+export function GetBrushChoices(arg1) {
+  return window['go']['editor']['App']['GetBrushChoices'](arg1);
+}
+
+export function GetChoices(arg1, arg2) {
+  return window['go']['editor']['App']['GetChoices'](arg1, arg2);
+}
+
 export function GetConfig() {
   return window['go']['editor']['App']['GetConfig']();
 }
 
+export function GetGame() {
+  return window['go']['editor']['App']['GetGame']();
+}
+
+export function GetGameStatus() {
+  return window['go']['editor']['App']['GetGameStatus']();
+}
+
+export function GetLanguagesList() {
+  return window['go']['editor']['App']['GetLanguagesList']();
+}
+
+export function GetLaunchProfiles() {
+  return window['go']['editor']['App']['GetLaunchProfiles']();
+}
+
+export function GetLoadReport() {
+  return window['go']['editor']['App']['GetLoadReport']();
+}
+
+export function GetLogEntries(arg1) {
+  return window['go']['editor']['App']['GetLogEntries'](arg1);
+}
+
+export function GetMapInfo() {
+  return window['go']['editor']['App']['GetMapInfo']();
+}
+
 export function GetModsList() {
   return window['go']['editor']['App']['GetModsList']();
 }
 
+export function GetPlayers() {
+  return window['go']['editor']['App']['GetPlayers']();
+}
+
+export function GetPlots(arg1) {
+  return window['go']['editor']['App']['GetPlots'](arg1);
+}
+
+export function GetProgress() {
+  return window['go']['editor']['App']['GetProgress']();
+}
+
+export function GetRegistryNames() {
+  return window['go']['editor']['App']['GetRegistryNames']();
+}
+
+export function GetTeams() {
+  return window['go']['editor']['App']['GetTeams']();
+}
+
+export function KillGame() {
+  return window['go']['editor']['App']['KillGame']();
+}
+
+export function LaunchGame(arg1, arg2) {
+  return window['go']['editor']['App']['LaunchGame'](arg1, arg2);
+}
+
+export function LintMod() {
+  return window['go']['editor']['App']['LintMod']();
+}
+
+export function NewMap(arg1, arg2) {
+  return window['go']['editor']['App']['NewMap'](arg1, arg2);
+}
+
+export function OpenMap(arg1) {
+  return window['go']['editor']['App']['OpenMap'](arg1);
+}
+
+export function Paint(arg1, arg2, arg3) {
+  return window['go']['editor']['App']['Paint'](arg1, arg2, arg3);
+}
+
+export function PatchGame(arg1) {
+  return window['go']['editor']['App']['PatchGame'](arg1);
+}
+
+export function PatchPlayer(arg1, arg2) {
+  return window['go']['editor']['App']['PatchPlayer'](arg1, arg2);
+}
+
+export function PatchPlot(arg1, arg2, arg3) {
+  return window['go']['editor']['App']['PatchPlot'](arg1, arg2, arg3);
+}
+
+export function PatchTeam(arg1, arg2) {
+  return window['go']['editor']['App']['PatchTeam'](arg1, arg2);
+}
+
+export function Redo() {
+  return window['go']['editor']['App']['Redo']();
+}
+
+export function RemovePlayer(arg1) {
+  return window['go']['editor']['App']['RemovePlayer'](arg1);
+}
+
+export function RestartGame() {
+  return window['go']['editor']['App']['RestartGame']();
+}
+
+export function SaveMap(arg1) {
+  return window['go']['editor']['App']['SaveMap'](arg1);
+}
+
+export function SetCivilization(arg1, arg2) {
+  return window['go']['editor']['App']['SetCivilization'](arg1, arg2);
+}
+
 export function SetConfig(arg1) {
   return window['go']['editor']['App']['SetConfig'](arg1);
 }
 
+export function SetTeamRelation(arg1, arg2, arg3, arg4) {
+  return window['go']['editor']['App']['SetTeamRelation'](arg1, arg2, arg3, arg4);
+}
+
+export function Undo() {
+  return window['go']['editor']['App']['Undo']();
+}
+
+export function ValidateMap() {
+  return window['go']['editor']['App']['ValidateMap']();
+}
+
 export function WriteConsole(arg1) {
   return window['go']['editor']['App']['WriteConsole'](arg1);
 }
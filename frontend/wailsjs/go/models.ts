@@ -1,9 +1,83 @@
 export namespace editor {
 	
+	export class Brush {
+	    Tool: string;
+	    Value: string;
+	    Variety: string;
+	    Size: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Brush(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Tool = source["Tool"];
+	        this.Value = source["Value"];
+	        this.Variety = source["Variety"];
+	        this.Size = source["Size"];
+	    }
+	}
+	export class Choice {
+	    type: string;
+	    description: string;
+	    help: string;
+	    icon: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Choice(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.description = source["description"];
+	        this.help = source["help"];
+	        this.icon = source["icon"];
+	    }
+	}
+	export class City {
+	    CityOwner: number;
+	    CityName: string;
+	    CityPopulation: number;
+	    ProductionUnit: string;
+	    ProductionBuilding: string;
+	    ProductionProject: string;
+	    ProductionProcess: string;
+	    BuildingType: string[];
+	    ReligionType: string[];
+	    HolyCityReligionType: string[];
+	    ScriptData: string;
+	    PlayerCulture: {[key: number]: number};
+	
+	    static createFrom(source: any = {}) {
+	        return new City(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.CityOwner = source["CityOwner"];
+	        this.CityName = source["CityName"];
+	        this.CityPopulation = source["CityPopulation"];
+	        this.ProductionUnit = source["ProductionUnit"];
+	        this.ProductionBuilding = source["ProductionBuilding"];
+	        this.ProductionProject = source["ProductionProject"];
+	        this.ProductionProcess = source["ProductionProcess"];
+	        this.BuildingType = source["BuildingType"];
+	        this.ReligionType = source["ReligionType"];
+	        this.HolyCityReligionType = source["HolyCityReligionType"];
+	        this.ScriptData = source["ScriptData"];
+	        this.PlayerCulture = source["PlayerCulture"];
+	    }
+	}
 	export class Config {
 	    game_dir: string;
 	    mod: string;
 	    auto_save: boolean;
+	    language: string;
+	    recent_files: string[];
+	    launch_profiles: LaunchProfile[];
+	    launch_profile: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new Config(source);
@@ -14,8 +88,598 @@ export namespace editor {
 	        this.game_dir = source["game_dir"];
 	        this.mod = source["mod"];
 	        this.auto_save = source["auto_save"];
+	        this.language = source["language"];
+	        this.recent_files = source["recent_files"];
+	        this.launch_profiles = this.convertValues(source["launch_profiles"], LaunchProfile);
+	        this.launch_profile = source["launch_profile"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Game {
+	    Era: string;
+	    Speed: string;
+	    Calendar: string;
+	    Victory: string[];
+	    GameTurn: number;
+	    MaxCityElimination: number;
+	    NumAdvancedStartPoints: number;
+	    TargetScore: number;
+	    StartYear: number;
+	    Description: string;
+	    ModPath: string;
+	    Tutorial: boolean;
+	    Option: string[];
+	    MPOption: string[];
+	    ForceControl: string[];
+	    MaxTurns: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Game(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Era = source["Era"];
+	        this.Speed = source["Speed"];
+	        this.Calendar = source["Calendar"];
+	        this.Victory = source["Victory"];
+	        this.GameTurn = source["GameTurn"];
+	        this.MaxCityElimination = source["MaxCityElimination"];
+	        this.NumAdvancedStartPoints = source["NumAdvancedStartPoints"];
+	        this.TargetScore = source["TargetScore"];
+	        this.StartYear = source["StartYear"];
+	        this.Description = source["Description"];
+	        this.ModPath = source["ModPath"];
+	        this.Tutorial = source["Tutorial"];
+	        this.Option = source["Option"];
+	        this.MPOption = source["MPOption"];
+	        this.ForceControl = source["ForceControl"];
+	        this.MaxTurns = source["MaxTurns"];
+	    }
+	}
+	export class GameInstall {
+	    dir: string;
+	    edition: string;
+	    version: string;
+	    source: string;
+	    wine_prefix: string;
+	    proton: string;
+	    supported: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new GameInstall(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.dir = source["dir"];
+	        this.edition = source["edition"];
+	        this.version = source["version"];
+	        this.source = source["source"];
+	        this.wine_prefix = source["wine_prefix"];
+	        this.proton = source["proton"];
+	        this.supported = source["supported"];
+	    }
+	}
+	export class GameStatus {
+	    running: boolean;
+	    profile: string;
+	    map_path: string;
+	    mode: string;
+	    pid: number;
+	    exit_code: number;
+	    killed: boolean;
+	    crashed: boolean;
+	    last_lines: string[];
+	    error: string;
+	    unsupervised: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new GameStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.running = source["running"];
+	        this.profile = source["profile"];
+	        this.map_path = source["map_path"];
+	        this.mode = source["mode"];
+	        this.pid = source["pid"];
+	        this.exit_code = source["exit_code"];
+	        this.killed = source["killed"];
+	        this.crashed = source["crashed"];
+	        this.last_lines = source["last_lines"];
+	        this.error = source["error"];
+	        this.unsupervised = source["unsupervised"];
+	    }
+	}
+	export class LaunchProfile {
+	    name: string;
+	    executable: string;
+	    mod: string;
+	    args: string[];
+	    world_builder_args: string[];
+	    work_dir: string;
+	    env: {[key: string]: string};
+	    wrapper: string[];
+	    wine_prefix: string;
+	    proton: string;
+	    logs_dir: string;
+	    elevated: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new LaunchProfile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.executable = source["executable"];
+	        this.mod = source["mod"];
+	        this.args = source["args"];
+	        this.world_builder_args = source["world_builder_args"];
+	        this.work_dir = source["work_dir"];
+	        this.env = source["env"];
+	        this.wrapper = source["wrapper"];
+	        this.wine_prefix = source["wine_prefix"];
+	        this.proton = source["proton"];
+	        this.logs_dir = source["logs_dir"];
+	        this.elevated = source["elevated"];
+	    }
+	}
+	export class LintIssue {
+	    path: string;
+	    line: number;
+	    kind: string;
+	    element: string;
+	    value: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LintIssue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.line = source["line"];
+	        this.kind = source["kind"];
+	        this.element = source["element"];
+	        this.value = source["value"];
+	    }
+	}
+	export class LintReport {
+	    files: number;
+	    issues: LintIssue[];
+	    errors: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new LintReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.files = source["files"];
+	        this.issues = this.convertValues(source["issues"], LintIssue);
+	        this.errors = source["errors"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LogEntry {
+	    // Go type: time
+	    time: any;
+	    level: string;
+	    message: string;
+	    fields: {[key: string]: string};
+	
+	    static createFrom(source: any = {}) {
+	        return new LogEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = this.convertValues(source["time"], null);
+	        this.level = source["level"];
+	        this.message = source["message"];
+	        this.fields = source["fields"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MapInfo {
+	    file_path: string;
+	    title: string;
+	    width: number;
+	    height: number;
+	    wrap_x: boolean;
+	    wrap_y: boolean;
+	    dirty: boolean;
+	    can_undo: boolean;
+	    can_redo: boolean;
+	    undo_name: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MapInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.file_path = source["file_path"];
+	        this.title = source["title"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.wrap_x = source["wrap_x"];
+	        this.wrap_y = source["wrap_y"];
+	        this.dirty = source["dirty"];
+	        this.can_undo = source["can_undo"];
+	        this.can_redo = source["can_redo"];
+	        this.undo_name = source["undo_name"];
+	    }
+	}
+	export class MapIssue {
+	    section: string;
+	    x: number;
+	    y: number;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MapIssue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.section = source["section"];
+	        this.x = source["x"];
+	        this.y = source["y"];
+	        this.message = source["message"];
+	    }
+	}
+	export class Player {
+	    CivDesc: string;
+	    CivShortDesc: string;
+	    LeaderName: string;
+	    CivAdjective: string;
+	    FlagDecal: string;
+	    WhiteFlag: boolean;
+	    LeaderType: string;
+	    CivType: string;
+	    Team: number;
+	    Handicap: string;
+	    Color: string;
+	    ArtStyle: string;
+	    PlayableCiv: boolean;
+	    MinorNationStatus: boolean;
+	    StartingGold: number;
+	    RandomStartLocation: boolean;
+	    StartingX: number;
+	    StartingY: number;
+	    StateReligion: string;
+	    StartingEra: string;
+	    CityList: string[];
+	    CivicOption: string[];
+	    Civic: string[];
+	    AttitudePlayer: number[];
+	    AttitudeExtra: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Player(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.CivDesc = source["CivDesc"];
+	        this.CivShortDesc = source["CivShortDesc"];
+	        this.LeaderName = source["LeaderName"];
+	        this.CivAdjective = source["CivAdjective"];
+	        this.FlagDecal = source["FlagDecal"];
+	        this.WhiteFlag = source["WhiteFlag"];
+	        this.LeaderType = source["LeaderType"];
+	        this.CivType = source["CivType"];
+	        this.Team = source["Team"];
+	        this.Handicap = source["Handicap"];
+	        this.Color = source["Color"];
+	        this.ArtStyle = source["ArtStyle"];
+	        this.PlayableCiv = source["PlayableCiv"];
+	        this.MinorNationStatus = source["MinorNationStatus"];
+	        this.StartingGold = source["StartingGold"];
+	        this.RandomStartLocation = source["RandomStartLocation"];
+	        this.StartingX = source["StartingX"];
+	        this.StartingY = source["StartingY"];
+	        this.StateReligion = source["StateReligion"];
+	        this.StartingEra = source["StartingEra"];
+	        this.CityList = source["CityList"];
+	        this.CivicOption = source["CivicOption"];
+	        this.Civic = source["Civic"];
+	        this.AttitudePlayer = source["AttitudePlayer"];
+	        this.AttitudeExtra = source["AttitudeExtra"];
+	    }
+	}
+	export class Plot {
+	    X: number;
+	    Y: number;
+	    Landmark: string;
+	    ScriptData: string;
+	    IsNOfRiver: boolean;
+	    IsWOfRiver: boolean;
+	    RiverNSDirection: number;
+	    RiverWEDirection: number;
+	    StartingPlot: boolean;
+	    BonusType: string;
+	    ImprovementType: string;
+	    FeatureType: string[];
+	    FeatureVariety: string[];
+	    RouteType: string;
+	    TerrainType: string;
+	    PlotType: number;
+	    Units: Unit[];
+	    Cities: City[];
+	    TeamReveal: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Plot(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.X = source["X"];
+	        this.Y = source["Y"];
+	        this.Landmark = source["Landmark"];
+	        this.ScriptData = source["ScriptData"];
+	        this.IsNOfRiver = source["IsNOfRiver"];
+	        this.IsWOfRiver = source["IsWOfRiver"];
+	        this.RiverNSDirection = source["RiverNSDirection"];
+	        this.RiverWEDirection = source["RiverWEDirection"];
+	        this.StartingPlot = source["StartingPlot"];
+	        this.BonusType = source["BonusType"];
+	        this.ImprovementType = source["ImprovementType"];
+	        this.FeatureType = source["FeatureType"];
+	        this.FeatureVariety = source["FeatureVariety"];
+	        this.RouteType = source["RouteType"];
+	        this.TerrainType = source["TerrainType"];
+	        this.PlotType = source["PlotType"];
+	        this.Units = this.convertValues(source["Units"], Unit);
+	        this.Cities = this.convertValues(source["Cities"], City);
+	        this.TeamReveal = source["TeamReveal"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Progress {
+	    id: number;
+	    operation: string;
+	    phase: string;
+	    detail: string;
+	    done: number;
+	    total: number;
+	    percent: number;
+	    finished: boolean;
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Progress(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.operation = source["operation"];
+	        this.phase = source["phase"];
+	        this.detail = source["detail"];
+	        this.done = source["done"];
+	        this.total = source["total"];
+	        this.percent = source["percent"];
+	        this.finished = source["finished"];
+	        this.error = source["error"];
+	    }
+	}
+	export class Selection {
+	    X1: number;
+	    Y1: number;
+	    X2: number;
+	    Y2: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Selection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.X1 = source["X1"];
+	        this.Y1 = source["Y1"];
+	        this.X2 = source["X2"];
+	        this.Y2 = source["Y2"];
+	    }
+	}
+	export class Team {
+	    TeamID: number;
+	    Tech: string[];
+	    ContactWithTeam: number[];
+	    AtWar: number[];
+	    PermanentWarPeace: number[];
+	    OpenBordersWithTeam: number[];
+	    DefensivePactWithTeam: number[];
+	    ProjectType: string[];
+	    RevealMap: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Team(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.TeamID = source["TeamID"];
+	        this.Tech = source["Tech"];
+	        this.ContactWithTeam = source["ContactWithTeam"];
+	        this.AtWar = source["AtWar"];
+	        this.PermanentWarPeace = source["PermanentWarPeace"];
+	        this.OpenBordersWithTeam = source["OpenBordersWithTeam"];
+	        this.DefensivePactWithTeam = source["DefensivePactWithTeam"];
+	        this.ProjectType = source["ProjectType"];
+	        this.RevealMap = source["RevealMap"];
+	    }
+	}
+	export class Unit {
+	    UnitType: string;
+	    UnitOwner: number;
+	    Level: number;
+	    Experience: number;
+	    PromotionType: string[];
+	    UnitAIType: string;
+	    Damage: number;
+	    FacingDirection: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Unit(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.UnitType = source["UnitType"];
+	        this.UnitOwner = source["UnitOwner"];
+	        this.Level = source["Level"];
+	        this.Experience = source["Experience"];
+	        this.PromotionType = source["PromotionType"];
+	        this.UnitAIType = source["UnitAIType"];
+	        this.Damage = source["Damage"];
+	        this.FacingDirection = source["FacingDirection"];
+	    }
+	}
+	export class XmlDuplicateType {
+	    type: string;
+	    files: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new XmlDuplicateType(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.files = source["files"];
+	    }
+	}
+	export class XmlFileReport {
+	    path: string;
+	    xml_type: string;
+	    schema: string;
+	    status: string;
+	    reason: string;
+	    count: number;
+	    warnings: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new XmlFileReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.xml_type = source["xml_type"];
+	        this.schema = source["schema"];
+	        this.status = source["status"];
+	        this.reason = source["reason"];
+	        this.count = source["count"];
+	        this.warnings = source["warnings"];
 	    }
 	}
+	export class XmlLoadReport {
+	    files: XmlFileReport[];
+	    duplicates: XmlDuplicateType[];
+	    errors: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new XmlLoadReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.files = this.convertValues(source["files"], XmlFileReport);
+	        this.duplicates = this.convertValues(source["duplicates"], XmlDuplicateType);
+	        this.errors = source["errors"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 
 }
-